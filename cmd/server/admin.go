@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	appPkg "voteweb/internal/app"
+	"voteweb/internal/domain"
+)
+
+const adminUsage = `usage: voteweb admin create-user --username NAME [--role superadmin]
+
+The password is read from ADMIN_PASSWORD, or from the first line of stdin.`
+
+// runAdmin handles the "admin" subcommand used to bootstrap admin accounts
+func runAdmin(ctx context.Context, app *appPkg.App, args []string) error {
+	if len(args) == 0 || args[0] != "create-user" {
+		return usageError(adminUsage)
+	}
+
+	flags := flag.NewFlagSet("admin create-user", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	username := flags.String("username", "", "login name of the new account")
+	role := flags.String("role", string(domain.AdminRoleSuperadmin), "viewer, operator or superadmin")
+	if err := flags.Parse(args[1:]); err != nil || *username == "" {
+		return usageError(adminUsage)
+	}
+
+	password, err := readAdminPassword()
+	if err != nil {
+		return err
+	}
+
+	ctx = domain.WithAuditActor(ctx, domain.AuditActor{Name: domain.AuditActorCLI})
+
+	user, err := app.Users.CreateUser(ctx, domain.AdminUserInput{
+		Username: *username,
+		Password: password,
+		Role:     domain.AdminRole(*role),
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Created %s account %q\n", user.Role, user.Username)
+	return nil
+}
+
+// readAdminPassword takes the password from ADMIN_PASSWORD or one line of stdin
+func readAdminPassword() (string, error) {
+	if password := os.Getenv("ADMIN_PASSWORD"); password != "" {
+		return password, nil
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("read password: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	appPkg "voteweb/internal/app"
+	"voteweb/internal/domain"
+	"voteweb/internal/util"
+)
+
+const exportUsage = `usage: voteweb export totals|rankings|votes [--event SLUG] [--format csv|xlsx] [--output FILE]
+
+Writes the report to stdout unless --output is given. The default event is
+used without --event.`
+
+// runExport writes one of the dashboard's spreadsheet reports
+func runExport(ctx context.Context, app *appPkg.App, args []string) error {
+	if len(args) == 0 {
+		return usageError(exportUsage)
+	}
+	report := args[0]
+	switch report {
+	case domain.ReportTotals, domain.ReportRankings, domain.ReportVotes:
+	default:
+		return usageError(exportUsage)
+	}
+
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	eventSlug := flags.String("event", "", "event slug")
+	format := flags.String("format", "csv", "csv or xlsx")
+	output := flags.String("output", "", "file to write instead of stdout")
+	if err := flags.Parse(args[1:]); err != nil || flags.NArg() > 0 {
+		return usageError(exportUsage)
+	}
+	if *format != "csv" && *format != "xlsx" {
+		return usageError(exportUsage)
+	}
+
+	event, err := app.Service.GetEvent(ctx, *eventSlug)
+	if err != nil {
+		return fmt.Errorf("load event: %w", err)
+	}
+
+	var out io.Writer = os.Stdout
+	var file *os.File
+	if *output != "" {
+		if file, err = os.Create(*output); err != nil {
+			return err
+		}
+		// Closed again below to report write errors; the second close is a no-op
+		defer file.Close()
+		out = file
+	}
+
+	var w util.TableWriter
+	if *format == "xlsx" {
+		if w, err = util.NewXLSXTableWriter(out, report); err != nil {
+			return fmt.Errorf("create workbook: %w", err)
+		}
+	} else {
+		w = util.NewCSVTableWriter(out)
+	}
+
+	ctx = domain.WithAuditActor(ctx, domain.AuditActor{Name: domain.AuditActorCLI})
+	app.Audit.Record(ctx, domain.AuditResultsExported, "event", event.ID, nil, map[string]any{
+		"report": report,
+		"format": *format,
+	})
+
+	if err := domain.WriteReport(ctx, app.Service, event.ID, report, w); err != nil {
+		return fmt.Errorf("export %s: %w", report, err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("finish export: %w", err)
+	}
+	if file != nil {
+		return file.Close()
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	appPkg "voteweb/internal/app"
+	"voteweb/internal/domain"
+)
+
+const importUsage = `usage: voteweb import [--event SLUG] [--format csv|json|yaml] [--dry-run] FILE
+
+Creates or updates innovations from FILE, matched by group_slug and slug.
+Columns: group_slug and name (required), slug (generated from the name when
+empty), division, entity_name, pic, description, logo_innovation_url,
+logo_entity_url, video_url, slide_url, ig_url, yt_url, hero_url and
+hero_mobile_url. Empty columns clear the stored value; columns missing from
+the file keep it. The format follows the file extension without --format;
+FILE "-" reads stdin. --dry-run prints the changes without applying them.`
+
+// runImport loads innovations from a CSV, JSON or YAML file
+func runImport(ctx context.Context, app *appPkg.App, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	eventSlug := flags.String("event", "", "event slug")
+	format := flags.String("format", "", "csv, json or yaml")
+	dryRun := flags.Bool("dry-run", false, "print the changes without applying them")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return usageError(importUsage)
+	}
+	path := flags.Arg(0)
+
+	if *format == "" {
+		var ok bool
+		if *format, ok = domain.ImportFormatFromPath(path); !ok {
+			return usageError(importUsage)
+		}
+	}
+	switch *format {
+	case domain.ImportFormatCSV, domain.ImportFormatJSON, domain.ImportFormatYAML:
+	default:
+		return usageError(importUsage)
+	}
+
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		in = file
+	}
+	rows, err := domain.ParseInnovationImport(in, *format)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", path, err)
+	}
+
+	event, err := app.Service.GetEvent(ctx, *eventSlug)
+	if err != nil {
+		return fmt.Errorf("load event: %w", err)
+	}
+
+	var plan *domain.ImportPlan
+	if *dryRun {
+		plan, err = app.Import.PlanImport(ctx, event.ID, rows)
+	} else {
+		ctx = domain.WithAuditActor(ctx, domain.AuditActor{Name: domain.AuditActorCLI})
+		plan, err = app.Import.ApplyImport(ctx, event.ID, rows)
+	}
+	if plan != nil {
+		printImportPlan(plan)
+	}
+	if err != nil {
+		return err
+	}
+	if len(plan.Problems) > 0 {
+		return errors.New("the file has problems; nothing would be imported")
+	}
+
+	verb := "Imported"
+	if *dryRun {
+		verb = "Dry run:"
+	}
+	fmt.Fprintf(os.Stderr, "%s %d created, %d updated, %d unchanged in event %s\n",
+		verb, len(plan.Created), len(plan.Updated), plan.Unchanged, event.Slug)
+	return nil
+}
+
+// printImportPlan writes the changes to stdout and the problems to stderr
+func printImportPlan(plan *domain.ImportPlan) {
+	for _, problem := range plan.Problems {
+		fmt.Fprintf(os.Stderr, "line %d: %s\n", problem.Line, problem.Message)
+	}
+	for _, change := range plan.Created {
+		innovation := change.Innovation
+		fmt.Printf("+ %s/%s %q (line %d)\n", innovation.GroupSlug, innovation.Slug, innovation.Name, change.Line)
+	}
+	for _, change := range plan.Updated {
+		innovation := change.Innovation
+		fmt.Printf("~ %s/%s (line %d)\n", innovation.GroupSlug, innovation.Slug, change.Line)
+		for _, field := range change.Fields {
+			fmt.Printf("    %s: %q -> %q\n", field.Field, field.Before, field.After)
+		}
+	}
+}
@@ -2,79 +2,108 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
-	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
-	"time"
 
 	appPkg "voteweb/internal/app"
-	httpPkg "voteweb/internal/http"
-	"voteweb/seed"
 )
 
-func main() {
-	ctx := context.Background()
+// Exit codes
+const (
+	exitOK = 0
+	// exitFailure means the command ran and failed
+	exitFailure = 1
+	// exitUsage means an unknown command or bad arguments
+	exitUsage = 2
+	// exitUnavailable means the configuration or the database is unusable
+	exitUnavailable = 3
+)
 
-	// Initialize application
-	app, err := appPkg.New(ctx)
-	if err != nil {
-		log.Fatalf("Failed to initialize app: %v", err)
-	}
-	defer app.Close()
+const usage = `usage: voteweb [COMMAND]
+
+Commands:
+  serve                      run the HTTP server (the default)
+  migrate up|down|status|... apply or revert schema migrations
+  seed                       load the built-in groups and innovations
+  import FILE                create or update innovations from CSV, JSON or YAML
+  export REPORT              write totals, rankings or votes as CSV or XLSX
+  voting open|close          resume or pause voting for an event
+  admin create-user          create an admin account
+
+Exit codes: 0 success, 1 command failed, 2 usage error, 3 configuration or
+database unavailable.`
+
+// commands maps subcommand names to their handlers. Each gets the app wired
+// by app.New; only serve starts the HTTP listener.
+var commands = map[string]func(ctx context.Context, app *appPkg.App, args []string) error{
+	"serve":   runServe,
+	"migrate": runMigrate,
+	"seed":    runSeed,
+	"import":  runImport,
+	"export":  runExport,
+	"voting":  runVoting,
+	"admin":   runAdmin,
+}
 
-	app.Logger.Info("Application initialized successfully")
+// errDemoMode is returned by commands that need PostgreSQL
+var errDemoMode = errors.New("not available in DEMO_MODE, which keeps data in memory and seeds it at startup")
 
-	// Run seeds if SEED environment variable is set
-	if os.Getenv("SEED") == "true" {
-		app.Logger.Info("Running seed data...")
-		if err := seed.SeedInnovations(ctx, app.Pool); err != nil {
-			log.Fatalf("Failed to seed innovations: %v", err)
-		}
-		app.Logger.Info("Seed data completed successfully")
-	}
+// usageError is returned by commands for bad arguments; its text is the usage
+type usageError string
 
-	// Setup router
-	router := httpPkg.SetupRouter(app.Config, app.Pool, app.Service, app.Logger)
-
-	// Create HTTP server
-	addr := fmt.Sprintf(":%s", app.Config.Port)
-	srv := &http.Server{
-		Addr:         addr,
-		Handler:      router,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
-	}
+func (e usageError) Error() string {
+	return string(e)
+}
 
-	// Start server in a goroutine
-	go func() {
-		app.Logger.Info("Starting HTTP server", "addr", addr)
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Failed to start server: %v", err)
-		}
-	}()
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:]))
+}
 
-	app.Logger.Info("Server started successfully", "port", app.Config.Port)
+// run dispatches to a subcommand and returns the process exit code
+func run(ctx context.Context, args []string) int {
+	name := "serve"
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
 
-	// Wait for interrupt signal to gracefully shutdown the server
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	switch name {
+	case "help", "-h", "--help":
+		fmt.Println(usage)
+		return exitOK
+	}
 
-	app.Logger.Info("Shutting down server...")
+	command, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s\n", name, usage)
+		return exitUsage
+	}
 
-	// Graceful shutdown with timeout
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	// migrate manages the schema itself: AUTO_MIGRATE would apply pending
+	// migrations before down reverts one, and fail before baseline can record
+	// a database migrated by hand
+	var opts []appPkg.Option
+	if name == "migrate" {
+		opts = append(opts, appPkg.WithoutAutoMigrate())
+	}
 
-	if err := srv.Shutdown(shutdownCtx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+	// Initialize application
+	app, err := appPkg.New(ctx, opts...)
+	if err != nil {
+		log.Printf("Failed to initialize app: %v", err)
+		return exitUnavailable
 	}
+	defer app.Close()
 
-	app.Logger.Info("Server exited gracefully")
+	if err := command(ctx, app, args); err != nil {
+		var usageErr usageError
+		if errors.As(err, &usageErr) {
+			fmt.Fprintln(os.Stderr, usageErr)
+			return exitUsage
+		}
+		log.Printf("%s: %v", name, err)
+		return exitFailure
+	}
+	return exitOK
 }
-
-
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	appPkg "voteweb/internal/app"
+	"voteweb/internal/migrate"
+	"voteweb/migrations"
+)
+
+const migrateUsage = `usage: voteweb migrate COMMAND
+
+Commands:
+  up                  apply every pending migration
+  down [N]            revert the last N applied migrations (default 1)
+  status              list migrations and when they were applied
+  to-version VERSION  apply or revert until VERSION is the last applied
+  baseline VERSION    record migrations up to VERSION as applied without
+                      running them, for databases migrated by hand; use
+                      the last migration really applied (2 for the old
+                      psql deploy), as any later one is skipped
+
+Every migration has a down file except 0001, the initial schema, and those
+that rewrite data: 0002, 0004, 0005 and 0012. down and to-version therefore
+stop at 0012; restore a backup to go further back.`
+
+// runMigrate handles the "migrate" subcommand
+func runMigrate(ctx context.Context, app *appPkg.App, args []string) error {
+	if len(args) == 0 {
+		return usageError(migrateUsage)
+	}
+	if app.Pool == nil {
+		return errDemoMode
+	}
+
+	migrator, err := migrate.New(app.Pool, migrations.Files, app.Logger)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		ran, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Applied %d migration(s)\n", ran)
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps <= 0 {
+				return usageError(migrateUsage)
+			}
+		}
+		ran, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Reverted %d migration(s)\n", ran)
+
+	case "to-version":
+		version, err := versionArg(args)
+		if err != nil {
+			return err
+		}
+		ran, err := migrator.To(ctx, version)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Ran %d migration(s); now at version %d\n", ran, version)
+
+	case "baseline":
+		version, err := versionArg(args)
+		if err != nil {
+			return err
+		}
+		recorded, err := migrator.Baseline(ctx, version)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Recorded %d migration(s) as applied\n", recorded)
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+		for _, status := range statuses {
+			name, applied := status.Name, "pending"
+			if name == "" {
+				name = "(no file in this binary)"
+			}
+			if status.AppliedAt != nil {
+				applied = status.AppliedAt.Local().Format(time.DateTime)
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", status.Version, name, applied)
+		}
+		return w.Flush()
+
+	default:
+		return usageError(migrateUsage)
+	}
+
+	return nil
+}
+
+// versionArg reads the VERSION argument of to-version and baseline
+func versionArg(args []string) (int, error) {
+	if len(args) != 2 {
+		return 0, usageError(migrateUsage)
+	}
+	version, err := strconv.Atoi(args[1])
+	if err != nil || version < 0 {
+		return 0, usageError(migrateUsage)
+	}
+	return version, nil
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	appPkg "voteweb/internal/app"
+	"voteweb/seed"
+)
+
+// runSeed loads the built-in groups and innovations into the default event
+func runSeed(ctx context.Context, app *appPkg.App, args []string) error {
+	if len(args) > 0 {
+		return usageError("usage: voteweb seed")
+	}
+	return seedInnovations(ctx, app)
+}
+
+func seedInnovations(ctx context.Context, app *appPkg.App) error {
+	if app.Pool == nil {
+		return errDemoMode
+	}
+	app.Logger.Info("Running seed data...")
+	if err := seed.SeedInnovations(ctx, app.Pool); err != nil {
+		return fmt.Errorf("failed to seed innovations: %w", err)
+	}
+	app.Logger.Info("Seed data completed successfully")
+	return nil
+}
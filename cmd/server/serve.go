@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	appPkg "voteweb/internal/app"
+	httpPkg "voteweb/internal/http"
+)
+
+// runServe runs the HTTP server and the fraud analyser until SIGINT or SIGTERM
+func runServe(ctx context.Context, app *appPkg.App, args []string) error {
+	if len(args) > 0 {
+		return usageError("usage: voteweb serve")
+	}
+
+	app.Logger.Info("Application initialized successfully")
+
+	// SEED=true still seeds before serving; docker-compose relies on it.
+	// DEMO_MODE has already seeded its in-memory store.
+	if os.Getenv("SEED") == "true" && !app.Config.DemoMode {
+		if err := seedInnovations(ctx, app); err != nil {
+			return err
+		}
+	}
+
+	// Warn about innovations that reference missing static files
+	if err := app.CheckInnovationAssets(ctx, "web/static"); err != nil {
+		app.Logger.Warn("Innovation asset check failed", "error", err)
+	}
+
+	// Setup router
+	router := httpPkg.SetupRouter(app)
+
+	// Create HTTP server
+	addr := fmt.Sprintf(":%s", app.Config.Port)
+	srv := &http.Server{
+		Addr:         addr,
+		Handler:      router,
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	// End live result streams so Shutdown does not wait on them
+	srv.RegisterOnShutdown(app.Live.Close)
+
+	// Flag suspicious votes in the background until shutdown
+	analyserCtx, stopAnalyser := context.WithCancel(ctx)
+	defer stopAnalyser()
+	go app.Fraud.Run(analyserCtx)
+
+	// Start server in a goroutine
+	serveErr := make(chan error, 1)
+	go func() {
+		app.Logger.Info("Starting HTTP server", "addr", addr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			serveErr <- err
+		}
+	}()
+
+	app.Logger.Info("Server started successfully", "port", app.Config.Port)
+
+	// Wait for interrupt signal to gracefully shutdown the server
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("failed to start server: %w", err)
+	case <-quit:
+	}
+
+	app.Logger.Info("Shutting down server...")
+	stopAnalyser()
+
+	// Graceful shutdown with timeout
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("server forced to shutdown: %w", err)
+	}
+
+	app.Logger.Info("Server exited gracefully")
+	return nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	appPkg "voteweb/internal/app"
+	"voteweb/internal/domain"
+)
+
+const votingUsage = `usage: voteweb voting open|close [--event SLUG]
+
+open resumes a paused event (the opens_at/closes_at window still applies);
+close pauses voting until it is opened again. The default event is used
+without --event.`
+
+// runVoting pauses or resumes voting like the dashboard's voting button
+func runVoting(ctx context.Context, app *appPkg.App, args []string) error {
+	if len(args) == 0 || (args[0] != "open" && args[0] != "close") {
+		return usageError(votingUsage)
+	}
+	paused := args[0] == "close"
+
+	flags := flag.NewFlagSet("voting", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	eventSlug := flags.String("event", "", "event slug")
+	if err := flags.Parse(args[1:]); err != nil || flags.NArg() > 0 {
+		return usageError(votingUsage)
+	}
+
+	event, err := app.Service.GetEvent(ctx, *eventSlug)
+	if err != nil {
+		return fmt.Errorf("load event: %w", err)
+	}
+
+	ctx = domain.WithAuditActor(ctx, domain.AuditActor{Name: domain.AuditActorCLI})
+	event.Window.Paused = paused
+	if err := app.Service.UpdateVotingWindow(ctx, event.ID, &event.Window); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Voting for %q is now %s\n", event.Slug, event.Window.Status(time.Now()))
+	return nil
+}
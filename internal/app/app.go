@@ -8,22 +8,62 @@ import (
 
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"voteweb/internal/captcha"
 	"voteweb/internal/config"
 	"voteweb/internal/domain"
+	"voteweb/internal/migrate"
+	"voteweb/internal/notify"
 	"voteweb/internal/repo"
 	"voteweb/internal/util"
+	"voteweb/migrations"
 )
 
 // App represents the application
 type App struct {
-	Config  *config.Config
-	Pool    *pgxpool.Pool
-	Service domain.VoteService
-	Logger  *slog.Logger
+	Config *config.Config
+	// Pool is nil in DEMO_MODE, where every repository is in memory
+	Pool        *pgxpool.Pool
+	Service     domain.VoteService
+	Innovations domain.InnovationService
+	Auth        domain.AdminAuthService
+	Users       domain.AdminUserService
+	Audit       domain.AuditService
+	Live        *domain.VoteBroadcaster
+	// Verification confirms voter contacts for events that use one-time codes
+	Verification domain.VerificationService
+	// VoterTokens signs the anonymous voter-token cookies
+	VoterTokens *util.TokenSigner
+	// Challenges asks suspicious voters to solve a puzzle first
+	Challenges domain.ChallengeService
+	// Fraud flags suspicious vote clusters for review
+	Fraud domain.FraudService
+	// Import loads innovations in bulk from CSV, JSON or YAML files
+	Import domain.ImportService
+	Logger *slog.Logger
+}
+
+// Option changes how New sets up the App
+type Option func(*options)
+
+type options struct {
+	skipAutoMigrate bool
+}
+
+// WithoutAutoMigrate leaves the schema alone even when AUTO_MIGRATE is set,
+// for commands that manage migrations themselves
+func WithoutAutoMigrate() Option {
+	return func(o *options) {
+		o.skipAutoMigrate = true
+	}
 }
 
 // New creates and initializes a new App
-func New(ctx context.Context) (*App, error) {
+func New(ctx context.Context, opts ...Option) (*App, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
@@ -33,6 +73,117 @@ func New(ctx context.Context) (*App, error) {
 	// Initialize logger
 	logger := initLogger()
 
+	// Open the data store: PostgreSQL, or process memory in demo mode
+	var pool *pgxpool.Pool
+	var memory *repo.MemoryRepository
+	var stores repositories
+	if cfg.DemoMode {
+		memory = repo.NewMemoryRepository()
+		stores = memoryRepositories(memory)
+		logger.Warn("DEMO_MODE is on: data is kept in memory and lost on restart")
+		if cfg.IPHashSaltGenerated {
+			logger.Warn("IP_HASH_SALT is not set: using a random salt, so sessions and voter tokens end on restart")
+		}
+	} else {
+		if pool, err = openDatabase(ctx, cfg, cfg.AutoMigrate && !o.skipAutoMigrate, logger); err != nil {
+			return nil, err
+		}
+		stores = postgresRepositories(pool)
+	}
+
+	// Initialize IP hasher
+	ipHasher := util.NewIPHasherWithIdentity(cfg.IPHashSalt, util.IPIdentity{
+		IPv4Prefix: cfg.VoterIPv4Prefix,
+		IPv6Prefix: cfg.VoterIPv6Prefix,
+	})
+
+	// Initialize audit log; every service below writes to it
+	audit := domain.NewAuditService(stores.audit, ipHasher, logger)
+
+	// Initialize live updates; recorded votes are pushed to dashboard streams
+	live := domain.NewVoteBroadcaster(domain.DefaultLiveBuffer, logger)
+
+	// Initialize service
+	service := domain.NewVoteService(stores.votes, ipHasher, audit, live, domain.VoterDedupe{
+		Mode:     cfg.VoterDedupe,
+		MaxPerIP: cfg.VoterMaxPerIP,
+	}, logger)
+	innovations := domain.NewInnovationService(stores.votes, audit, logger)
+
+	// Initialize admin accounts and sessions
+	signer := util.NewTokenSigner(cfg.SessionSecret)
+	passwords := util.NewPasswordHasher(0)
+	auth := domain.NewAdminAuthService(stores.adminUsers, stores.sessions, signer, passwords, audit, cfg.SessionTTL, logger)
+	users := domain.NewAdminUserService(stores.adminUsers, stores.sessions, passwords, audit, logger)
+
+	// Initialize voter verification; its cookies are signed with a key
+	// derived from the session secret, so they never verify as admin sessions
+	verification := domain.NewVerificationService(
+		stores.verification,
+		newCodeSender(cfg, logger),
+		ipHasher,
+		signer.Derive("voter-verification"),
+		domain.VerificationSettings{
+			Channels:    cfg.OTP.Channels,
+			CodeTTL:     cfg.OTP.CodeTTL,
+			MaxAttempts: cfg.OTP.MaxAttempts,
+		},
+		logger)
+
+	// Initialize vote challenges; proof-of-work tokens are signed with keys
+	// derived from the voter-token keys, so they never verify as voter tokens
+	voterTokens := util.NewTokenSigner(cfg.VoterTokenKeys[0], cfg.VoterTokenKeys[1:]...)
+	challenges := domain.NewChallengeService(
+		stores.challenges,
+		ipHasher,
+		newChallengeVerifier(cfg, stores.challenges, voterTokens.Derive("proof-of-work")),
+		domain.ChallengeSettings{
+			Threshold: cfg.Challenge.Threshold,
+			Window:    cfg.Challenge.Window,
+		},
+		logger)
+
+	// Initialize the fraud analyser; main runs it in the background
+	fraud := domain.NewFraudService(stores.fraud, audit, domain.FraudSettings{
+		Interval:     cfg.Fraud.Interval,
+		BurstWindow:  cfg.Fraud.BurstWindow,
+		BurstSize:    cfg.Fraud.BurstSize,
+		SubnetWindow: cfg.Fraud.SubnetWindow,
+		SubnetSize:   cfg.Fraud.SubnetSize,
+	}, logger)
+	importer := domain.NewImportService(stores.imports, audit, logger)
+
+	if cfg.DemoMode {
+		if err := seedDemo(ctx, memory, users, cfg.DemoAdminPassword, logger); err != nil {
+			return nil, err
+		}
+	}
+
+	if os.Getenv("ADMIN_CODE") != "" {
+		logger.Warn("ADMIN_CODE is no longer used; create admin accounts with 'server admin create-user'")
+	}
+
+	return &App{
+		Config:       cfg,
+		Pool:         pool,
+		Service:      service,
+		Innovations:  innovations,
+		Auth:         auth,
+		Users:        users,
+		Audit:        audit,
+		Live:         live,
+		Verification: verification,
+		VoterTokens:  voterTokens,
+		Challenges:   challenges,
+		Fraud:        fraud,
+		Import:       importer,
+		Logger:       logger,
+	}, nil
+}
+
+// openDatabase connects to PostgreSQL and applies pending migrations when
+// autoMigrate is set
+func openDatabase(ctx context.Context, cfg *config.Config, autoMigrate bool, logger *slog.Logger) (*pgxpool.Pool, error) {
 	// Connect to database
 	pool, err := pgxpool.New(ctx, cfg.DatabaseURL)
 	if err != nil {
@@ -47,25 +198,64 @@ func New(ctx context.Context) (*App, error) {
 
 	logger.Info("Connected to database")
 
-	// Initialize repository
-	repository := repo.NewPostgresRepository(pool)
+	// Apply pending migrations; replicas starting together wait on one lock
+	if autoMigrate {
+		migrator, err := migrate.New(pool, migrations.Files, logger)
+		if err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("failed to load migrations: %w", err)
+		}
+		if _, err := migrator.Up(ctx); err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("failed to migrate database: %w", err)
+		}
+	}
 
-	// Initialize IP hasher
-	ipHasher := util.NewIPHasher(cfg.IPHashSalt)
+	return pool, nil
+}
 
-	// Initialize service
-	service := domain.NewVoteService(repository, ipHasher, logger)
+// newCodeSender returns the configured one-time code sender
+func newCodeSender(cfg *config.Config, logger *slog.Logger) domain.CodeSender {
+	switch {
+	case cfg.OTP.Sender == "smtp":
+		return notify.NewSMTPSender(notify.SMTPConfig{
+			Host:     cfg.OTP.SMTP.Host,
+			Port:     cfg.OTP.SMTP.Port,
+			Username: cfg.OTP.SMTP.Username,
+			Password: cfg.OTP.SMTP.Password,
+			From:     cfg.OTP.SMTP.From,
+		})
+	case cfg.OTP.LogFile != "":
+		return notify.NewFileSender(cfg.OTP.LogFile)
+	default:
+		return notify.NewLogSender(logger)
+	}
+}
 
-	return &App{
-		Config:  cfg,
-		Pool:    pool,
-		Service: service,
-		Logger:  logger,
-	}, nil
+// newChallengeVerifier returns the configured challenge verifier, or nil when
+// challenges are off
+func newChallengeVerifier(cfg *config.Config, repo domain.ChallengeRepository, signer *util.TokenSigner) domain.ChallengeVerifier {
+	switch cfg.Challenge.Provider {
+	case "pow":
+		return domain.NewProofOfWork(repo, signer, cfg.Challenge.PoWDifficulty, 0)
+	case "captcha":
+		return captcha.NewSiteVerifier(captcha.Config{
+			VerifyURL: cfg.Challenge.Captcha.VerifyURL,
+			SiteKey:   cfg.Challenge.Captcha.SiteKey,
+			Secret:    cfg.Challenge.Captcha.Secret,
+		})
+	case "stub":
+		return captcha.NewStub()
+	default:
+		return nil
+	}
 }
 
 // Close closes the application resources
 func (a *App) Close() {
+	if a.Live != nil {
+		a.Live.Close()
+	}
 	if a.Pool != nil {
 		a.Pool.Close()
 		a.Logger.Info("Database connection closed")
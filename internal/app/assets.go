@@ -0,0 +1,80 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"voteweb/internal/domain"
+)
+
+// CheckInnovationAssets logs a warning for every innovation that references a
+// /static/ file missing from staticDir. It only reports; startup continues.
+func (a *App) CheckInnovationAssets(ctx context.Context, staticDir string) error {
+	events, err := a.Service.ListEvents(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list events: %w", err)
+	}
+
+	var missing int
+	for _, event := range events {
+		innovations, err := a.Service.ListInnovations(ctx, event.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list innovations of event %s: %w", event.Slug, err)
+		}
+
+		for _, innovation := range innovations {
+			for field, url := range innovationAssets(innovation) {
+				path, ok := staticPath(staticDir, url)
+				if !ok {
+					continue
+				}
+				if _, err := os.Stat(path); err != nil {
+					missing++
+					a.Logger.Warn("innovation asset missing",
+						"event", event.Slug,
+						"group_slug", innovation.GroupSlug,
+						"slug", innovation.Slug,
+						"field", field,
+						"url", url)
+				}
+			}
+		}
+	}
+
+	if missing > 0 {
+		a.Logger.Warn("innovation asset check found missing files", "missing", missing)
+	} else {
+		a.Logger.Info("innovation asset check passed")
+	}
+
+	return nil
+}
+
+// innovationAssets returns the asset URLs referenced by an innovation keyed by field name
+func innovationAssets(innovation *domain.Innovation) map[string]string {
+	assets := make(map[string]string)
+	for field, url := range map[string]*string{
+		"hero_url":            innovation.HeroURL,
+		"hero_mobile_url":     innovation.HeroMobileURL,
+		"logo_innovation_url": innovation.LogoInnovationURL,
+		"logo_entity_url":     innovation.LogoEntityURL,
+	} {
+		if url != nil && *url != "" {
+			assets[field] = *url
+		}
+	}
+	return assets
+}
+
+// staticPath maps a /static/ URL to a file under staticDir. External URLs
+// are not checked.
+func staticPath(staticDir, url string) (string, bool) {
+	rel, ok := strings.CutPrefix(url, "/static/")
+	if !ok {
+		return "", false
+	}
+	return filepath.Join(staticDir, filepath.FromSlash(rel)), true
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+
+	"voteweb/internal/domain"
+	"voteweb/internal/repo"
+	"voteweb/seed"
+)
+
+// demoAdminUsername is the superadmin account created in DEMO_MODE
+const demoAdminUsername = "demo"
+
+// seedDemo fills the in-memory store with the seed innovations, opens voting
+// and creates the demo superadmin. Without a configured password one is
+// generated and logged.
+func seedDemo(ctx context.Context, store *repo.MemoryRepository, users domain.AdminUserService, password string, logger *slog.Logger) error {
+	if err := seed.SeedMemory(ctx, store); err != nil {
+		return fmt.Errorf("failed to seed demo data: %w", err)
+	}
+
+	generated := password == ""
+	if generated {
+		b := make([]byte, 12)
+		if _, err := rand.Read(b); err != nil {
+			return fmt.Errorf("failed to generate demo password: %w", err)
+		}
+		password = base64.RawURLEncoding.EncodeToString(b)
+	}
+
+	ctx = domain.WithAuditActor(ctx, domain.AuditActor{Name: domain.AuditActorSeed})
+	_, err := users.CreateUser(ctx, domain.AdminUserInput{
+		Username: demoAdminUsername,
+		Password: password,
+		Role:     domain.AdminRoleSuperadmin,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create demo admin: %w", err)
+	}
+
+	if generated {
+		logger.Warn("Demo admin account created", "username", demoAdminUsername, "password", password)
+	} else {
+		logger.Info("Demo admin account created", "username", demoAdminUsername)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package app
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"voteweb/internal/domain"
+	"voteweb/internal/repo"
+)
+
+// repositories holds the data access of every service
+type repositories struct {
+	votes        domain.Repository
+	audit        domain.AuditRepository
+	adminUsers   domain.AdminUserRepository
+	sessions     domain.SessionRepository
+	verification domain.VerificationRepository
+	challenges   domain.ChallengeRepository
+	fraud        domain.FraudRepository
+	imports      domain.ImportRepository
+}
+
+func postgresRepositories(pool *pgxpool.Pool) repositories {
+	return repositories{
+		votes:        repo.NewPostgresRepository(pool),
+		audit:        repo.NewPostgresAuditRepository(pool),
+		adminUsers:   repo.NewPostgresAdminUserRepository(pool),
+		sessions:     repo.NewPostgresSessionRepository(pool),
+		verification: repo.NewPostgresVerificationRepository(pool),
+		challenges:   repo.NewPostgresChallengeRepository(pool),
+		fraud:        repo.NewPostgresFraudRepository(pool),
+		imports:      repo.NewPostgresImportRepository(pool),
+	}
+}
+
+// memoryRepositories backs every service with the same in-memory store
+func memoryRepositories(store *repo.MemoryRepository) repositories {
+	return repositories{
+		votes:        store,
+		audit:        store,
+		adminUsers:   store,
+		sessions:     store,
+		verification: store,
+		challenges:   store,
+		fraud:        store,
+		imports:      store,
+	}
+}
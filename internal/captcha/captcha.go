@@ -0,0 +1,122 @@
+// Package captcha verifies third-party CAPTCHA responses for vote challenges
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"voteweb/internal/domain"
+)
+
+// Config holds a CAPTCHA provider's verification endpoint and keys
+type Config struct {
+	// VerifyURL is the provider's siteverify endpoint, e.g.
+	// https://hcaptcha.com/siteverify or
+	// https://challenges.cloudflare.com/turnstile/v0/siteverify
+	VerifyURL string
+	SiteKey   string
+	Secret    string
+}
+
+// SiteVerifier checks widget responses with the siteverify protocol shared
+// by reCAPTCHA, hCaptcha and Turnstile
+type SiteVerifier struct {
+	cfg    Config
+	client *http.Client
+}
+
+// NewSiteVerifier creates a SiteVerifier
+func NewSiteVerifier(cfg Config) *SiteVerifier {
+	return &SiteVerifier{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Issue tells the client to show the provider's widget
+func (v *SiteVerifier) Issue(ctx context.Context, clientIP string) (*domain.Challenge, error) {
+	return &domain.Challenge{
+		Kind:    domain.ChallengeCaptcha,
+		SiteKey: v.cfg.SiteKey,
+	}, nil
+}
+
+func (v *SiteVerifier) Verify(ctx context.Context, solution domain.ChallengeSolution, clientIP string) error {
+	if solution.Answer == "" {
+		return fmt.Errorf("%w: missing captcha response", domain.ErrChallengeFailed)
+	}
+
+	form := url.Values{
+		"secret":   {v.cfg.Secret},
+		"response": {solution.Answer},
+		"remoteip": {clientIP},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.cfg.VerifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("build captcha request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("verify captcha: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("verify captcha: provider answered %s", resp.Status)
+	}
+
+	var result struct {
+		Success    bool     `json:"success"`
+		ErrorCodes []string `json:"error-codes"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("decode captcha response: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("%w: %s", domain.ErrChallengeFailed, strings.Join(result.ErrorCodes, ", "))
+	}
+	return nil
+}
+
+// Release does nothing: the provider spends a response when it verifies it
+func (v *SiteVerifier) Release(ctx context.Context, solution domain.ChallengeSolution) error {
+	return nil
+}
+
+// StubAnswer is the only response the Stub verifier accepts
+const StubAnswer = "stub-pass"
+
+// Stub stands in for a CAPTCHA provider during local testing: it accepts
+// StubAnswer and nothing else, without any network call
+type Stub struct{}
+
+// NewStub creates a Stub
+func NewStub() *Stub {
+	return &Stub{}
+}
+
+func (s *Stub) Issue(ctx context.Context, clientIP string) (*domain.Challenge, error) {
+	return &domain.Challenge{
+		Kind:    domain.ChallengeCaptcha,
+		SiteKey: "stub",
+	}, nil
+}
+
+func (s *Stub) Verify(ctx context.Context, solution domain.ChallengeSolution, clientIP string) error {
+	if solution.Answer != StubAnswer {
+		return fmt.Errorf("%w: stub expects %q", domain.ErrChallengeFailed, StubAnswer)
+	}
+	return nil
+}
+
+// Release does nothing; the stub keeps no state
+func (s *Stub) Release(ctx context.Context, solution domain.ChallengeSolution) error {
+	return nil
+}
@@ -0,0 +1,200 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	minPasswordLength = 10
+	// maxPasswordBytes is the longest input bcrypt accepts
+	maxPasswordBytes = 72
+)
+
+var usernamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{2,63}$`)
+
+// AdminUserService manages admin accounts
+type AdminUserService interface {
+	ListUsers(ctx context.Context) ([]*AdminUser, error)
+	CreateUser(ctx context.Context, input AdminUserInput) (*AdminUser, error)
+	UpdateUser(ctx context.Context, id string, input AdminUserUpdate) (*AdminUser, error)
+}
+
+// AdminUserRepository stores admin accounts
+type AdminUserRepository interface {
+	CreateAdminUser(ctx context.Context, user *AdminUser) error
+	GetAdminUserByID(ctx context.Context, id string) (*AdminUser, error)
+	GetAdminUserByUsername(ctx context.Context, username string) (*AdminUser, error)
+	ListAdminUsers(ctx context.Context) ([]*AdminUser, error)
+	UpdateAdminUser(ctx context.Context, user *AdminUser) error
+	RecordAdminLogin(ctx context.Context, id string) error
+	CountActiveSuperadmins(ctx context.Context) (int, error)
+}
+
+// AdminUserInput holds the fields of a new admin account
+type AdminUserInput struct {
+	Username string    `json:"username"`
+	Password string    `json:"password"`
+	Role     AdminRole `json:"role"`
+}
+
+// AdminUserUpdate holds the fields to change on an admin account; nil fields are kept
+type AdminUserUpdate struct {
+	Role     *AdminRole `json:"role"`
+	Password *string    `json:"password"`
+	Disabled *bool      `json:"disabled"`
+}
+
+type adminUserService struct {
+	users     AdminUserRepository
+	sessions  SessionRepository
+	passwords PasswordHasher
+	audit     AuditRecorder
+	logger    *slog.Logger
+}
+
+// NewAdminUserService creates a new AdminUserService
+func NewAdminUserService(users AdminUserRepository, sessions SessionRepository, passwords PasswordHasher, audit AuditRecorder, logger *slog.Logger) AdminUserService {
+	return &adminUserService{
+		users:     users,
+		sessions:  sessions,
+		passwords: passwords,
+		audit:     audit,
+		logger:    logger,
+	}
+}
+
+func (s *adminUserService) ListUsers(ctx context.Context) ([]*AdminUser, error) {
+	return s.users.ListAdminUsers(ctx)
+}
+
+func (s *adminUserService) CreateUser(ctx context.Context, input AdminUserInput) (*AdminUser, error) {
+	username := normalizeUsername(input.Username)
+	if !usernamePattern.MatchString(username) {
+		return nil, fmt.Errorf("%w: username must be 3-64 characters of letters, digits, '.', '_' or '-'", ErrInvalidInput)
+	}
+	if !input.Role.IsValid() {
+		return nil, fmt.Errorf("%w: role must be viewer, operator or superadmin", ErrInvalidInput)
+	}
+	if err := validatePassword(input.Password); err != nil {
+		return nil, err
+	}
+
+	hash, err := s.passwords.Hash(input.Password)
+	if err != nil {
+		return nil, fmt.Errorf("hash password: %w", err)
+	}
+
+	user := &AdminUser{
+		Username:     username,
+		PasswordHash: hash,
+		Role:         input.Role,
+	}
+	if err := s.users.CreateAdminUser(ctx, user); err != nil {
+		if !errors.Is(err, ErrUsernameTaken) {
+			s.logger.ErrorContext(ctx, "failed to create admin user",
+				"username", username,
+				"error", err)
+		}
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "admin user created",
+		"user_id", user.ID,
+		"username", user.Username,
+		"role", user.Role)
+	s.audit.Record(ctx, AuditAdminUserCreated, "admin_user", user.ID, nil, user)
+	return user, nil
+}
+
+func (s *adminUserService) UpdateUser(ctx context.Context, id string, input AdminUserUpdate) (*AdminUser, error) {
+	user, err := s.users.GetAdminUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *user
+	wasActiveSuperadmin := user.Role == AdminRoleSuperadmin && user.DisabledAt == nil
+	revokeSessions := false
+
+	if input.Role != nil {
+		if !input.Role.IsValid() {
+			return nil, fmt.Errorf("%w: role must be viewer, operator or superadmin", ErrInvalidInput)
+		}
+		user.Role = *input.Role
+	}
+
+	if input.Password != nil {
+		if err := validatePassword(*input.Password); err != nil {
+			return nil, err
+		}
+		hash, err := s.passwords.Hash(*input.Password)
+		if err != nil {
+			return nil, fmt.Errorf("hash password: %w", err)
+		}
+		user.PasswordHash = hash
+		revokeSessions = true
+	}
+
+	if input.Disabled != nil {
+		switch {
+		case *input.Disabled && user.DisabledAt == nil:
+			now := time.Now()
+			user.DisabledAt = &now
+			revokeSessions = true
+		case !*input.Disabled:
+			user.DisabledAt = nil
+		}
+	}
+
+	isActiveSuperadmin := user.Role == AdminRoleSuperadmin && user.DisabledAt == nil
+	if wasActiveSuperadmin && !isActiveSuperadmin {
+		count, err := s.users.CountActiveSuperadmins(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if count <= 1 {
+			return nil, ErrLastSuperadmin
+		}
+	}
+
+	if err := s.users.UpdateAdminUser(ctx, user); err != nil {
+		s.logger.ErrorContext(ctx, "failed to update admin user",
+			"user_id", id,
+			"error", err)
+		return nil, err
+	}
+
+	if revokeSessions {
+		if err := s.sessions.RevokeUserSessions(ctx, user.ID); err != nil {
+			s.logger.ErrorContext(ctx, "failed to revoke admin user sessions",
+				"user_id", id,
+				"error", err)
+			return nil, err
+		}
+	}
+
+	s.logger.InfoContext(ctx, "admin user updated",
+		"user_id", user.ID,
+		"username", user.Username,
+		"role", user.Role,
+		"disabled", user.DisabledAt != nil)
+	s.audit.Record(ctx, AuditAdminUserUpdated, "admin_user", user.ID, before, user)
+	return user, nil
+}
+
+// validatePassword enforces the password length limits
+func validatePassword(password string) error {
+	if utf8.RuneCountInString(password) < minPasswordLength {
+		return fmt.Errorf("%w: password must be at least %d characters", ErrInvalidInput, minPasswordLength)
+	}
+	if len(password) > maxPasswordBytes {
+		return fmt.Errorf("%w: password must be at most %d bytes", ErrInvalidInput, maxPasswordBytes)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+)
+
+func newTestAdminUserService(t *testing.T) (*mockAdminUserRepository, *mockSessionRepository, *adminAuthService, AdminUserService) {
+	users, sessions, auth := newTestAuthService(t)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	return users, sessions, auth, NewAdminUserService(users, sessions, testPasswords, &mockAuditRecorder{}, logger)
+}
+
+func TestAdminUserService_CreateUser(t *testing.T) {
+	_, _, auth, service := newTestAdminUserService(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		input   AdminUserInput
+		wantErr error
+	}{
+		{"valid", AdminUserInput{Username: "Bob.Ops", Password: "long-enough-pw", Role: AdminRoleViewer}, nil},
+		{"duplicate username", AdminUserInput{Username: "ALICE", Password: "long-enough-pw", Role: AdminRoleViewer}, ErrUsernameTaken},
+		{"short username", AdminUserInput{Username: "ab", Password: "long-enough-pw", Role: AdminRoleViewer}, ErrInvalidInput},
+		{"username with spaces", AdminUserInput{Username: "bob smith", Password: "long-enough-pw", Role: AdminRoleViewer}, ErrInvalidInput},
+		{"short password", AdminUserInput{Username: "carol", Password: "short", Role: AdminRoleViewer}, ErrInvalidInput},
+		{"unknown role", AdminUserInput{Username: "carol", Password: "long-enough-pw", Role: "root"}, ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := service.CreateUser(ctx, tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateUser() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if user.Username != "bob.ops" {
+				t.Errorf("CreateUser() username = %q, want %q", user.Username, "bob.ops")
+			}
+			if user.PasswordHash == tt.input.Password {
+				t.Error("CreateUser() stored the plain password")
+			}
+			if _, _, err := auth.Login(ctx, "bob.ops", tt.input.Password, "test-agent"); err != nil {
+				t.Errorf("Login() as new user error = %v", err)
+			}
+		})
+	}
+}
+
+func TestAdminUserService_UpdateUser_RevokesSessions(t *testing.T) {
+	users, _, auth, service := newTestAdminUserService(t)
+	ctx := context.Background()
+	alice := users.users[0]
+
+	_, cookie, err := auth.Login(ctx, "alice", "correct-horse", "test-agent")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	password := "a-brand-new-password"
+	if _, err := service.UpdateUser(ctx, alice.ID, AdminUserUpdate{Password: &password}); err != nil {
+		t.Fatalf("UpdateUser() error = %v", err)
+	}
+
+	if _, err := auth.Authenticate(ctx, cookie); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("session survived password change, error = %v", err)
+	}
+	if _, _, err := auth.Login(ctx, "alice", "correct-horse", "test-agent"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Login() with old password error = %v, want %v", err, ErrInvalidCredentials)
+	}
+	if _, _, err := auth.Login(ctx, "alice", password, "test-agent"); err != nil {
+		t.Errorf("Login() with new password error = %v", err)
+	}
+}
+
+func TestAdminUserService_UpdateUser_LastSuperadmin(t *testing.T) {
+	users, _, _, service := newTestAdminUserService(t)
+	ctx := context.Background()
+	root := users.addUser(t, "root", AdminRoleSuperadmin)
+
+	disable := true
+	viewer := AdminRoleViewer
+
+	if _, err := service.UpdateUser(ctx, root.ID, AdminUserUpdate{Disabled: &disable}); !errors.Is(err, ErrLastSuperadmin) {
+		t.Errorf("disabling last superadmin error = %v, want %v", err, ErrLastSuperadmin)
+	}
+	if _, err := service.UpdateUser(ctx, root.ID, AdminUserUpdate{Role: &viewer}); !errors.Is(err, ErrLastSuperadmin) {
+		t.Errorf("demoting last superadmin error = %v, want %v", err, ErrLastSuperadmin)
+	}
+
+	superadmin := AdminRoleSuperadmin
+	alice := users.users[0]
+	if _, err := service.UpdateUser(ctx, alice.ID, AdminUserUpdate{Role: &superadmin}); err != nil {
+		t.Fatalf("promoting alice error = %v", err)
+	}
+	if _, err := service.UpdateUser(ctx, root.ID, AdminUserUpdate{Disabled: &disable}); err != nil {
+		t.Errorf("disabling one of two superadmins error = %v", err)
+	}
+
+	if _, err := service.UpdateUser(ctx, "missing", AdminUserUpdate{Role: &viewer}); !errors.Is(err, ErrAdminUserNotFound) {
+		t.Errorf("UpdateUser() unknown id error = %v, want %v", err, ErrAdminUserNotFound)
+	}
+}
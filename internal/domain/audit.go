@@ -0,0 +1,227 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+)
+
+const (
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 500
+)
+
+// AuditAction names what happened in an audit event
+type AuditAction string
+
+const (
+	AuditVotingWindowUpdated AuditAction = "voting.window_updated"
+	AuditResultsViewed       AuditAction = "results.viewed"
+	AuditResultsExported     AuditAction = "results.exported"
+	AuditVotesInvalidated    AuditAction = "vote.invalidated"
+	AuditVotesRestored       AuditAction = "vote.restored"
+
+	AuditInnovationCreated    AuditAction = "innovation.created"
+	AuditInnovationUpdated    AuditAction = "innovation.updated"
+	AuditInnovationsReordered AuditAction = "innovation.reordered"
+	AuditInnovationArchived   AuditAction = "innovation.archived"
+	AuditInnovationRestored   AuditAction = "innovation.restored"
+	AuditInnovationsImported  AuditAction = "innovation.imported"
+
+	AuditAdminLogin          AuditAction = "admin.login"
+	AuditAdminLoginFailed    AuditAction = "admin.login_failed"
+	AuditAdminLogout         AuditAction = "admin.logout"
+	AuditAdminSessionRevoked AuditAction = "admin.session_revoked"
+	AuditAdminUserCreated    AuditAction = "admin_user.created"
+	AuditAdminUserUpdated    AuditAction = "admin_user.updated"
+	AuditLogExported         AuditAction = "audit.exported"
+
+	// AuditSeedOverwritten is written by the seeder when it changes an existing row
+	AuditSeedOverwritten AuditAction = "seed.overwritten"
+)
+
+// Audit actor names used when no admin is logged in
+const (
+	AuditActorSystem    = "system"
+	AuditActorAnonymous = "anonymous"
+	AuditActorSeed      = "seed"
+	AuditActorCLI       = "cli"
+)
+
+// AuditRecorder appends events to the audit log. Services call it after a
+// change succeeds; the actor, request ID and client IP come from ctx.
+type AuditRecorder interface {
+	Record(ctx context.Context, action AuditAction, targetType, targetID string, before, after any)
+}
+
+// AuditService records and reads the audit log
+type AuditService interface {
+	AuditRecorder
+	ListEvents(ctx context.Context, filter AuditFilter) (*AuditPage, error)
+	// ExportEvents calls fn for every matching event, newest first, without paging
+	ExportEvents(ctx context.Context, filter AuditFilter, fn func(*AuditEvent) error) error
+}
+
+// AuditRepository stores audit events. There is no update or delete.
+type AuditRepository interface {
+	InsertAuditEvent(ctx context.Context, event *AuditEvent) error
+	ListAuditEvents(ctx context.Context, filter AuditFilter) ([]*AuditEvent, error)
+	StreamAuditEvents(ctx context.Context, filter AuditFilter, fn func(*AuditEvent) error) error
+}
+
+// AuditFilter selects audit events. Empty fields match everything. Pages are
+// keyed by ID: BeforeID returns events older than the given one.
+type AuditFilter struct {
+	Action     AuditAction
+	Actor      string
+	TargetType string
+	TargetID   string
+	Since      *time.Time
+	Until      *time.Time
+	BeforeID   int64
+	Limit      int
+}
+
+// AuditPage is one page of audit events, newest first
+type AuditPage struct {
+	Events []*AuditEvent `json:"events"`
+	// NextBeforeID is passed as BeforeID to fetch the next page; 0 on the last page
+	NextBeforeID int64 `json:"next_before_id"`
+}
+
+// AuditActor identifies who performed an action
+type AuditActor struct {
+	ID   string
+	Name string
+}
+
+// RequestInfo is the request metadata copied into audit events
+type RequestInfo struct {
+	RequestID string
+	ClientIP  string
+}
+
+type auditActorKey struct{}
+type requestInfoKey struct{}
+
+// WithAuditActor returns a context whose audit events are attributed to actor
+func WithAuditActor(ctx context.Context, actor AuditActor) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+// WithRequestInfo returns a context carrying the request ID and client IP
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// AuditActorFromContext returns the actor set by WithAuditActor
+func AuditActorFromContext(ctx context.Context) (AuditActor, bool) {
+	actor, ok := ctx.Value(auditActorKey{}).(AuditActor)
+	return actor, ok
+}
+
+// RequestInfoFromContext returns the metadata set by WithRequestInfo
+func RequestInfoFromContext(ctx context.Context) (RequestInfo, bool) {
+	info, ok := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info, ok
+}
+
+type auditService struct {
+	repo   AuditRepository
+	hasher IPHasher
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+// NewAuditService creates a new AuditService. Client IPs are stored hashed.
+func NewAuditService(repo AuditRepository, hasher IPHasher, logger *slog.Logger) AuditService {
+	return &auditService{
+		repo:   repo,
+		hasher: hasher,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Record writes the event. A failed write is logged but never fails the
+// action being audited, which has already happened.
+func (s *auditService) Record(ctx context.Context, action AuditAction, targetType, targetID string, before, after any) {
+	event := &AuditEvent{
+		OccurredAt: s.now(),
+		Actor:      AuditActorSystem,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Before:     s.marshal(ctx, action, before),
+		After:      s.marshal(ctx, action, after),
+	}
+
+	if info, ok := RequestInfoFromContext(ctx); ok {
+		event.Actor = AuditActorAnonymous
+		event.RequestID = info.RequestID
+		if info.ClientIP != "" {
+			event.IPHash = s.hasher.HashIP(info.ClientIP)
+		}
+	}
+	if actor, ok := AuditActorFromContext(ctx); ok {
+		event.Actor = actor.Name
+		if actor.ID != "" {
+			id := actor.ID
+			event.ActorID = &id
+		}
+	}
+
+	if err := s.repo.InsertAuditEvent(ctx, event); err != nil {
+		s.logger.ErrorContext(ctx, "failed to write audit event",
+			"action", action,
+			"target_type", targetType,
+			"target_id", targetID,
+			"error", err)
+	}
+}
+
+func (s *auditService) ListEvents(ctx context.Context, filter AuditFilter) (*AuditPage, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditPageSize
+	}
+	if filter.Limit > maxAuditPageSize {
+		filter.Limit = maxAuditPageSize
+	}
+
+	// Fetch one extra row to know whether another page exists
+	limit := filter.Limit
+	filter.Limit++
+	events, err := s.repo.ListAuditEvents(ctx, filter)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list audit events", "error", err)
+		return nil, err
+	}
+
+	page := &AuditPage{Events: events}
+	if len(events) > limit {
+		page.Events = events[:limit]
+		page.NextBeforeID = page.Events[limit-1].ID
+	}
+	return page, nil
+}
+
+func (s *auditService) ExportEvents(ctx context.Context, filter AuditFilter, fn func(*AuditEvent) error) error {
+	filter.Limit = 0
+	return s.repo.StreamAuditEvents(ctx, filter, fn)
+}
+
+// marshal encodes a before/after snapshot; nil stays nil
+func (s *auditService) marshal(ctx context.Context, action AuditAction, v any) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to encode audit snapshot",
+			"action", action,
+			"error", err)
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,190 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"testing"
+
+	"voteweb/internal/util"
+)
+
+// Mock audit recorder for testing other services
+type mockAuditRecorder struct {
+	entries []mockAuditEntry
+}
+
+type mockAuditEntry struct {
+	action   AuditAction
+	targetID string
+	before   any
+	after    any
+}
+
+func (m *mockAuditRecorder) Record(ctx context.Context, action AuditAction, targetType, targetID string, before, after any) {
+	m.entries = append(m.entries, mockAuditEntry{action: action, targetID: targetID, before: before, after: after})
+}
+
+// actions returns the recorded actions in order
+func (m *mockAuditRecorder) actions() []AuditAction {
+	var actions []AuditAction
+	for _, entry := range m.entries {
+		actions = append(actions, entry.action)
+	}
+	return actions
+}
+
+// Mock audit repository for testing
+type mockAuditRepository struct {
+	events []*AuditEvent
+}
+
+func (m *mockAuditRepository) InsertAuditEvent(ctx context.Context, event *AuditEvent) error {
+	event.ID = int64(len(m.events) + 1)
+	m.events = append(m.events, event)
+	return nil
+}
+
+func (m *mockAuditRepository) ListAuditEvents(ctx context.Context, filter AuditFilter) ([]*AuditEvent, error) {
+	var events []*AuditEvent
+	err := m.StreamAuditEvents(ctx, filter, func(event *AuditEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	return events, err
+}
+
+func (m *mockAuditRepository) StreamAuditEvents(ctx context.Context, filter AuditFilter, fn func(*AuditEvent) error) error {
+	count := 0
+	for i := len(m.events) - 1; i >= 0; i-- {
+		event := m.events[i]
+		if filter.BeforeID > 0 && event.ID >= filter.BeforeID {
+			continue
+		}
+		if filter.Action != "" && event.Action != filter.Action {
+			continue
+		}
+		if filter.Limit > 0 && count == filter.Limit {
+			break
+		}
+		count++
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newTestAuditService() (*mockAuditRepository, AuditService) {
+	repo := &mockAuditRepository{}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	return repo, NewAuditService(repo, util.NewIPHasher("test-salt"), logger)
+}
+
+func TestAuditService_Record(t *testing.T) {
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		wantActor  string
+		wantID     bool
+		wantIPHash bool
+		wantReqID  string
+	}{
+		{
+			name:      "background job",
+			ctx:       context.Background(),
+			wantActor: AuditActorSystem,
+		},
+		{
+			name:       "public request",
+			ctx:        WithRequestInfo(context.Background(), RequestInfo{RequestID: "req-1", ClientIP: "192.0.2.1"}),
+			wantActor:  AuditActorAnonymous,
+			wantIPHash: true,
+			wantReqID:  "req-1",
+		},
+		{
+			name: "admin request",
+			ctx: WithAuditActor(
+				WithRequestInfo(context.Background(), RequestInfo{RequestID: "req-2", ClientIP: "192.0.2.1"}),
+				AuditActor{ID: "user-1", Name: "alice"}),
+			wantActor:  "alice",
+			wantID:     true,
+			wantIPHash: true,
+			wantReqID:  "req-2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, service := newTestAuditService()
+
+			service.Record(tt.ctx, AuditVotingWindowUpdated, "event", "event-1",
+				VotingWindow{Paused: false}, VotingWindow{Paused: true})
+
+			if len(repo.events) != 1 {
+				t.Fatalf("events written = %d, want 1", len(repo.events))
+			}
+			event := repo.events[0]
+			if event.Actor != tt.wantActor {
+				t.Errorf("Actor = %q, want %q", event.Actor, tt.wantActor)
+			}
+			if (event.ActorID != nil) != tt.wantID {
+				t.Errorf("ActorID = %v, want set = %v", event.ActorID, tt.wantID)
+			}
+			if (len(event.IPHash) > 0) != tt.wantIPHash {
+				t.Errorf("IPHash = %x, want set = %v", event.IPHash, tt.wantIPHash)
+			}
+			if string(event.IPHash) == "192.0.2.1" {
+				t.Error("IPHash stores the raw IP")
+			}
+			if event.RequestID != tt.wantReqID {
+				t.Errorf("RequestID = %q, want %q", event.RequestID, tt.wantReqID)
+			}
+
+			var after VotingWindow
+			if err := json.Unmarshal(event.After, &after); err != nil || !after.Paused {
+				t.Errorf("After = %s, want paused window", event.After)
+			}
+			if event.Before == nil {
+				t.Error("Before = nil, want snapshot")
+			}
+		})
+	}
+}
+
+func TestAuditService_ListEvents(t *testing.T) {
+	repo, service := newTestAuditService()
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		service.Record(ctx, AuditResultsViewed, "event", "event-1", nil, nil)
+	}
+	service.Record(ctx, AuditAdminLogin, "admin_session", "session-1", nil, nil)
+
+	first, err := service.ListEvents(ctx, AuditFilter{Action: AuditResultsViewed, Limit: 3})
+	if err != nil {
+		t.Fatalf("ListEvents() error = %v", err)
+	}
+	if len(first.Events) != 3 || first.Events[0].ID != 5 {
+		t.Fatalf("first page = %d events starting at %d, want 3 starting at 5", len(first.Events), first.Events[0].ID)
+	}
+	if first.NextBeforeID != 3 {
+		t.Errorf("NextBeforeID = %d, want 3", first.NextBeforeID)
+	}
+	if first.Events[0].Before != nil {
+		t.Errorf("Before = %s, want nil for a nil snapshot", first.Events[0].Before)
+	}
+
+	second, err := service.ListEvents(ctx, AuditFilter{Action: AuditResultsViewed, Limit: 3, BeforeID: first.NextBeforeID})
+	if err != nil {
+		t.Fatalf("ListEvents() error = %v", err)
+	}
+	if len(second.Events) != 2 || second.NextBeforeID != 0 {
+		t.Errorf("second page = %d events, next %d; want 2 events, next 0", len(second.Events), second.NextBeforeID)
+	}
+
+	if len(repo.events) != 6 {
+		t.Errorf("events written = %d, want 6", len(repo.events))
+	}
+}
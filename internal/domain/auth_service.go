@@ -0,0 +1,255 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sessionTouchInterval limits how often last_seen_at is written
+const sessionTouchInterval = time.Minute
+
+// AdminAuthService handles admin login and server-side sessions
+type AdminAuthService interface {
+	// Login checks the credentials and returns the session and its signed cookie value
+	Login(ctx context.Context, username, password, userAgent string) (*AdminSession, string, error)
+	// Authenticate resolves a signed cookie value to an active session
+	Authenticate(ctx context.Context, signedToken string) (*AdminSession, error)
+	Logout(ctx context.Context, signedToken string) error
+	ListSessions(ctx context.Context) ([]*AdminSession, error)
+	RevokeSession(ctx context.Context, id string) error
+}
+
+// SessionRepository stores admin sessions
+type SessionRepository interface {
+	CreateAdminSession(ctx context.Context, session *AdminSession) error
+	GetAdminSession(ctx context.Context, tokenHash []byte) (*AdminSession, error)
+	TouchAdminSession(ctx context.Context, id string) error
+	ListActiveAdminSessions(ctx context.Context) ([]*AdminSession, error)
+	RevokeAdminSession(ctx context.Context, id string) error
+	RevokeUserSessions(ctx context.Context, userID string) error
+}
+
+// TokenSigner defines the interface for signing session tokens
+type TokenSigner interface {
+	Sign(token string) string
+	Verify(signed string) (string, bool)
+}
+
+// PasswordHasher defines the interface for hashing admin passwords
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Compare(hash, password string) bool
+}
+
+type adminAuthService struct {
+	users     AdminUserRepository
+	sessions  SessionRepository
+	signer    TokenSigner
+	passwords PasswordHasher
+	audit     AuditRecorder
+	ttl       time.Duration
+	logger    *slog.Logger
+	now       func() time.Time
+
+	dummyOnce sync.Once
+	dummyHash string
+}
+
+// NewAdminAuthService creates a new AdminAuthService. Sessions expire after ttl.
+func NewAdminAuthService(users AdminUserRepository, sessions SessionRepository, signer TokenSigner, passwords PasswordHasher, audit AuditRecorder, ttl time.Duration, logger *slog.Logger) AdminAuthService {
+	return &adminAuthService{
+		users:     users,
+		sessions:  sessions,
+		signer:    signer,
+		passwords: passwords,
+		audit:     audit,
+		ttl:       ttl,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+func (s *adminAuthService) Login(ctx context.Context, username, password, userAgent string) (*AdminSession, string, error) {
+	username = normalizeUsername(username)
+
+	user, err := s.users.GetAdminUserByUsername(ctx, username)
+	if err != nil && !errors.Is(err, ErrAdminUserNotFound) {
+		s.logger.ErrorContext(ctx, "failed to load admin user",
+			"username", username,
+			"error", err)
+		return nil, "", err
+	}
+
+	if user == nil || user.DisabledAt != nil {
+		// Spend the same time as a real check so unknown usernames cannot be probed
+		s.passwords.Compare(s.dummyPasswordHash(), password)
+		s.logger.WarnContext(ctx, "admin login rejected", "username", username)
+		s.audit.Record(ctx, AuditAdminLoginFailed, "admin_user", username, nil, nil)
+		return nil, "", ErrInvalidCredentials
+	}
+
+	if !s.passwords.Compare(user.PasswordHash, password) {
+		s.logger.WarnContext(ctx, "admin login rejected", "username", username)
+		s.audit.Record(ctx, AuditAdminLoginFailed, "admin_user", username, nil, nil)
+		return nil, "", ErrInvalidCredentials
+	}
+
+	token, err := newSessionToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := s.now()
+	session := &AdminSession{
+		TokenHash:  hashSessionToken(token),
+		UserID:     user.ID,
+		Username:   user.Username,
+		Role:       user.Role,
+		UserAgent:  userAgent,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(s.ttl),
+	}
+
+	if err := s.sessions.CreateAdminSession(ctx, session); err != nil {
+		s.logger.ErrorContext(ctx, "failed to create admin session",
+			"user_id", user.ID,
+			"error", err)
+		return nil, "", err
+	}
+
+	if err := s.users.RecordAdminLogin(ctx, user.ID); err != nil {
+		s.logger.WarnContext(ctx, "failed to record admin login",
+			"user_id", user.ID,
+			"error", err)
+	}
+
+	s.logger.InfoContext(ctx, "admin logged in",
+		"session_id", session.ID,
+		"username", user.Username,
+		"role", user.Role)
+	s.audit.Record(WithAuditActor(ctx, AuditActor{ID: user.ID, Name: user.Username}),
+		AuditAdminLogin, "admin_session", session.ID, nil, nil)
+	return session, s.signer.Sign(token), nil
+}
+
+func (s *adminAuthService) Authenticate(ctx context.Context, signedToken string) (*AdminSession, error) {
+	token, ok := s.signer.Verify(signedToken)
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+
+	session, err := s.sessions.GetAdminSession(ctx, hashSessionToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	if !session.IsActive(now) {
+		return nil, ErrSessionNotFound
+	}
+
+	// Read the account on every request so disabling or demoting takes effect at once
+	user, err := s.users.GetAdminUserByID(ctx, session.UserID)
+	if err != nil {
+		if errors.Is(err, ErrAdminUserNotFound) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, err
+	}
+	if user.DisabledAt != nil {
+		return nil, ErrSessionNotFound
+	}
+	session.Username = user.Username
+	session.Role = user.Role
+
+	if now.Sub(session.LastSeenAt) >= sessionTouchInterval {
+		if err := s.sessions.TouchAdminSession(ctx, session.ID); err != nil {
+			s.logger.WarnContext(ctx, "failed to update admin session last seen",
+				"session_id", session.ID,
+				"error", err)
+		}
+		session.LastSeenAt = now
+	}
+
+	return session, nil
+}
+
+func (s *adminAuthService) Logout(ctx context.Context, signedToken string) error {
+	session, err := s.Authenticate(ctx, signedToken)
+	if err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if err := s.sessions.RevokeAdminSession(ctx, session.ID); err != nil {
+		s.logger.ErrorContext(ctx, "failed to revoke admin session",
+			"session_id", session.ID,
+			"error", err)
+		return err
+	}
+
+	s.audit.Record(WithAuditActor(ctx, AuditActor{ID: session.UserID, Name: session.Username}),
+		AuditAdminLogout, "admin_session", session.ID, nil, nil)
+	return nil
+}
+
+func (s *adminAuthService) ListSessions(ctx context.Context) ([]*AdminSession, error) {
+	return s.sessions.ListActiveAdminSessions(ctx)
+}
+
+func (s *adminAuthService) RevokeSession(ctx context.Context, id string) error {
+	if err := s.sessions.RevokeAdminSession(ctx, id); err != nil {
+		if !errors.Is(err, ErrSessionNotFound) {
+			s.logger.ErrorContext(ctx, "failed to revoke admin session",
+				"session_id", id,
+				"error", err)
+		}
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "admin session revoked", "session_id", id)
+	s.audit.Record(ctx, AuditAdminSessionRevoked, "admin_session", id, nil, nil)
+	return nil
+}
+
+// dummyPasswordHash returns a hash to compare against when the user is unknown
+func (s *adminAuthService) dummyPasswordHash() string {
+	s.dummyOnce.Do(func() {
+		hash, err := s.passwords.Hash("dummy-password-for-timing")
+		if err == nil {
+			s.dummyHash = hash
+		}
+	})
+	return s.dummyHash
+}
+
+// newSessionToken returns 32 random bytes encoded as base64url
+func newSessionToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate session token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashSessionToken returns the digest stored in place of the raw token
+func hashSessionToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
+
+// normalizeUsername makes usernames case-insensitive
+func normalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
@@ -0,0 +1,339 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"voteweb/internal/util"
+)
+
+// Mock session repository for testing
+type mockSessionRepository struct {
+	sessions []*AdminSession
+}
+
+func (m *mockSessionRepository) CreateAdminSession(ctx context.Context, session *AdminSession) error {
+	session.ID = fmt.Sprintf("session-%d", len(m.sessions)+1)
+	m.sessions = append(m.sessions, session)
+	return nil
+}
+
+func (m *mockSessionRepository) GetAdminSession(ctx context.Context, tokenHash []byte) (*AdminSession, error) {
+	for _, session := range m.sessions {
+		if string(session.TokenHash) == string(tokenHash) {
+			copied := *session
+			return &copied, nil
+		}
+	}
+	return nil, ErrSessionNotFound
+}
+
+func (m *mockSessionRepository) TouchAdminSession(ctx context.Context, id string) error {
+	return nil
+}
+
+func (m *mockSessionRepository) ListActiveAdminSessions(ctx context.Context) ([]*AdminSession, error) {
+	var sessions []*AdminSession
+	for _, session := range m.sessions {
+		if session.RevokedAt == nil {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func (m *mockSessionRepository) RevokeAdminSession(ctx context.Context, id string) error {
+	for _, session := range m.sessions {
+		if session.ID == id && session.RevokedAt == nil {
+			now := time.Now()
+			session.RevokedAt = &now
+			return nil
+		}
+	}
+	return ErrSessionNotFound
+}
+
+func (m *mockSessionRepository) RevokeUserSessions(ctx context.Context, userID string) error {
+	now := time.Now()
+	for _, session := range m.sessions {
+		if session.UserID == userID && session.RevokedAt == nil {
+			session.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+// Mock admin user repository for testing
+type mockAdminUserRepository struct {
+	users []*AdminUser
+}
+
+func (m *mockAdminUserRepository) CreateAdminUser(ctx context.Context, user *AdminUser) error {
+	for _, existing := range m.users {
+		if strings.EqualFold(existing.Username, user.Username) {
+			return ErrUsernameTaken
+		}
+	}
+	user.ID = fmt.Sprintf("user-%d", len(m.users)+1)
+	copied := *user
+	m.users = append(m.users, &copied)
+	return nil
+}
+
+func (m *mockAdminUserRepository) GetAdminUserByID(ctx context.Context, id string) (*AdminUser, error) {
+	for _, user := range m.users {
+		if user.ID == id {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, ErrAdminUserNotFound
+}
+
+func (m *mockAdminUserRepository) GetAdminUserByUsername(ctx context.Context, username string) (*AdminUser, error) {
+	for _, user := range m.users {
+		if strings.EqualFold(user.Username, username) {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, ErrAdminUserNotFound
+}
+
+func (m *mockAdminUserRepository) ListAdminUsers(ctx context.Context) ([]*AdminUser, error) {
+	return m.users, nil
+}
+
+func (m *mockAdminUserRepository) UpdateAdminUser(ctx context.Context, user *AdminUser) error {
+	for i, existing := range m.users {
+		if existing.ID == user.ID {
+			copied := *user
+			m.users[i] = &copied
+			return nil
+		}
+	}
+	return ErrAdminUserNotFound
+}
+
+func (m *mockAdminUserRepository) RecordAdminLogin(ctx context.Context, id string) error {
+	return nil
+}
+
+func (m *mockAdminUserRepository) CountActiveSuperadmins(ctx context.Context) (int, error) {
+	count := 0
+	for _, user := range m.users {
+		if user.Role == AdminRoleSuperadmin && user.DisabledAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+var testPasswords = util.NewPasswordHasher(bcrypt.MinCost)
+
+// addUser stores an account with the given role and password "correct-horse"
+func (m *mockAdminUserRepository) addUser(t *testing.T, username string, role AdminRole) *AdminUser {
+	t.Helper()
+	hash, err := testPasswords.Hash("correct-horse")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	user := &AdminUser{Username: username, PasswordHash: hash, Role: role}
+	if err := m.CreateAdminUser(context.Background(), user); err != nil {
+		t.Fatalf("CreateAdminUser() error = %v", err)
+	}
+	return user
+}
+
+func newTestAuthService(t *testing.T) (*mockAdminUserRepository, *mockSessionRepository, *adminAuthService) {
+	users := &mockAdminUserRepository{}
+	users.addUser(t, "alice", AdminRoleOperator)
+	sessions := &mockSessionRepository{}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	service := NewAdminAuthService(users, sessions, util.NewTokenSigner("test-secret"), testPasswords, &mockAuditRecorder{}, time.Hour, logger)
+	return users, sessions, service.(*adminAuthService)
+}
+
+func TestAdminAuthService_Login(t *testing.T) {
+	_, repo, service := newTestAuthService(t)
+	ctx := context.Background()
+
+	rejected := []struct {
+		name     string
+		username string
+		password string
+	}{
+		{"wrong password", "alice", "wrong-password"},
+		{"empty password", "alice", ""},
+		{"unknown user", "mallory", "correct-horse"},
+	}
+	for _, tt := range rejected {
+		if _, _, err := service.Login(ctx, tt.username, tt.password, "test-agent"); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("Login() with %s error = %v, want %v", tt.name, err, ErrInvalidCredentials)
+		}
+	}
+	if len(repo.sessions) != 0 {
+		t.Fatalf("sessions created = %d after rejected logins, want 0", len(repo.sessions))
+	}
+
+	session, cookie, err := service.Login(ctx, " Alice ", "correct-horse", "test-agent")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if string(repo.sessions[0].TokenHash) == cookie {
+		t.Error("session stores the raw cookie value")
+	}
+
+	authenticated, err := service.Authenticate(ctx, cookie)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if authenticated.ID != session.ID {
+		t.Errorf("Authenticate() session = %s, want %s", authenticated.ID, session.ID)
+	}
+	if authenticated.Username != "alice" || authenticated.Role != AdminRoleOperator {
+		t.Errorf("Authenticate() = %s/%s, want alice/operator", authenticated.Username, authenticated.Role)
+	}
+}
+
+func TestAdminAuthService_Authenticate(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		mutate func(users *mockAdminUserRepository, service *adminAuthService, cookie string) string
+	}{
+		{
+			name: "tampered cookie",
+			mutate: func(users *mockAdminUserRepository, service *adminAuthService, cookie string) string {
+				return "x" + cookie
+			},
+		},
+		{
+			name: "forged signature",
+			mutate: func(users *mockAdminUserRepository, service *adminAuthService, cookie string) string {
+				return util.NewTokenSigner("other-secret").Sign("forged-token")
+			},
+		},
+		{
+			name: "expired session",
+			mutate: func(users *mockAdminUserRepository, service *adminAuthService, cookie string) string {
+				service.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+				return cookie
+			},
+		},
+		{
+			name: "logged out session",
+			mutate: func(users *mockAdminUserRepository, service *adminAuthService, cookie string) string {
+				if err := service.Logout(ctx, cookie); err != nil {
+					t.Fatalf("Logout() error = %v", err)
+				}
+				return cookie
+			},
+		},
+		{
+			name: "disabled account",
+			mutate: func(users *mockAdminUserRepository, service *adminAuthService, cookie string) string {
+				now := time.Now()
+				users.users[0].DisabledAt = &now
+				return cookie
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, _, service := newTestAuthService(t)
+
+			_, cookie, err := service.Login(ctx, "alice", "correct-horse", "test-agent")
+			if err != nil {
+				t.Fatalf("Login() error = %v", err)
+			}
+
+			_, err = service.Authenticate(ctx, tt.mutate(users, service, cookie))
+			if !errors.Is(err, ErrSessionNotFound) {
+				t.Errorf("Authenticate() error = %v, want %v", err, ErrSessionNotFound)
+			}
+		})
+	}
+}
+
+func TestAdminAuthService_RoleChangeAppliesToOpenSessions(t *testing.T) {
+	users, _, service := newTestAuthService(t)
+	ctx := context.Background()
+
+	_, cookie, err := service.Login(ctx, "alice", "correct-horse", "test-agent")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	users.users[0].Role = AdminRoleViewer
+
+	session, err := service.Authenticate(ctx, cookie)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if session.Role != AdminRoleViewer {
+		t.Errorf("Authenticate() role = %s, want %s", session.Role, AdminRoleViewer)
+	}
+}
+
+func TestAdminAuthService_RevokeSession(t *testing.T) {
+	_, _, service := newTestAuthService(t)
+	ctx := context.Background()
+
+	first, firstCookie, _ := service.Login(ctx, "alice", "correct-horse", "browser-1")
+	_, secondCookie, _ := service.Login(ctx, "alice", "correct-horse", "browser-2")
+
+	if err := service.RevokeSession(ctx, first.ID); err != nil {
+		t.Fatalf("RevokeSession() error = %v", err)
+	}
+
+	if _, err := service.Authenticate(ctx, firstCookie); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("revoked session still authenticates, error = %v", err)
+	}
+	if _, err := service.Authenticate(ctx, secondCookie); err != nil {
+		t.Errorf("other session stopped working: %v", err)
+	}
+
+	sessions, err := service.ListSessions(ctx)
+	if err != nil {
+		t.Fatalf("ListSessions() error = %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Errorf("ListSessions() = %d sessions, want 1", len(sessions))
+	}
+
+	if err := service.RevokeSession(ctx, first.ID); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("RevokeSession() twice error = %v, want %v", err, ErrSessionNotFound)
+	}
+}
+
+func TestAdminRole_Allows(t *testing.T) {
+	tests := []struct {
+		role     AdminRole
+		required AdminRole
+		want     bool
+	}{
+		{AdminRoleViewer, AdminRoleViewer, true},
+		{AdminRoleViewer, AdminRoleOperator, false},
+		{AdminRoleOperator, AdminRoleViewer, true},
+		{AdminRoleOperator, AdminRoleSuperadmin, false},
+		{AdminRoleSuperadmin, AdminRoleOperator, true},
+		{AdminRole("root"), AdminRoleViewer, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.role.Allows(tt.required); got != tt.want {
+			t.Errorf("%s.Allows(%s) = %v, want %v", tt.role, tt.required, got, tt.want)
+		}
+	}
+}
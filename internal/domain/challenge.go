@@ -0,0 +1,224 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Challenge kinds
+const (
+	ChallengeProofOfWork = "pow"
+	ChallengeCaptcha     = "captcha"
+)
+
+// Challenge is a puzzle the client must solve before a suspicious vote counts
+type Challenge struct {
+	Kind string `json:"kind"`
+	// Token identifies the challenge and is sent back with the solution
+	Token string `json:"token,omitempty"`
+	// Difficulty is the number of leading zero bits a proof of work needs
+	Difficulty int `json:"difficulty,omitempty"`
+	// SiteKey identifies the site to a CAPTCHA widget
+	SiteKey   string    `json:"site_key,omitempty"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+// ChallengeSolution is the client's answer to a challenge. A CAPTCHA answer
+// is the widget's response token.
+type ChallengeSolution struct {
+	Token  string
+	Answer string
+}
+
+// Empty reports whether the client sent no solution
+func (s ChallengeSolution) Empty() bool {
+	return s.Token == "" && s.Answer == ""
+}
+
+// ChallengeVerifier issues challenges and checks their solutions. clientIP
+// lets a verifier tie a challenge to the client it was issued to.
+type ChallengeVerifier interface {
+	Issue(ctx context.Context, clientIP string) (*Challenge, error)
+	// Verify returns ErrChallengeFailed for a wrong, expired or reused
+	// solution. An accepted solution is used up.
+	Verify(ctx context.Context, solution ChallengeSolution, clientIP string) error
+	// Release makes an accepted solution usable again, where the verifier
+	// can take it back
+	Release(ctx context.Context, solution ChallengeSolution) error
+}
+
+// ChallengeRepository counts recent votes for suspicion scoring and remembers
+// solved challenges
+type ChallengeRepository interface {
+	CountRecentVotesByIP(ctx context.Context, eventID string, ipHash []byte, since time.Time) (int64, error)
+	// UseChallengeToken records a solved challenge token until expiresAt and
+	// returns false when it was recorded before. Expired tokens may be forgotten.
+	UseChallengeToken(ctx context.Context, tokenHash []byte, expiresAt time.Time) (bool, error)
+	// ReleaseChallengeToken forgets a recorded challenge token
+	ReleaseChallengeToken(ctx context.Context, tokenHash []byte) error
+}
+
+// ChallengeSettings decides which votes need a solved challenge
+type ChallengeSettings struct {
+	// Threshold is the suspicion score from which a challenge is required;
+	// 0 challenges every vote
+	Threshold int
+	// Window is how far back votes from the same IP raise the score
+	Window time.Duration
+}
+
+// DefaultChallengeWindow is used when ChallengeSettings.Window is unset
+const DefaultChallengeWindow = 10 * time.Minute
+
+// ChallengeService asks suspicious voters to solve a challenge
+type ChallengeService interface {
+	// Check returns a nil error when the vote may go ahead, with a hold on
+	// the solution when it went ahead on one; the caller releases the hold
+	// if the vote does not count. Otherwise it returns a fresh challenge with
+	// ErrChallengeRequired, or with ErrChallengeFailed when the solution sent
+	// was rejected.
+	Check(ctx context.Context, event *Event, req VoteRequest, solution ChallengeSolution) (*Challenge, *ChallengeHold, error)
+}
+
+// ChallengeHold is an accepted solution held for the vote it let through
+type ChallengeHold struct {
+	verifier ChallengeVerifier
+	solution ChallengeSolution
+	eventID  string
+	logger   *slog.Logger
+}
+
+// Release hands the solution back after the vote did not count, for example
+// because voting closed or the voter had already voted, so the client need
+// not solve another challenge. Release on a nil hold does nothing.
+func (h *ChallengeHold) Release(ctx context.Context) {
+	if h == nil {
+		return
+	}
+	// Release even when the client has gone away
+	ctx = context.WithoutCancel(ctx)
+	if err := h.verifier.Release(ctx, h.solution); err != nil {
+		h.logger.ErrorContext(ctx, "failed to release challenge",
+			"event_id", h.eventID,
+			"error", err)
+	}
+}
+
+type challengeService struct {
+	repo     ChallengeRepository
+	hasher   IPHasher
+	verifier ChallengeVerifier
+	settings ChallengeSettings
+	logger   *slog.Logger
+	now      func() time.Time
+}
+
+// NewChallengeService creates a new ChallengeService. A nil verifier turns
+// challenges off.
+func NewChallengeService(repo ChallengeRepository, hasher IPHasher, verifier ChallengeVerifier, settings ChallengeSettings, logger *slog.Logger) ChallengeService {
+	if settings.Window <= 0 {
+		settings.Window = DefaultChallengeWindow
+	}
+	return &challengeService{
+		repo:     repo,
+		hasher:   hasher,
+		verifier: verifier,
+		settings: settings,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+func (s *challengeService) Check(ctx context.Context, event *Event, req VoteRequest, solution ChallengeSolution) (*Challenge, *ChallengeHold, error) {
+	if s.verifier == nil {
+		return nil, nil, nil
+	}
+
+	score, err := s.suspicion(ctx, event, req)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to score vote request",
+			"event_id", event.ID,
+			"error", err)
+		return nil, nil, err
+	}
+	if score < s.settings.Threshold {
+		return nil, nil, nil
+	}
+
+	reason := ErrChallengeRequired
+	if !solution.Empty() {
+		err := s.verifier.Verify(ctx, solution, req.ClientIP)
+		if err == nil {
+			return nil, &ChallengeHold{verifier: s.verifier, solution: solution, eventID: event.ID, logger: s.logger}, nil
+		}
+		if !errors.Is(err, ErrChallengeFailed) {
+			s.logger.ErrorContext(ctx, "failed to verify challenge",
+				"event_id", event.ID,
+				"error", err)
+			return nil, nil, err
+		}
+		reason = ErrChallengeFailed
+	}
+
+	s.logger.InfoContext(ctx, "vote challenged",
+		"event_id", event.ID,
+		"suspicion", score,
+		"reason", reason.Error())
+
+	challenge, err := s.verifier.Issue(ctx, req.ClientIP)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to issue challenge",
+			"event_id", event.ID,
+			"error", err)
+		return nil, nil, err
+	}
+	return challenge, nil, reason
+}
+
+// suspicion scores a vote request: 2 for a missing or scripted user agent,
+// 1 without a voter token, and 1 for every vote cast from the same IP in the
+// event within the window
+func (s *challengeService) suspicion(ctx context.Context, event *Event, req VoteRequest) (int, error) {
+	score := 0
+	if scriptedUserAgent(req.UserAgent) {
+		score += 2
+	}
+	if req.VoterToken == "" {
+		score++
+	}
+
+	// Already suspicious enough; skip the query
+	if score >= s.settings.Threshold {
+		return score, nil
+	}
+
+	recent, err := s.repo.CountRecentVotesByIP(ctx, event.ID, s.hasher.HashIP(req.ClientIP), s.now().Add(-s.settings.Window))
+	if err != nil {
+		return 0, fmt.Errorf("count recent votes: %w", err)
+	}
+	return score + int(recent), nil
+}
+
+// scriptedUserAgents are user agent fragments of HTTP libraries and headless
+// browsers
+var scriptedUserAgents = []string{
+	"curl", "wget", "python", "go-http-client", "java/", "node-fetch", "axios",
+	"httpie", "libwww", "headless", "phantomjs", "bot", "spider", "crawl",
+}
+
+func scriptedUserAgent(userAgent string) bool {
+	userAgent = strings.ToLower(strings.TrimSpace(userAgent))
+	if userAgent == "" {
+		return true
+	}
+	for _, fragment := range scriptedUserAgents {
+		if strings.Contains(userAgent, fragment) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,275 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"log/slog"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"voteweb/internal/util"
+)
+
+const browserUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36"
+
+// Mock challenge repository
+type mockChallengeRepository struct {
+	recent int64
+	since  time.Time
+	used   map[string]time.Time
+}
+
+func (m *mockChallengeRepository) CountRecentVotesByIP(ctx context.Context, eventID string, ipHash []byte, since time.Time) (int64, error) {
+	m.since = since
+	return m.recent, nil
+}
+
+func (m *mockChallengeRepository) UseChallengeToken(ctx context.Context, tokenHash []byte, expiresAt time.Time) (bool, error) {
+	if m.used == nil {
+		m.used = make(map[string]time.Time)
+	}
+	if _, ok := m.used[string(tokenHash)]; ok {
+		return false, nil
+	}
+	m.used[string(tokenHash)] = expiresAt
+	return true, nil
+}
+
+func (m *mockChallengeRepository) ReleaseChallengeToken(ctx context.Context, tokenHash []byte) error {
+	delete(m.used, string(tokenHash))
+	return nil
+}
+
+// Mock challenge verifier; the answer "ok" passes
+type mockChallengeVerifier struct {
+	issued   int
+	released []ChallengeSolution
+}
+
+func (m *mockChallengeVerifier) Issue(ctx context.Context, clientIP string) (*Challenge, error) {
+	m.issued++
+	return &Challenge{Kind: ChallengeProofOfWork, Token: "challenge-" + strconv.Itoa(m.issued)}, nil
+}
+
+func (m *mockChallengeVerifier) Verify(ctx context.Context, solution ChallengeSolution, clientIP string) error {
+	if solution.Answer != "ok" {
+		return ErrChallengeFailed
+	}
+	return nil
+}
+
+func (m *mockChallengeVerifier) Release(ctx context.Context, solution ChallengeSolution) error {
+	m.released = append(m.released, solution)
+	return nil
+}
+
+func TestChallengeService_Check(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	event := &Event{ID: testEventID}
+	browser := VoteRequest{ClientIP: "203.0.113.7", UserAgent: browserUserAgent, VoterToken: "token-a"}
+	script := VoteRequest{ClientIP: "203.0.113.7", UserAgent: "python-requests/2.31"}
+
+	tests := []struct {
+		name     string
+		req      VoteRequest
+		recent   int64
+		solution ChallengeSolution
+		wantErr  error
+		// wantHold is whether the vote goes ahead on the solution
+		wantHold bool
+	}{
+		{
+			name: "browser with a voter token",
+			req:  browser,
+		},
+		{
+			name:   "busy network below the threshold",
+			req:    browser,
+			recent: 2,
+		},
+		{
+			name:    "busy network at the threshold",
+			req:     browser,
+			recent:  3,
+			wantErr: ErrChallengeRequired,
+		},
+		{
+			name:    "script without a voter token",
+			req:     script,
+			wantErr: ErrChallengeRequired,
+		},
+		{
+			name:     "browser with an unneeded solution",
+			req:      browser,
+			solution: ChallengeSolution{Token: "challenge-1", Answer: "ok"},
+		},
+		{
+			name:     "script with a solution",
+			req:      script,
+			solution: ChallengeSolution{Token: "challenge-1", Answer: "ok"},
+			wantHold: true,
+		},
+		{
+			name:     "script with a wrong solution",
+			req:      script,
+			solution: ChallengeSolution{Token: "challenge-1", Answer: "guess"},
+			wantErr:  ErrChallengeFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockChallengeRepository{recent: tt.recent}
+			verifier := &mockChallengeVerifier{}
+			service := NewChallengeService(repo, &mockIPHasher{}, verifier, ChallengeSettings{Threshold: 3}, logger)
+
+			challenge, hold, err := service.Check(context.Background(), event, tt.req, tt.solution)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Check() error = %v, want %v", err, tt.wantErr)
+			}
+			if (challenge != nil) != (tt.wantErr != nil) {
+				t.Errorf("Check() challenge = %+v, want one only with an error", challenge)
+			}
+			if (hold != nil) != tt.wantHold {
+				t.Fatalf("Check() hold = %+v, want one: %v", hold, tt.wantHold)
+			}
+
+			// Releasing hands back only a solution that was used up
+			hold.Release(context.Background())
+			if tt.wantHold && (len(verifier.released) != 1 || verifier.released[0] != tt.solution) {
+				t.Errorf("released %+v, want %+v", verifier.released, tt.solution)
+			}
+			if !tt.wantHold && len(verifier.released) != 0 {
+				t.Errorf("released %+v without a hold", verifier.released)
+			}
+		})
+	}
+
+	t.Run("window", func(t *testing.T) {
+		repo := &mockChallengeRepository{}
+		service := NewChallengeService(repo, &mockIPHasher{}, &mockChallengeVerifier{}, ChallengeSettings{Threshold: 3}, logger).(*challengeService)
+		now := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+		service.now = func() time.Time { return now }
+
+		if _, _, err := service.Check(context.Background(), event, browser, ChallengeSolution{}); err != nil {
+			t.Fatalf("Check() error = %v", err)
+		}
+		if want := now.Add(-DefaultChallengeWindow); !repo.since.Equal(want) {
+			t.Errorf("counted votes since %v, want %v", repo.since, want)
+		}
+	})
+
+	t.Run("no verifier", func(t *testing.T) {
+		service := NewChallengeService(&mockChallengeRepository{}, &mockIPHasher{}, nil, ChallengeSettings{}, logger)
+		if challenge, hold, err := service.Check(context.Background(), event, script, ChallengeSolution{}); challenge != nil || hold != nil || err != nil {
+			t.Errorf("Check() = %+v, %+v, %v, want challenges off", challenge, hold, err)
+		}
+	})
+}
+
+func TestScriptedUserAgent(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		want      bool
+	}{
+		{"", true},
+		{"curl/8.4.0", true},
+		{"Go-http-client/1.1", true},
+		{"Mozilla/5.0 (X11; Linux x86_64) HeadlessChrome/120.0", true},
+		{browserUserAgent, false},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 Mobile/15E148", false},
+	}
+
+	for _, tt := range tests {
+		if got := scriptedUserAgent(tt.userAgent); got != tt.want {
+			t.Errorf("scriptedUserAgent(%q) = %v, want %v", tt.userAgent, got, tt.want)
+		}
+	}
+}
+
+func TestProofOfWork(t *testing.T) {
+	ctx := context.Background()
+	repo := &mockChallengeRepository{}
+	signer := util.NewTokenSigner("test-secret")
+	pow := NewProofOfWork(repo, signer, 8, time.Minute)
+	now := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	pow.now = func() time.Time { return now }
+
+	const clientIP = "203.0.113.7"
+	challenge, err := pow.Issue(ctx, clientIP)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	if challenge.Kind != ChallengeProofOfWork || challenge.Difficulty != 8 {
+		t.Fatalf("Issue() = %+v, want an 8-bit proof of work", challenge)
+	}
+
+	answer := solvePoW(challenge.Token, challenge.Difficulty)
+
+	wrong := 0
+	for leadingZeroBits(sha256.Sum256([]byte(challenge.Token+":"+strconv.Itoa(wrong)))) >= 8 {
+		wrong++
+	}
+	if err := pow.Verify(ctx, ChallengeSolution{Token: challenge.Token, Answer: strconv.Itoa(wrong)}, clientIP); !errors.Is(err, ErrChallengeFailed) {
+		t.Errorf("Verify(wrong answer) error = %v, want ErrChallengeFailed", err)
+	}
+	if err := pow.Verify(ctx, ChallengeSolution{Token: challenge.Token + "x", Answer: answer}, clientIP); !errors.Is(err, ErrChallengeFailed) {
+		t.Errorf("Verify(tampered token) error = %v, want ErrChallengeFailed", err)
+	}
+
+	if err := pow.Verify(ctx, ChallengeSolution{Token: challenge.Token, Answer: answer}, "198.51.100.9"); !errors.Is(err, ErrChallengeFailed) {
+		t.Errorf("Verify(another client) error = %v, want ErrChallengeFailed", err)
+	}
+
+	if err := pow.Verify(ctx, ChallengeSolution{Token: challenge.Token, Answer: answer}, clientIP); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if err := pow.Verify(ctx, ChallengeSolution{Token: challenge.Token, Answer: answer}, clientIP); !errors.Is(err, ErrChallengeFailed) {
+		t.Errorf("Verify(reused solution) error = %v, want ErrChallengeFailed", err)
+	}
+
+	// Another replica sharing the repository rejects the replay too
+	replica := NewProofOfWork(repo, signer, 8, time.Minute)
+	replica.now = pow.now
+	if err := replica.Verify(ctx, ChallengeSolution{Token: challenge.Token, Answer: answer}, clientIP); !errors.Is(err, ErrChallengeFailed) {
+		t.Errorf("Verify(replayed on another replica) error = %v, want ErrChallengeFailed", err)
+	}
+	if expiresAt := repo.used[string(sha256Sum(challenge.Token))]; !expiresAt.Equal(challenge.ExpiresAt.Truncate(time.Second)) {
+		t.Errorf("solved token kept until %v, want %v", expiresAt, challenge.ExpiresAt)
+	}
+
+	// A released solution verifies once more
+	if err := pow.Release(ctx, ChallengeSolution{Token: challenge.Token, Answer: answer}); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if err := pow.Verify(ctx, ChallengeSolution{Token: challenge.Token, Answer: answer}, clientIP); err != nil {
+		t.Errorf("Verify(released solution) error = %v", err)
+	}
+
+	expired, _ := pow.Issue(ctx, clientIP)
+	answer = solvePoW(expired.Token, expired.Difficulty)
+	now = now.Add(2 * time.Minute)
+	if err := pow.Verify(ctx, ChallengeSolution{Token: expired.Token, Answer: answer}, clientIP); !errors.Is(err, ErrChallengeFailed) {
+		t.Errorf("Verify(expired) error = %v, want ErrChallengeFailed", err)
+	}
+
+	if len(repo.used) != 1 {
+		t.Errorf("%d solved tokens recorded, want only the first", len(repo.used))
+	}
+}
+
+func sha256Sum(s string) []byte {
+	sum := sha256.Sum256([]byte(s))
+	return sum[:]
+}
+
+func solvePoW(token string, difficulty int) string {
+	for answer := 0; ; answer++ {
+		if leadingZeroBits(sha256.Sum256([]byte(token+":"+strconv.Itoa(answer)))) >= difficulty {
+			return strconv.Itoa(answer)
+		}
+	}
+}
@@ -3,14 +3,61 @@ package domain
 import "errors"
 
 var (
+	// ErrEventNotFound is returned when an event is not found
+	ErrEventNotFound = errors.New("event not found")
+
+	// ErrGroupNotFound is returned when a group is not found
+	ErrGroupNotFound = errors.New("group not found")
+
 	// ErrInnovationNotFound is returned when an innovation is not found
 	ErrInnovationNotFound = errors.New("innovation not found")
 
 	// ErrAlreadyVoted is returned when a user has already voted for an innovation
 	ErrAlreadyVoted = errors.New("already voted for this innovation")
 
+	// ErrSlugTaken is returned when a slug is already used within a group
+	ErrSlugTaken = errors.New("slug already used in group")
+
 	// ErrInvalidInput is returned when input validation fails
 	ErrInvalidInput = errors.New("invalid input")
-)
 
+	// ErrInvalidCredentials is returned when an admin login is rejected
+	ErrInvalidCredentials = errors.New("invalid credentials")
+
+	// ErrSessionNotFound is returned when an admin session is unknown, expired or revoked
+	ErrSessionNotFound = errors.New("session not found")
+
+	// ErrAdminUserNotFound is returned when an admin account is not found
+	ErrAdminUserNotFound = errors.New("admin user not found")
+
+	// ErrUsernameTaken is returned when an admin username is already used
+	ErrUsernameTaken = errors.New("username already used")
+
+	// ErrLastSuperadmin is returned when a change would leave no active superadmin
+	ErrLastSuperadmin = errors.New("at least one active superadmin is required")
 
+	// ErrVotingClosed is returned when a vote is submitted outside the voting window
+	ErrVotingClosed = errors.New("voting is closed")
+
+	// ErrVerificationRequired is returned when an event needs a verified voter contact
+	ErrVerificationRequired = errors.New("voter verification required")
+
+	// ErrInvalidCode is returned when a one-time code is wrong, used or expired
+	ErrInvalidCode = errors.New("invalid or expired verification code")
+
+	// ErrTooManyAttempts is returned when a one-time code was guessed wrong too often
+	ErrTooManyAttempts = errors.New("too many verification attempts")
+
+	// ErrCodeRequestLimited is returned when codes are requested too often for a contact
+	ErrCodeRequestLimited = errors.New("verification code requested too often")
+
+	// ErrIPVoteCapReached is returned when an IP hash has cast as many votes in a
+	// scope as the voter dedupe allows
+	ErrIPVoteCapReached = errors.New("ip vote cap reached")
+
+	// ErrChallengeRequired is returned when a vote needs a solved challenge
+	ErrChallengeRequired = errors.New("challenge required")
+
+	// ErrChallengeFailed is returned when a challenge solution is wrong, expired or reused
+	ErrChallengeFailed = errors.New("challenge failed")
+)
@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// Export reports
+const (
+	// ReportTotals lists every active innovation with its vote count
+	ReportTotals = "totals"
+	// ReportRankings lists the per-group rankings
+	ReportRankings = "rankings"
+	// ReportVotes lists every raw vote, invalidated ones included
+	ReportVotes = "votes"
+)
+
+// RowWriter receives the rows of an export report one at a time. The CSV and
+// XLSX writers live outside the domain; the caller flushes and closes them.
+type RowWriter interface {
+	WriteRow(values ...any) error
+}
+
+// exportYes marks yes/no columns such as ties and invalidated votes
+const exportYes = "Ya"
+
+// shortIPHashBytes is how much of the voter hash exports reveal: enough to
+// spot repeated voters, too little to match against other data
+const shortIPHashBytes = 6
+
+// VoteRecord is one raw vote with its innovation, as exported to organisers.
+// Invalidated votes are exported but do not count.
+type VoteRecord struct {
+	ID             int64
+	InnovationID   string
+	GroupSlug      string
+	InnovationSlug string
+	InnovationName string
+	CreatedAt      time.Time
+	VoterIPHash    []byte
+	UserAgent      string
+	Invalidated    bool
+}
+
+// ShortIPHash returns the leading bytes of the voter IP hash in hex
+func (r *VoteRecord) ShortIPHash() string {
+	return ShortHash(r.VoterIPHash)
+}
+
+// ShortHash returns the leading bytes of a voter hash in hex
+func ShortHash(hash []byte) string {
+	if len(hash) > shortIPHashBytes {
+		hash = hash[:shortIPHashBytes]
+	}
+	return hex.EncodeToString(hash)
+}
+
+func (s *voteService) StreamVotes(ctx context.Context, eventID string, fn func(*VoteRecord) error) error {
+	if err := s.repo.StreamVotes(ctx, eventID, fn); err != nil {
+		s.logger.ErrorContext(ctx, "failed to stream votes",
+			"event_id", eventID,
+			"error", err)
+		return err
+	}
+	return nil
+}
+
+// WriteReport writes one of the export reports of the event to w. Raw votes
+// are streamed from the database row by row. The caller closes w.
+func WriteReport(ctx context.Context, service VoteService, eventID, report string, w RowWriter) error {
+	switch report {
+	case ReportTotals:
+		return writeTotals(ctx, service, eventID, w)
+	case ReportRankings:
+		return writeRankings(ctx, service, eventID, w)
+	case ReportVotes:
+		return writeVotes(ctx, service, eventID, w)
+	default:
+		return fmt.Errorf("%w: unknown report %q", ErrInvalidInput, report)
+	}
+}
+
+func writeTotals(ctx context.Context, service VoteService, eventID string, w RowWriter) error {
+	results, err := service.GetResults(ctx, eventID, "")
+	if err != nil {
+		return err
+	}
+
+	groupNames := make(map[string]string, len(results.Groups))
+	for _, group := range results.Groups {
+		groupNames[group.GroupSlug] = group.GroupName
+	}
+
+	if err := w.WriteRow("Kategori", "Slug Kategori", "Inovasi", "Slug", "Divisi", "Entitas",
+		"Jumlah Suara", "Porsi Total (%)"); err != nil {
+		return err
+	}
+	for _, result := range results.Innovations {
+		var share float64
+		if results.TotalVotes > 0 {
+			share = float64(result.VoteCount) / float64(results.TotalVotes) * 100
+		}
+		if err := w.WriteRow(groupNames[result.GroupSlug], result.GroupSlug, result.Name, result.Slug,
+			derefString(result.Division), derefString(result.EntityName), result.VoteCount, share); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeRankings(ctx context.Context, service VoteService, eventID string, w RowWriter) error {
+	results, err := service.GetResults(ctx, eventID, "")
+	if err != nil {
+		return err
+	}
+
+	if err := w.WriteRow("Kategori", "Peringkat", "Seri", "Inovasi", "Slug", "Divisi", "Entitas",
+		"Jumlah Suara", "Porsi Kategori (%)", "Selisih ke Atas"); err != nil {
+		return err
+	}
+	for _, group := range results.Groups {
+		for _, entry := range group.Entries {
+			tied := ""
+			if entry.Tied {
+				tied = exportYes
+			}
+			if err := w.WriteRow(group.GroupName, entry.Rank, tied, entry.Name, entry.Slug,
+				derefString(entry.Division), derefString(entry.EntityName),
+				entry.VoteCount, entry.GroupShare, entry.GapToNext); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func writeVotes(ctx context.Context, service VoteService, eventID string, w RowWriter) error {
+	if err := w.WriteRow("ID", "Kategori", "Inovasi", "Slug", "ID Inovasi", "Waktu (UTC)",
+		"Hash IP (dipotong)", "User Agent", "Dibatalkan"); err != nil {
+		return err
+	}
+	return service.StreamVotes(ctx, eventID, func(vote *VoteRecord) error {
+		invalidated := ""
+		if vote.Invalidated {
+			invalidated = exportYes
+		}
+		return w.WriteRow(vote.ID, vote.GroupSlug, vote.InnovationName, vote.InnovationSlug,
+			vote.InnovationID, vote.CreatedAt, vote.ShortIPHash(), vote.UserAgent, invalidated)
+	})
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
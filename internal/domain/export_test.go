@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+)
+
+// rowRecorder is a RowWriter that keeps the rows it receives
+type rowRecorder struct {
+	rows [][]any
+}
+
+func (r *rowRecorder) WriteRow(values ...any) error {
+	r.rows = append(r.rows, values)
+	return nil
+}
+
+func TestVoteRecord_ShortIPHash(t *testing.T) {
+	tests := []struct {
+		name string
+		hash []byte
+		want string
+	}{
+		{"truncated", []byte{0xde, 0xad, 0xbe, 0xef, 0x00, 0x11, 0x22, 0x33}, "deadbeef0011"},
+		{"short hash kept", []byte{0xab}, "ab"},
+		{"empty", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := &VoteRecord{VoterIPHash: tt.hash}
+			if got := record.ShortIPHash(); got != tt.want {
+				t.Errorf("ShortIPHash() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVoteService_StreamVotes(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	repo := newMockRepository()
+	service := NewVoteService(repo, &mockIPHasher{}, &mockAuditRecorder{}, &mockVotePublisher{}, VoterDedupe{}, logger)
+
+	repo.addInnovation(&Innovation{ID: "a", GroupSlug: "g1", Slug: "a", Name: "A"})
+	repo.votes = append(repo.votes,
+		&Vote{EventID: testEventID, InnovationID: "a", VoterIPHash: []byte("10.0.0.1")},
+		&Vote{EventID: "other-event", InnovationID: "a", VoterIPHash: []byte("10.0.0.2")},
+	)
+
+	var records []*VoteRecord
+	err := service.StreamVotes(context.Background(), testEventID, func(record *VoteRecord) error {
+		records = append(records, record)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamVotes() error = %v", err)
+	}
+	if len(records) != 1 || records[0].InnovationName != "A" || records[0].GroupSlug != "g1" {
+		t.Errorf("records = %d, want the one vote of the event with its innovation", len(records))
+	}
+}
+
+func TestWriteReport_UnknownReport(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	service := NewVoteService(newMockRepository(), &mockIPHasher{}, &mockAuditRecorder{}, &mockVotePublisher{}, VoterDedupe{}, logger)
+
+	var rows rowRecorder
+	err := WriteReport(context.Background(), service, testEventID, "summary", &rows)
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("WriteReport() error = %v, want ErrInvalidInput", err)
+	}
+	if len(rows.rows) != 0 {
+		t.Errorf("WriteReport() wrote %v for an unknown report", rows.rows)
+	}
+}
@@ -0,0 +1,462 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultFlaggedPageSize = 50
+	maxFlaggedPageSize     = 500
+	maxReviewBatch         = 500
+)
+
+// Fraud rules a vote can be flagged by
+const (
+	// FlagBurst marks votes for one innovation cast within seconds of each other
+	FlagBurst = "burst"
+	// FlagRareUserAgent marks clusters of votes sharing an uncommon user agent
+	FlagRareUserAgent = "rare-user-agent"
+	// FlagHeadless marks votes from headless or automated browsers
+	FlagHeadless = "headless"
+	// FlagSubnet marks votes from a /24 or /64 that cast many of them
+	FlagSubnet = "subnet"
+)
+
+// Review states of flagged votes
+const (
+	// FlagStatusPending selects flagged votes nobody has reviewed yet
+	FlagStatusPending = "pending"
+	// FlagStatusInvalidated selects votes excluded from the counts
+	FlagStatusInvalidated = "invalidated"
+	// FlagStatusKept selects reviewed votes that still count
+	FlagStatusKept = "kept"
+	// FlagStatusAll selects every flagged vote
+	FlagStatusAll = "all"
+)
+
+// FraudSettings tunes the analyser. A rule fires when at least its size of
+// votes fall within its window.
+type FraudSettings struct {
+	// Interval is how often Run analyses every event; 0 disables Run
+	Interval time.Duration
+
+	BurstWindow time.Duration
+	BurstSize   int
+
+	// A user agent is rare when at most RareUserAgentShare of the analysed
+	// votes use it
+	RareUserAgentShare   float64
+	RareUserAgentWindow  time.Duration
+	RareUserAgentCluster int
+
+	SubnetWindow time.Duration
+	SubnetSize   int
+}
+
+// DefaultFraudSettings are used for the rule settings left at zero
+var DefaultFraudSettings = FraudSettings{
+	Interval:             time.Minute,
+	BurstWindow:          10 * time.Second,
+	BurstSize:            5,
+	RareUserAgentShare:   0.05,
+	RareUserAgentWindow:  10 * time.Minute,
+	RareUserAgentCluster: 5,
+	SubnetWindow:         time.Hour,
+	SubnetSize:           30,
+}
+
+func (s FraudSettings) withDefaults() FraudSettings {
+	d := DefaultFraudSettings
+	if s.BurstWindow <= 0 {
+		s.BurstWindow = d.BurstWindow
+	}
+	if s.BurstSize <= 0 {
+		s.BurstSize = d.BurstSize
+	}
+	if s.RareUserAgentShare <= 0 {
+		s.RareUserAgentShare = d.RareUserAgentShare
+	}
+	if s.RareUserAgentWindow <= 0 {
+		s.RareUserAgentWindow = d.RareUserAgentWindow
+	}
+	if s.RareUserAgentCluster <= 0 {
+		s.RareUserAgentCluster = d.RareUserAgentCluster
+	}
+	if s.SubnetWindow <= 0 {
+		s.SubnetWindow = d.SubnetWindow
+	}
+	if s.SubnetSize <= 0 {
+		s.SubnetSize = d.SubnetSize
+	}
+	return s
+}
+
+// lookback is how far back each run reads votes: the widest window plus two
+// intervals, so clusters straddling runs are still seen whole
+func (s FraudSettings) lookback() time.Duration {
+	widest := s.BurstWindow
+	for _, w := range []time.Duration{s.RareUserAgentWindow, s.SubnetWindow} {
+		if w > widest {
+			widest = w
+		}
+	}
+	return widest + 2*s.Interval
+}
+
+// VoteSample is the part of a vote the analyser looks at
+type VoteSample struct {
+	ID           int64
+	InnovationID string
+	CreatedAt    time.Time
+	SubnetHash   []byte
+	UserAgent    string
+}
+
+// VoteFlag records why a vote looks suspicious
+type VoteFlag struct {
+	VoteID    int64     `json:"vote_id"`
+	EventID   string    `json:"event_id"`
+	Rule      string    `json:"rule"`
+	Detail    string    `json:"detail"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// FlagSummary is one flag of a flagged vote
+type FlagSummary struct {
+	Rule   string `json:"rule"`
+	Detail string `json:"detail"`
+}
+
+// FlaggedVote is a vote in the review queue
+type FlaggedVote struct {
+	ID                 int64         `json:"id"`
+	InnovationID       string        `json:"innovation_id"`
+	GroupSlug          string        `json:"group_slug"`
+	InnovationName     string        `json:"innovation_name"`
+	CreatedAt          time.Time     `json:"created_at"`
+	UserAgent          string        `json:"user_agent"`
+	ShortIPHash        string        `json:"ip_hash"`
+	Flags              []FlagSummary `json:"flags"`
+	ReviewedAt         *time.Time    `json:"reviewed_at,omitempty"`
+	InvalidatedAt      *time.Time    `json:"invalidated_at,omitempty"`
+	InvalidationReason string        `json:"invalidation_reason,omitempty"`
+}
+
+// FlaggedVoteFilter selects flagged votes of an event. Pages are keyed by
+// vote ID, newest first.
+type FlaggedVoteFilter struct {
+	EventID string
+	// Status is one of the FlagStatus values; empty means pending
+	Status   string
+	Rule     string
+	BeforeID int64
+	Limit    int
+}
+
+// FlaggedVotePage is one page of the review queue
+type FlaggedVotePage struct {
+	Votes []*FlaggedVote `json:"votes"`
+	// NextBeforeID is passed as BeforeID to fetch the next page; 0 on the last page
+	NextBeforeID int64 `json:"next_before_id"`
+}
+
+// FraudRepository stores vote flags and review decisions
+type FraudRepository interface {
+	ListEvents(ctx context.Context) ([]*Event, error)
+	// ListVoteSamples returns the event's votes cast since the given time, oldest first
+	ListVoteSamples(ctx context.Context, eventID string, since time.Time) ([]*VoteSample, error)
+	// InsertVoteFlags stores flags, skipping those already stored, and
+	// returns how many were new
+	InsertVoteFlags(ctx context.Context, flags []*VoteFlag) (int, error)
+	ListFlaggedVotes(ctx context.Context, filter FlaggedVoteFilter) ([]*FlaggedVote, error)
+	// ReviewVotes marks votes of the event reviewed and invalidated (with
+	// reason) or valid, and returns how many votes it changed
+	ReviewVotes(ctx context.Context, eventID string, ids []int64, invalidate bool, reason string, at time.Time) (int, error)
+}
+
+// FraudService flags suspicious votes and lets admins review them.
+// Invalidated votes stay stored but no longer count.
+type FraudService interface {
+	// Analyse flags the event's recent votes and returns how many flags are new
+	Analyse(ctx context.Context, eventID string) (int, error)
+	// Run analyses every event each interval until ctx is done
+	Run(ctx context.Context)
+	ListFlaggedVotes(ctx context.Context, filter FlaggedVoteFilter) (*FlaggedVotePage, error)
+	InvalidateVotes(ctx context.Context, eventID string, ids []int64, reason string) (int, error)
+	RestoreVotes(ctx context.Context, eventID string, ids []int64) (int, error)
+}
+
+type fraudService struct {
+	repo     FraudRepository
+	audit    AuditRecorder
+	settings FraudSettings
+	logger   *slog.Logger
+	now      func() time.Time
+}
+
+// NewFraudService creates a new FraudService
+func NewFraudService(repo FraudRepository, audit AuditRecorder, settings FraudSettings, logger *slog.Logger) FraudService {
+	return &fraudService{
+		repo:     repo,
+		audit:    audit,
+		settings: settings.withDefaults(),
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+func (s *fraudService) Analyse(ctx context.Context, eventID string) (int, error) {
+	samples, err := s.repo.ListVoteSamples(ctx, eventID, s.now().Add(-s.settings.lookback()))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to load votes for analysis",
+			"event_id", eventID,
+			"error", err)
+		return 0, err
+	}
+
+	flags := DetectVoteFlags(samples, s.settings)
+	if len(flags) == 0 {
+		return 0, nil
+	}
+	now := s.now()
+	for _, flag := range flags {
+		flag.EventID = eventID
+		flag.CreatedAt = now
+	}
+
+	added, err := s.repo.InsertVoteFlags(ctx, flags)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to store vote flags",
+			"event_id", eventID,
+			"error", err)
+		return 0, err
+	}
+	if added > 0 {
+		s.logger.WarnContext(ctx, "suspicious votes flagged",
+			"event_id", eventID,
+			"new_flags", added)
+	}
+	return added, nil
+}
+
+func (s *fraudService) Run(ctx context.Context) {
+	if s.settings.Interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.settings.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		events, err := s.repo.ListEvents(ctx)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "failed to list events for analysis", "error", err)
+			continue
+		}
+		for _, event := range events {
+			// Errors are logged by Analyse; the other events still run
+			_, _ = s.Analyse(ctx, event.ID)
+		}
+	}
+}
+
+func (s *fraudService) ListFlaggedVotes(ctx context.Context, filter FlaggedVoteFilter) (*FlaggedVotePage, error) {
+	switch filter.Status {
+	case "":
+		filter.Status = FlagStatusPending
+	case FlagStatusPending, FlagStatusInvalidated, FlagStatusKept, FlagStatusAll:
+	default:
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidInput, filter.Status)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultFlaggedPageSize
+	}
+	if filter.Limit > maxFlaggedPageSize {
+		filter.Limit = maxFlaggedPageSize
+	}
+
+	// Fetch one extra vote to learn whether another page follows
+	limit := filter.Limit
+	filter.Limit++
+	votes, err := s.repo.ListFlaggedVotes(ctx, filter)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to list flagged votes",
+			"event_id", filter.EventID,
+			"error", err)
+		return nil, err
+	}
+
+	page := &FlaggedVotePage{Votes: votes}
+	if len(votes) > limit {
+		page.Votes = votes[:limit]
+		page.NextBeforeID = page.Votes[limit-1].ID
+	}
+	if page.Votes == nil {
+		page.Votes = []*FlaggedVote{}
+	}
+	return page, nil
+}
+
+func (s *fraudService) InvalidateVotes(ctx context.Context, eventID string, ids []int64, reason string) (int, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return 0, fmt.Errorf("%w: a reason is required", ErrInvalidInput)
+	}
+	return s.review(ctx, eventID, ids, true, reason)
+}
+
+func (s *fraudService) RestoreVotes(ctx context.Context, eventID string, ids []int64) (int, error) {
+	return s.review(ctx, eventID, ids, false, "")
+}
+
+func (s *fraudService) review(ctx context.Context, eventID string, ids []int64, invalidate bool, reason string) (int, error) {
+	if len(ids) == 0 {
+		return 0, fmt.Errorf("%w: no votes selected", ErrInvalidInput)
+	}
+	if len(ids) > maxReviewBatch {
+		return 0, fmt.Errorf("%w: at most %d votes at once", ErrInvalidInput, maxReviewBatch)
+	}
+
+	changed, err := s.repo.ReviewVotes(ctx, eventID, ids, invalidate, reason, s.now())
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to review votes",
+			"event_id", eventID,
+			"invalidate", invalidate,
+			"error", err)
+		return 0, err
+	}
+
+	action := AuditVotesRestored
+	if invalidate {
+		action = AuditVotesInvalidated
+	}
+	s.audit.Record(ctx, action, "event", eventID, nil, map[string]any{
+		"vote_ids": ids,
+		"reason":   reason,
+		"changed":  changed,
+	})
+	return changed, nil
+}
+
+// DetectVoteFlags applies every rule to samples, which must be sorted by time
+func DetectVoteFlags(samples []*VoteSample, settings FraudSettings) []*VoteFlag {
+	settings = settings.withDefaults()
+
+	var flags []*VoteFlag
+	add := func(rule string, marked map[int64]string) {
+		ids := make([]int64, 0, len(marked))
+		for id := range marked {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		for _, id := range ids {
+			flags = append(flags, &VoteFlag{VoteID: id, Rule: rule, Detail: marked[id]})
+		}
+	}
+
+	// Bursts for one innovation
+	byInnovation := groupSamples(samples, func(v *VoteSample) string { return v.InnovationID })
+	add(FlagBurst, markClusters(byInnovation, settings.BurstWindow, settings.BurstSize, "votes for one innovation"))
+
+	// Clusters of identical, uncommon user agents
+	byUserAgent := groupSamples(samples, func(v *VoteSample) string { return v.UserAgent })
+	for userAgent, group := range byUserAgent {
+		if userAgent == "" || float64(len(group)) > settings.RareUserAgentShare*float64(len(samples)) {
+			delete(byUserAgent, userAgent)
+		}
+	}
+	add(FlagRareUserAgent, markClusters(byUserAgent, settings.RareUserAgentWindow, settings.RareUserAgentCluster, "votes with the same rare user agent"))
+
+	// Headless and automated browsers
+	headless := make(map[int64]string)
+	for _, v := range samples {
+		if fragment := headlessUserAgent(v.UserAgent); fragment != "" {
+			headless[v.ID] = "user agent contains " + fragment
+		}
+	}
+	add(FlagHeadless, headless)
+
+	// Many votes from one subnet
+	bySubnet := groupSamples(samples, func(v *VoteSample) string { return string(v.SubnetHash) })
+	delete(bySubnet, "")
+	add(FlagSubnet, markClusters(bySubnet, settings.SubnetWindow, settings.SubnetSize, "votes from one subnet"))
+
+	return flags
+}
+
+func groupSamples(samples []*VoteSample, key func(*VoteSample) string) map[string][]*VoteSample {
+	groups := make(map[string][]*VoteSample)
+	for _, v := range samples {
+		k := key(v)
+		groups[k] = append(groups[k], v)
+	}
+	return groups
+}
+
+// markClusters marks every vote that lies in a window holding at least size
+// votes of its group, with the largest such count in the detail
+func markClusters(groups map[string][]*VoteSample, window time.Duration, size int, what string) map[int64]string {
+	largest := make(map[int64]int)
+	for _, group := range groups {
+		start := 0
+		for end := range group {
+			for group[end].CreatedAt.Sub(group[start].CreatedAt) > window {
+				start++
+			}
+			count := end - start + 1
+			if count < size {
+				continue
+			}
+			for _, v := range group[start : end+1] {
+				if count > largest[v.ID] {
+					largest[v.ID] = count
+				}
+			}
+		}
+	}
+
+	marked := make(map[int64]string, len(largest))
+	for id, count := range largest {
+		marked[id] = fmt.Sprintf("%d %s within %s", count, what, shortDuration(window))
+	}
+	return marked
+}
+
+// shortDuration formats d without trailing zero units, e.g. 1h instead of 1h0m0s
+func shortDuration(d time.Duration) string {
+	text := d.String()
+	if strings.HasSuffix(text, "m0s") {
+		text = strings.TrimSuffix(text, "0s")
+	}
+	if strings.HasSuffix(text, "h0m") {
+		text = strings.TrimSuffix(text, "0m")
+	}
+	return text
+}
+
+// headlessUserAgents are user agent fragments of automated browsers
+var headlessUserAgents = []string{
+	"headless", "phantomjs", "selenium", "webdriver", "puppeteer", "playwright",
+}
+
+// headlessUserAgent returns the automation fragment found in userAgent, if any
+func headlessUserAgent(userAgent string) string {
+	userAgent = strings.ToLower(userAgent)
+	for _, fragment := range headlessUserAgents {
+		if strings.Contains(userAgent, fragment) {
+			return fragment
+		}
+	}
+	return ""
+}
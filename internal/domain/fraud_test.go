@@ -0,0 +1,285 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// Mock fraud repository; ReviewVotes changes every listed vote
+type mockFraudRepository struct {
+	samples  []*VoteSample
+	flags    map[string]*VoteFlag
+	flagged  []*FlaggedVote
+	filter   FlaggedVoteFilter
+	reviewed []int64
+	reason   string
+}
+
+func (m *mockFraudRepository) ListEvents(ctx context.Context) ([]*Event, error) {
+	return []*Event{{ID: testEventID}}, nil
+}
+
+func (m *mockFraudRepository) ListVoteSamples(ctx context.Context, eventID string, since time.Time) ([]*VoteSample, error) {
+	var samples []*VoteSample
+	for _, v := range m.samples {
+		if !v.CreatedAt.Before(since) {
+			samples = append(samples, v)
+		}
+	}
+	return samples, nil
+}
+
+func (m *mockFraudRepository) InsertVoteFlags(ctx context.Context, flags []*VoteFlag) (int, error) {
+	if m.flags == nil {
+		m.flags = make(map[string]*VoteFlag)
+	}
+	added := 0
+	for _, flag := range flags {
+		key := strconv.FormatInt(flag.VoteID, 10) + "/" + flag.Rule
+		if _, ok := m.flags[key]; !ok {
+			m.flags[key] = flag
+			added++
+		}
+	}
+	return added, nil
+}
+
+func (m *mockFraudRepository) ListFlaggedVotes(ctx context.Context, filter FlaggedVoteFilter) ([]*FlaggedVote, error) {
+	m.filter = filter
+	var votes []*FlaggedVote
+	for _, v := range m.flagged {
+		if filter.BeforeID > 0 && v.ID >= filter.BeforeID {
+			continue
+		}
+		if len(votes) == filter.Limit {
+			break
+		}
+		votes = append(votes, v)
+	}
+	return votes, nil
+}
+
+func (m *mockFraudRepository) ReviewVotes(ctx context.Context, eventID string, ids []int64, invalidate bool, reason string, at time.Time) (int, error) {
+	m.reviewed = ids
+	m.reason = reason
+	return len(ids), nil
+}
+
+var fraudStart = time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+
+// sample builds a vote cast offset after fraudStart
+func sample(id int64, innovationID string, offset time.Duration, subnet, userAgent string) *VoteSample {
+	return &VoteSample{
+		ID:           id,
+		InnovationID: innovationID,
+		CreatedAt:    fraudStart.Add(offset),
+		SubnetHash:   []byte(subnet),
+		UserAgent:    userAgent,
+	}
+}
+
+// flaggedIDs returns the vote IDs flagged by rule
+func flaggedIDs(flags []*VoteFlag, rule string) []int64 {
+	var ids []int64
+	for _, flag := range flags {
+		if flag.Rule == rule {
+			ids = append(ids, flag.VoteID)
+		}
+	}
+	return ids
+}
+
+func equalIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestDetectVoteFlags(t *testing.T) {
+	settings := FraudSettings{BurstWindow: 10 * time.Second, BurstSize: 3, SubnetWindow: time.Hour, SubnetSize: 4}
+
+	t.Run("burst", func(t *testing.T) {
+		samples := []*VoteSample{
+			sample(1, "inn-a", 0, "a", browserUserAgent),
+			sample(2, "inn-a", 4*time.Second, "b", browserUserAgent),
+			sample(3, "inn-b", 5*time.Second, "c", browserUserAgent),
+			sample(4, "inn-a", 9*time.Second, "d", browserUserAgent),
+			sample(5, "inn-a", 30*time.Second, "e", browserUserAgent),
+		}
+		flags := DetectVoteFlags(samples, settings)
+
+		if got := flaggedIDs(flags, FlagBurst); !equalIDs(got, []int64{1, 2, 4}) {
+			t.Errorf("burst flagged %v, want [1 2 4]", got)
+		}
+		for _, flag := range flags {
+			if flag.Rule == FlagBurst && flag.Detail != "3 votes for one innovation within 10s" {
+				t.Errorf("burst detail = %q", flag.Detail)
+			}
+		}
+	})
+
+	t.Run("rare user agent", func(t *testing.T) {
+		var samples []*VoteSample
+		for i := 0; i < 100; i++ {
+			samples = append(samples, sample(int64(i+1), "inn-"+strconv.Itoa(i), time.Duration(i)*time.Second, strconv.Itoa(i), browserUserAgent))
+		}
+		// Five votes with one odd user agent in a minute, and one straggler
+		for i := 0; i < 5; i++ {
+			samples = append(samples, sample(int64(200+i), "inn-x"+strconv.Itoa(i), 100*time.Second+time.Duration(i)*10*time.Second, "r"+strconv.Itoa(i), "OddBrowser/1.0"))
+		}
+		samples = append(samples, sample(300, "inn-y", time.Hour, "s", "OtherBrowser/2.0"))
+
+		got := flaggedIDs(DetectVoteFlags(samples, settings), FlagRareUserAgent)
+		if !equalIDs(got, []int64{200, 201, 202, 203, 204}) {
+			t.Errorf("rare user agent flagged %v, want [200 201 202 203 204]", got)
+		}
+	})
+
+	t.Run("headless", func(t *testing.T) {
+		samples := []*VoteSample{
+			sample(1, "inn-a", 0, "a", "Mozilla/5.0 (X11; Linux x86_64) HeadlessChrome/120.0"),
+			sample(2, "inn-b", time.Minute, "b", browserUserAgent),
+		}
+		flags := DetectVoteFlags(samples, settings)
+
+		if got := flaggedIDs(flags, FlagHeadless); !equalIDs(got, []int64{1}) {
+			t.Errorf("headless flagged %v, want [1]", got)
+		}
+	})
+
+	t.Run("subnet", func(t *testing.T) {
+		var samples []*VoteSample
+		for i := 0; i < 4; i++ {
+			samples = append(samples, sample(int64(i+1), "inn-"+strconv.Itoa(i), time.Duration(i)*10*time.Minute, "campus", browserUserAgent))
+		}
+		samples = append(samples, sample(5, "inn-z", 2*time.Hour, "campus", browserUserAgent))
+		samples = append(samples, sample(6, "inn-z", 2*time.Hour, "", browserUserAgent))
+
+		got := flaggedIDs(DetectVoteFlags(samples, settings), FlagSubnet)
+		if !equalIDs(got, []int64{1, 2, 3, 4}) {
+			t.Errorf("subnet flagged %v, want [1 2 3 4]", got)
+		}
+	})
+
+	t.Run("quiet event", func(t *testing.T) {
+		samples := []*VoteSample{
+			sample(1, "inn-a", 0, "a", browserUserAgent),
+			sample(2, "inn-b", time.Minute, "b", browserUserAgent),
+		}
+		if flags := DetectVoteFlags(samples, settings); len(flags) != 0 {
+			t.Errorf("DetectVoteFlags() = %d flags, want none", len(flags))
+		}
+	})
+}
+
+func TestFraudService_Analyse(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	repo := &mockFraudRepository{}
+	for i := 0; i < 5; i++ {
+		repo.samples = append(repo.samples, sample(int64(i+1), "inn-a", time.Duration(i)*time.Second, strconv.Itoa(i), browserUserAgent))
+	}
+	// Too old for the lookback
+	repo.samples = append(repo.samples, sample(99, "inn-b", -3*time.Hour, "old", "HeadlessChrome"))
+
+	service := NewFraudService(repo, &mockAuditRecorder{}, FraudSettings{}, logger).(*fraudService)
+	service.now = func() time.Time { return fraudStart.Add(time.Minute) }
+
+	added, err := service.Analyse(context.Background(), testEventID)
+	if err != nil {
+		t.Fatalf("Analyse() error = %v", err)
+	}
+	if added != 5 {
+		t.Errorf("Analyse() = %d new flags, want 5", added)
+	}
+	for _, flag := range repo.flags {
+		if flag.EventID != testEventID || flag.Rule != FlagBurst {
+			t.Errorf("stored flag %+v, want burst flags of the event", flag)
+		}
+	}
+
+	// A second run finds the same votes but stores nothing new
+	if added, err := service.Analyse(context.Background(), testEventID); err != nil || added != 0 {
+		t.Errorf("Analyse() again = %d, %v, want 0 new flags", added, err)
+	}
+}
+
+func TestFraudService_ListFlaggedVotes(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	repo := &mockFraudRepository{}
+	for id := int64(5); id >= 1; id-- {
+		repo.flagged = append(repo.flagged, &FlaggedVote{ID: id})
+	}
+	service := NewFraudService(repo, &mockAuditRecorder{}, FraudSettings{}, logger)
+
+	page, err := service.ListFlaggedVotes(context.Background(), FlaggedVoteFilter{EventID: testEventID, Limit: 2})
+	if err != nil {
+		t.Fatalf("ListFlaggedVotes() error = %v", err)
+	}
+	if repo.filter.Status != FlagStatusPending {
+		t.Errorf("status = %q, want pending by default", repo.filter.Status)
+	}
+	if len(page.Votes) != 2 || page.NextBeforeID != 4 {
+		t.Errorf("page = %d votes, next %d, want 2 votes, next 4", len(page.Votes), page.NextBeforeID)
+	}
+
+	page, err = service.ListFlaggedVotes(context.Background(), FlaggedVoteFilter{EventID: testEventID, Status: FlagStatusAll, BeforeID: 2})
+	if err != nil {
+		t.Fatalf("ListFlaggedVotes() error = %v", err)
+	}
+	if len(page.Votes) != 1 || page.NextBeforeID != 0 {
+		t.Errorf("last page = %d votes, next %d, want 1 vote, next 0", len(page.Votes), page.NextBeforeID)
+	}
+
+	if _, err := service.ListFlaggedVotes(context.Background(), FlaggedVoteFilter{Status: "deleted"}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("ListFlaggedVotes(unknown status) error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestFraudService_Review(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	ctx := context.Background()
+	repo := &mockFraudRepository{}
+	audit := &mockAuditRecorder{}
+	service := NewFraudService(repo, audit, FraudSettings{}, logger)
+
+	if _, err := service.InvalidateVotes(ctx, testEventID, []int64{1, 2}, "  "); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("InvalidateVotes(no reason) error = %v, want ErrInvalidInput", err)
+	}
+	if _, err := service.RestoreVotes(ctx, testEventID, nil); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("RestoreVotes(no votes) error = %v, want ErrInvalidInput", err)
+	}
+	if len(audit.entries) != 0 {
+		t.Fatalf("rejected reviews were audited: %v", audit.actions())
+	}
+
+	changed, err := service.InvalidateVotes(ctx, testEventID, []int64{1, 2}, " bot network ")
+	if err != nil {
+		t.Fatalf("InvalidateVotes() error = %v", err)
+	}
+	if changed != 2 || repo.reason != "bot network" {
+		t.Errorf("InvalidateVotes() = %d with reason %q, want 2 with %q", changed, repo.reason, "bot network")
+	}
+
+	if _, err := service.RestoreVotes(ctx, testEventID, []int64{2}); err != nil {
+		t.Fatalf("RestoreVotes() error = %v", err)
+	}
+	if !equalIDs(repo.reviewed, []int64{2}) || repo.reason != "" {
+		t.Errorf("RestoreVotes() reviewed %v with reason %q", repo.reviewed, repo.reason)
+	}
+
+	actions := audit.actions()
+	if len(actions) != 2 || actions[0] != AuditVotesInvalidated || actions[1] != AuditVotesRestored {
+		t.Errorf("audited %v, want invalidated then restored", actions)
+	}
+}
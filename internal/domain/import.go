@@ -0,0 +1,507 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"voteweb/internal/util"
+)
+
+// Import file formats
+const (
+	ImportFormatCSV  = "csv"
+	ImportFormatJSON = "json"
+	ImportFormatYAML = "yaml"
+)
+
+// importColumns are the fields an import file may set, in the order used by
+// diffs. Rows are matched to stored innovations by group_slug and slug.
+var importColumns = []string{
+	"group_slug", "slug", "name", "division", "entity_name", "pic", "description",
+	"logo_innovation_url", "logo_entity_url", "video_url", "slide_url",
+	"ig_url", "yt_url", "hero_url", "hero_mobile_url",
+}
+
+// requiredImportColumns must be present in a CSV header
+var requiredImportColumns = []string{"group_slug", "name"}
+
+// ImportRow is one innovation read from an import file
+type ImportRow struct {
+	// Line is where the row starts in the file
+	Line  int
+	Input InnovationInput
+	// Columns lists the columns the file gives for the row. Optional fields
+	// whose column is absent keep their stored value on update.
+	Columns []string
+}
+
+// ImportPlan describes what an import changes. Innovations that are stored
+// but missing from the file are left alone.
+type ImportPlan struct {
+	Created   []*ImportChange
+	Updated   []*ImportChange
+	Unchanged int
+	Problems  []ImportProblem
+}
+
+// ImportChange is an innovation the import creates or updates
+type ImportChange struct {
+	Line       int
+	Innovation *Innovation
+	// Before is the stored innovation of an update and nil for a create
+	Before *Innovation
+	// Fields lists the changed fields of an update
+	Fields []FieldChange
+}
+
+// FieldChange is one changed field of an updated innovation
+type FieldChange struct {
+	Field  string
+	Before string
+	After  string
+}
+
+// ImportProblem is a row that cannot be imported
+type ImportProblem struct {
+	Line    int
+	Message string
+}
+
+// ImportService imports innovations in bulk from a file
+type ImportService interface {
+	// PlanImport validates rows and compares them with the event's
+	// innovations without changing anything
+	PlanImport(ctx context.Context, eventID string, rows []ImportRow) (*ImportPlan, error)
+	// ApplyImport plans the import and, when no row has a problem, applies
+	// it in a single transaction
+	ApplyImport(ctx context.Context, eventID string, rows []ImportRow) (*ImportPlan, error)
+}
+
+// ImportRepository defines the data access needed by the importer
+type ImportRepository interface {
+	GetEventByID(ctx context.Context, id string) (*Event, error)
+	ListGroups(ctx context.Context, eventID string) ([]*Group, error)
+	ListAllInnovations(ctx context.Context, eventID string) ([]*Innovation, error)
+	// ImportInnovations inserts creates and saves updates in one transaction,
+	// filling in the IDs and timestamps of the created innovations
+	ImportInnovations(ctx context.Context, creates, updates []*Innovation) error
+}
+
+type importService struct {
+	repo   ImportRepository
+	audit  AuditRecorder
+	logger *slog.Logger
+}
+
+// NewImportService creates a new ImportService
+func NewImportService(repo ImportRepository, audit AuditRecorder, logger *slog.Logger) ImportService {
+	return &importService{
+		repo:   repo,
+		audit:  audit,
+		logger: logger,
+	}
+}
+
+func (s *importService) PlanImport(ctx context.Context, eventID string, rows []ImportRow) (*ImportPlan, error) {
+	if _, err := s.repo.GetEventByID(ctx, eventID); err != nil {
+		return nil, err
+	}
+
+	groups, err := s.repo.ListGroups(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	groupExists := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		groupExists[group.Slug] = true
+	}
+
+	innovations, err := s.repo.ListAllInnovations(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	stored := make(map[string]*Innovation, len(innovations))
+	for _, innovation := range innovations {
+		stored[innovation.GroupSlug+"/"+innovation.Slug] = innovation
+	}
+
+	plan := &ImportPlan{}
+	seen := make(map[string]int)
+	for _, row := range rows {
+		input := row.Input
+		name := strings.TrimSpace(input.Name)
+		groupSlug := strings.TrimSpace(input.GroupSlug)
+
+		problems := validateInnovationInput(input)
+		if groupSlug == "" {
+			problems = append(problems, "group_slug is required")
+		} else if !groupExists[groupSlug] {
+			problems = append(problems, fmt.Sprintf("unknown group %q", groupSlug))
+		}
+
+		slug := input.Slug
+		if slug == "" {
+			slug = util.Slugify(name)
+			if slug == "" && name != "" {
+				problems = append(problems, "name must contain letters or digits")
+			}
+		}
+
+		key := groupSlug + "/" + slug
+		if slug != "" {
+			if line, ok := seen[key]; ok {
+				problems = append(problems, fmt.Sprintf("duplicate of line %d (%s)", line, key))
+			} else {
+				seen[key] = row.Line
+			}
+		}
+
+		if len(problems) > 0 {
+			for _, problem := range problems {
+				plan.Problems = append(plan.Problems, ImportProblem{Line: row.Line, Message: problem})
+			}
+			continue
+		}
+
+		innovation := &Innovation{EventID: eventID}
+		before := stored[key]
+		if before != nil {
+			copied := *before
+			innovation = &copied
+		}
+		innovation.GroupSlug = groupSlug
+		innovation.Slug = slug
+		innovation.Name = name
+		setOptionalFields(innovation, input)
+		if before != nil {
+			keepAbsentFields(innovation, before, row.Columns)
+		}
+
+		if before == nil {
+			plan.Created = append(plan.Created, &ImportChange{Line: row.Line, Innovation: innovation})
+			continue
+		}
+		fields := diffInnovations(before, innovation)
+		if len(fields) == 0 {
+			plan.Unchanged++
+			continue
+		}
+		plan.Updated = append(plan.Updated, &ImportChange{
+			Line:       row.Line,
+			Innovation: innovation,
+			Before:     before,
+			Fields:     fields,
+		})
+	}
+
+	return plan, nil
+}
+
+func (s *importService) ApplyImport(ctx context.Context, eventID string, rows []ImportRow) (*ImportPlan, error) {
+	plan, err := s.PlanImport(ctx, eventID, rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(plan.Problems) > 0 {
+		return plan, fmt.Errorf("%w: %d problem(s) in the import file", ErrInvalidInput, len(plan.Problems))
+	}
+	if len(plan.Created) == 0 && len(plan.Updated) == 0 {
+		return plan, nil
+	}
+
+	creates := make([]*Innovation, 0, len(plan.Created))
+	for _, change := range plan.Created {
+		creates = append(creates, change.Innovation)
+	}
+	updates := make([]*Innovation, 0, len(plan.Updated))
+	for _, change := range plan.Updated {
+		updates = append(updates, change.Innovation)
+	}
+
+	if err := s.repo.ImportInnovations(ctx, creates, updates); err != nil {
+		s.logger.ErrorContext(ctx, "failed to import innovations",
+			"event_id", eventID,
+			"created", len(creates),
+			"updated", len(updates),
+			"error", err)
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "innovations imported",
+		"event_id", eventID,
+		"created", len(creates),
+		"updated", len(updates),
+		"unchanged", plan.Unchanged)
+	for _, change := range plan.Created {
+		s.audit.Record(ctx, AuditInnovationCreated, "innovation", change.Innovation.ID, nil, change.Innovation)
+	}
+	for _, change := range plan.Updated {
+		s.audit.Record(ctx, AuditInnovationUpdated, "innovation", change.Innovation.ID, change.Before, change.Innovation)
+	}
+	s.audit.Record(ctx, AuditInnovationsImported, "event", eventID, nil, map[string]int{
+		"created":   len(creates),
+		"updated":   len(updates),
+		"unchanged": plan.Unchanged,
+	})
+	return plan, nil
+}
+
+// keepAbsentFields copies the optional fields whose column is not in columns
+// from before, so a file without a column leaves the stored values alone
+func keepAbsentFields(innovation, before *Innovation, columns []string) {
+	stored := optionalImportFields(before)
+	for column, field := range optionalImportFields(innovation) {
+		if !slices.Contains(columns, column) {
+			*field = *stored[column]
+		}
+	}
+}
+
+// optionalImportFields maps the optional import columns to the fields of
+// innovation
+func optionalImportFields(innovation *Innovation) map[string]**string {
+	return map[string]**string{
+		"division":            &innovation.Division,
+		"entity_name":         &innovation.EntityName,
+		"pic":                 &innovation.PIC,
+		"description":         &innovation.Description,
+		"logo_innovation_url": &innovation.LogoInnovationURL,
+		"logo_entity_url":     &innovation.LogoEntityURL,
+		"video_url":           &innovation.VideoURL,
+		"slide_url":           &innovation.SlideURL,
+		"ig_url":              &innovation.IgURL,
+		"yt_url":              &innovation.YtURL,
+		"hero_url":            &innovation.HeroURL,
+		"hero_mobile_url":     &innovation.HeroMobileURL,
+	}
+}
+
+// diffInnovations lists the import fields that differ between before and after
+func diffInnovations(before, after *Innovation) []FieldChange {
+	b, a := importValues(before), importValues(after)
+	var changes []FieldChange
+	for i, column := range importColumns {
+		if b[i] != a[i] {
+			changes = append(changes, FieldChange{Field: column, Before: b[i], After: a[i]})
+		}
+	}
+	return changes
+}
+
+// importValues returns the fields of innovation in importColumns order
+func importValues(innovation *Innovation) []string {
+	return []string{
+		innovation.GroupSlug,
+		innovation.Slug,
+		innovation.Name,
+		derefString(innovation.Division),
+		derefString(innovation.EntityName),
+		derefString(innovation.PIC),
+		derefString(innovation.Description),
+		derefString(innovation.LogoInnovationURL),
+		derefString(innovation.LogoEntityURL),
+		derefString(innovation.VideoURL),
+		derefString(innovation.SlideURL),
+		derefString(innovation.IgURL),
+		derefString(innovation.YtURL),
+		derefString(innovation.HeroURL),
+		derefString(innovation.HeroMobileURL),
+	}
+}
+
+// ImportFormatFromPath guesses the import format from a file extension
+func ImportFormatFromPath(path string) (string, bool) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return ImportFormatCSV, true
+	case ".json":
+		return ImportFormatJSON, true
+	case ".yaml", ".yml":
+		return ImportFormatYAML, true
+	default:
+		return "", false
+	}
+}
+
+// ParseInnovationImport reads the innovations of an import file. Every
+// format uses the importColumns names: a CSV header row, or the keys of a
+// JSON or YAML list of objects. Values are trimmed.
+func ParseInnovationImport(r io.Reader, format string) ([]ImportRow, error) {
+	switch format {
+	case ImportFormatCSV:
+		return parseImportCSV(r)
+	case ImportFormatJSON:
+		return parseImportJSON(r)
+	case ImportFormatYAML:
+		return parseImportYAML(r)
+	default:
+		return nil, fmt.Errorf("%w: unknown import format %q", ErrInvalidInput, format)
+	}
+}
+
+func parseImportCSV(r io.Reader) ([]ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: the file is empty", ErrInvalidInput)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	// Spreadsheet programs often start UTF-8 CSV files with a byte order mark
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	for i, column := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(column))
+		if !slices.Contains(importColumns, header[i]) {
+			return nil, fmt.Errorf("%w: line 1: unknown column %q", ErrInvalidInput, column)
+		}
+		if slices.Contains(header[:i], header[i]) {
+			return nil, fmt.Errorf("%w: line 1: column %q appears twice", ErrInvalidInput, header[i])
+		}
+	}
+	for _, column := range requiredImportColumns {
+		if !slices.Contains(header, column) {
+			return nil, fmt.Errorf("%w: line 1: missing required column %q", ErrInvalidInput, column)
+		}
+	}
+
+	var rows []ImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		values := make(map[string]string, len(header))
+		for i, column := range header {
+			values[column] = record[i]
+		}
+		row, err := importRow(line, values)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+}
+
+func parseImportJSON(r io.Reader) ([]ImportRow, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return nil, fmt.Errorf("%w: expected a JSON list of innovations", ErrInvalidInput)
+	}
+
+	var rows []ImportRow
+	for decoder.More() {
+		// Skip to the opening brace so the line is that of the object
+		offset := int(decoder.InputOffset())
+		for offset < len(data) && strings.IndexByte(" \t\r\n,", data[offset]) >= 0 {
+			offset++
+		}
+		line := 1 + bytes.Count(data[:offset], []byte("\n"))
+
+		var values map[string]string
+		if err := decoder.Decode(&values); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidInput, line, err)
+		}
+		row, err := importRow(line, values)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	return rows, nil
+}
+
+func parseImportYAML(r io.Reader) ([]ImportRow, error) {
+	var document yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&document); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: the file is empty", ErrInvalidInput)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("%w: expected a YAML list of innovations", ErrInvalidInput)
+	}
+
+	var rows []ImportRow
+	for _, item := range document.Content[0].Content {
+		var values map[string]string
+		if err := item.Decode(&values); err != nil {
+			return nil, fmt.Errorf("%w: line %d: expected a mapping of text values", ErrInvalidInput, item.Line)
+		}
+		row, err := importRow(item.Line, values)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// importRow builds a row from column values, rejecting unknown columns and
+// recording the known ones in importColumns order
+func importRow(line int, values map[string]string) (ImportRow, error) {
+	row := ImportRow{Line: line}
+	fields := map[string]*string{
+		"group_slug":          &row.Input.GroupSlug,
+		"slug":                &row.Input.Slug,
+		"name":                &row.Input.Name,
+		"division":            &row.Input.Division,
+		"entity_name":         &row.Input.EntityName,
+		"pic":                 &row.Input.PIC,
+		"description":         &row.Input.Description,
+		"logo_innovation_url": &row.Input.LogoInnovationURL,
+		"logo_entity_url":     &row.Input.LogoEntityURL,
+		"video_url":           &row.Input.VideoURL,
+		"slide_url":           &row.Input.SlideURL,
+		"ig_url":              &row.Input.IgURL,
+		"yt_url":              &row.Input.YtURL,
+		"hero_url":            &row.Input.HeroURL,
+		"hero_mobile_url":     &row.Input.HeroMobileURL,
+	}
+	present := make(map[string]bool, len(values))
+	for column, value := range values {
+		present[strings.ToLower(column)] = true
+		field, ok := fields[strings.ToLower(column)]
+		if !ok {
+			return ImportRow{}, fmt.Errorf("%w: line %d: unknown column %q", ErrInvalidInput, line, column)
+		}
+		*field = strings.TrimSpace(value)
+	}
+	for _, column := range importColumns {
+		if present[column] {
+			row.Columns = append(row.Columns, column)
+		}
+	}
+	return row, nil
+}
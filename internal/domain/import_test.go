@@ -0,0 +1,275 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+)
+
+// ImportInnovations makes mockRepository an ImportRepository; it stops at the
+// first failure without undoing earlier writes
+func (m *mockRepository) ImportInnovations(ctx context.Context, creates, updates []*Innovation) error {
+	for _, innovation := range creates {
+		if err := m.CreateInnovation(ctx, innovation); err != nil {
+			return err
+		}
+	}
+	for _, innovation := range updates {
+		if err := m.UpdateInnovation(ctx, innovation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newTestImportService() (*mockRepository, *mockAuditRecorder, ImportService) {
+	repo := newMockRepository()
+	repo.groups = []*Group{
+		{EventID: testEventID, Slug: "digital", Name: "Digital", Position: 1},
+		{EventID: testEventID, Slug: "operasional", Name: "Operasional", Position: 2},
+	}
+	audit := &mockAuditRecorder{}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	return repo, audit, NewImportService(repo, audit, logger)
+}
+
+func TestParseInnovationImport(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		data   string
+		lines  [2]int
+	}{
+		{
+			name:   "csv",
+			format: ImportFormatCSV,
+			data: "\ufeffName,Group_Slug,hero_url\n" +
+				"Smart Meter , digital,/static/smart.webp\n" +
+				",,\n" +
+				"\"Jabar\nForm\",operasional,\n",
+			lines: [2]int{2, 4},
+		},
+		{
+			name:   "json",
+			format: ImportFormatJSON,
+			data: `[
+  {"name": "Smart Meter ", "group_slug": " digital", "hero_url": "/static/smart.webp"},
+
+  {"name": "Jabar\nForm", "group_slug": "operasional"}
+]`,
+			lines: [2]int{2, 4},
+		},
+		{
+			name:   "yaml",
+			format: ImportFormatYAML,
+			data: `- name: "Smart Meter "
+  group_slug: digital
+  hero_url: /static/smart.webp
+
+- name: "Jabar\nForm"
+  group_slug: operasional
+`,
+			lines: [2]int{1, 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := ParseInnovationImport(strings.NewReader(tt.data), tt.format)
+			if err != nil {
+				t.Fatalf("ParseInnovationImport() error = %v", err)
+			}
+			if len(rows) != 2 {
+				t.Fatalf("rows = %d, want 2", len(rows))
+			}
+
+			first := rows[0]
+			if first.Line != tt.lines[0] || first.Input.Name != "Smart Meter" || first.Input.GroupSlug != "digital" ||
+				first.Input.HeroURL != "/static/smart.webp" {
+				t.Errorf("rows[0] = %+v, want the trimmed Smart Meter row at line %d", first, tt.lines[0])
+			}
+			if rows[1].Line != tt.lines[1] || rows[1].Input.Name != "Jabar\nForm" {
+				t.Errorf("rows[1] = %+v, want Jabar Form at line %d", rows[1], tt.lines[1])
+			}
+		})
+	}
+}
+
+func TestParseInnovationImport_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		data   string
+		want   string
+	}{
+		{"missing required column", ImportFormatCSV, "name,slug\nA,a\n", `missing required column "group_slug"`},
+		{"unknown csv column", ImportFormatCSV, "name,group_slug,votes\nA,digital,3\n", `unknown column "votes"`},
+		{"repeated column", ImportFormatCSV, "name,group_slug,name\nA,digital,B\n", "appears twice"},
+		{"empty csv", ImportFormatCSV, "", "empty"},
+		{"unknown json key", ImportFormatJSON, "[\n{\"name\": \"A\", \"group\": \"digital\"}\n]", `line 2: unknown column "group"`},
+		{"json object", ImportFormatJSON, `{"name": "A"}`, "list of innovations"},
+		{"json number", ImportFormatJSON, `[{"name": 3}]`, "line 1"},
+		{"yaml mapping", ImportFormatYAML, "name: A\n", "list of innovations"},
+		{"yaml nested value", ImportFormatYAML, "- name: A\n- name: [B]\n", "line 2"},
+		{"unknown format", "xlsx", "", "unknown import format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseInnovationImport(strings.NewReader(tt.data), tt.format)
+			if !errors.Is(err, ErrInvalidInput) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseInnovationImport() error = %v, want ErrInvalidInput containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportService_PlanImport(t *testing.T) {
+	repo, _, service := newTestImportService()
+	division := "Dinas Kominfo"
+	repo.addInnovation(&Innovation{ID: "meter", GroupSlug: "digital", Slug: "smart-meter", Name: "Smart Meter", Division: &division})
+	repo.addInnovation(&Innovation{ID: "form", GroupSlug: "digital", Slug: "jabar-form", Name: "Jabar Form"})
+
+	rows := []ImportRow{
+		{Line: 2, Input: InnovationInput{GroupSlug: "digital", Name: "Smart Meter", Division: "Diskominfo"}},
+		{Line: 3, Input: InnovationInput{GroupSlug: "digital", Slug: "jabar-form", Name: "Jabar Form"}},
+		{Line: 4, Input: InnovationInput{GroupSlug: "operasional", Name: "Sapa Warga", VideoURL: "https://youtu.be/x"}},
+		{Line: 5, Input: InnovationInput{GroupSlug: "operasional", Slug: "sapa-warga", Name: "Sapa Warga 2"}},
+		{Line: 6, Input: InnovationInput{GroupSlug: "kesehatan", Name: "Sehat"}},
+		{Line: 7, Input: InnovationInput{GroupSlug: "digital", Name: "Bad Links", IgURL: "instagram.com/x"}},
+		{Line: 8, Input: InnovationInput{GroupSlug: "digital", Name: "!!!"}},
+		{Line: 9, Input: InnovationInput{Name: "No Group"}},
+	}
+	for i := range rows {
+		rows[i].Columns = importColumns
+	}
+
+	plan, err := service.PlanImport(context.Background(), testEventID, rows)
+	if err != nil {
+		t.Fatalf("PlanImport() error = %v", err)
+	}
+
+	if len(plan.Created) != 1 || plan.Created[0].Innovation.Slug != "sapa-warga" || plan.Created[0].Line != 4 {
+		t.Errorf("Created = %+v, want sapa-warga from line 4", plan.Created)
+	}
+	if len(plan.Updated) != 1 || plan.Updated[0].Innovation.ID != "meter" {
+		t.Fatalf("Updated = %+v, want the smart meter", plan.Updated)
+	}
+	fields := plan.Updated[0].Fields
+	if len(fields) != 1 || fields[0] != (FieldChange{Field: "division", Before: "Dinas Kominfo", After: "Diskominfo"}) {
+		t.Errorf("Fields = %+v, want only the division change", fields)
+	}
+	if plan.Unchanged != 1 {
+		t.Errorf("Unchanged = %d, want 1", plan.Unchanged)
+	}
+	if *repo.innovations["meter"].Division != "Dinas Kominfo" {
+		t.Error("PlanImport() changed the stored innovation")
+	}
+
+	wantProblems := map[int]string{
+		5: "duplicate of line 4",
+		6: `unknown group "kesehatan"`,
+		7: "ig_url must be an http(s) URL",
+		8: "name must contain letters or digits",
+		9: "group_slug is required",
+	}
+	if len(plan.Problems) != len(wantProblems) {
+		t.Errorf("Problems = %+v, want %d", plan.Problems, len(wantProblems))
+	}
+	for _, problem := range plan.Problems {
+		if want := wantProblems[problem.Line]; !strings.Contains(problem.Message, want) || want == "" {
+			t.Errorf("line %d problem = %q, want one containing %q", problem.Line, problem.Message, want)
+		}
+	}
+}
+
+func TestImportService_ApplyImport(t *testing.T) {
+	repo, audit, service := newTestImportService()
+	repo.addInnovation(&Innovation{ID: "meter", GroupSlug: "digital", Slug: "smart-meter", Name: "Smart Meter"})
+	ctx := context.Background()
+
+	rows := []ImportRow{
+		{Line: 2, Input: InnovationInput{GroupSlug: "digital", Name: "Smart Meter", PIC: "Rina"}},
+		{Line: 3, Input: InnovationInput{GroupSlug: "operasional", Name: "Sapa Warga"}},
+	}
+	for i := range rows {
+		rows[i].Columns = importColumns
+	}
+	plan, err := service.ApplyImport(ctx, testEventID, rows)
+	if err != nil {
+		t.Fatalf("ApplyImport() error = %v", err)
+	}
+	if len(plan.Created) != 1 || len(plan.Updated) != 1 {
+		t.Fatalf("plan = %d created, %d updated, want 1 and 1", len(plan.Created), len(plan.Updated))
+	}
+	if created := plan.Created[0].Innovation; created.ID == "" || repo.innovations[created.ID] == nil {
+		t.Errorf("created innovation %+v was not stored", created)
+	}
+	if pic := repo.innovations["meter"].PIC; pic == nil || *pic != "Rina" {
+		t.Errorf("PIC = %v, want Rina", pic)
+	}
+
+	got := audit.actions()
+	want := []AuditAction{AuditInnovationCreated, AuditInnovationUpdated, AuditInnovationsImported}
+	if len(got) != len(want) {
+		t.Fatalf("audit actions = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("audit actions = %v, want %v", got, want)
+			break
+		}
+	}
+
+	// Importing the same file again changes nothing
+	plan, err = service.ApplyImport(ctx, testEventID, rows)
+	if err != nil {
+		t.Fatalf("second ApplyImport() error = %v", err)
+	}
+	if plan.Unchanged != 2 || len(plan.Created)+len(plan.Updated) != 0 {
+		t.Errorf("second plan = %+v, want everything unchanged", plan)
+	}
+}
+
+func TestImportService_ApplyImport_KeepsAbsentColumns(t *testing.T) {
+	repo, _, service := newTestImportService()
+	description := "Pencatatan meter listrik otomatis"
+	repo.addInnovation(&Innovation{ID: "meter", GroupSlug: "digital", Slug: "smart-meter", Name: "Smart Meter", Description: &description})
+
+	rows, err := ParseInnovationImport(strings.NewReader("group_slug,name\ndigital,Smart Meter\n"), ImportFormatCSV)
+	if err != nil {
+		t.Fatalf("ParseInnovationImport() error = %v", err)
+	}
+	plan, err := service.ApplyImport(context.Background(), testEventID, rows)
+	if err != nil {
+		t.Fatalf("ApplyImport() error = %v", err)
+	}
+	if plan.Unchanged != 1 || len(plan.Updated) != 0 {
+		t.Errorf("plan = %+v, want the smart meter unchanged", plan)
+	}
+	if stored := repo.innovations["meter"].Description; stored == nil || *stored != description {
+		t.Errorf("Description = %v, want %q kept", stored, description)
+	}
+}
+
+func TestImportService_ApplyImport_Problems(t *testing.T) {
+	repo, audit, service := newTestImportService()
+
+	rows := []ImportRow{
+		{Line: 2, Input: InnovationInput{GroupSlug: "digital", Name: "Smart Meter"}},
+		{Line: 3, Input: InnovationInput{GroupSlug: "digital", Name: "Smart Meter"}},
+	}
+	plan, err := service.ApplyImport(context.Background(), testEventID, rows)
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Fatalf("ApplyImport() error = %v, want ErrInvalidInput", err)
+	}
+	if plan == nil || len(plan.Problems) != 1 {
+		t.Errorf("plan = %+v, want the duplicate reported", plan)
+	}
+	if len(repo.innovations) != 0 || len(audit.entries) != 0 {
+		t.Error("ApplyImport() wrote innovations despite problems")
+	}
+}
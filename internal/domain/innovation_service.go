@@ -0,0 +1,373 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"voteweb/internal/util"
+)
+
+const (
+	maxNameLength        = 300
+	maxTextLength        = 5000
+	maxSlugSuffixAttempt = 100
+)
+
+// InnovationService handles innovation management for admins
+type InnovationService interface {
+	ListInnovations(ctx context.Context, eventID string) ([]*Innovation, error)
+	GetInnovation(ctx context.Context, id string) (*Innovation, error)
+	CreateInnovation(ctx context.Context, input InnovationInput) (*Innovation, error)
+	UpdateInnovation(ctx context.Context, id string, input InnovationInput) (*Innovation, error)
+	ReorderInnovations(ctx context.Context, eventID, groupSlug string, ids []string) error
+	ArchiveInnovation(ctx context.Context, id string) error
+	RestoreInnovation(ctx context.Context, id string) error
+}
+
+// InnovationInput holds the editable fields of an innovation. Empty optional
+// fields are stored as NULL. An empty slug is generated from the name.
+type InnovationInput struct {
+	EventID           string `json:"event_id"`
+	GroupSlug         string `json:"group_slug"`
+	Slug              string `json:"slug"`
+	Name              string `json:"name"`
+	Division          string `json:"division"`
+	EntityName        string `json:"entity_name"`
+	PIC               string `json:"pic"`
+	Description       string `json:"description"`
+	LogoInnovationURL string `json:"logo_innovation_url"`
+	LogoEntityURL     string `json:"logo_entity_url"`
+	VideoURL          string `json:"video_url"`
+	SlideURL          string `json:"slide_url"`
+	IgURL             string `json:"ig_url"`
+	YtURL             string `json:"yt_url"`
+	HeroURL           string `json:"hero_url"`
+	HeroMobileURL     string `json:"hero_mobile_url"`
+}
+
+type innovationService struct {
+	repo   Repository
+	audit  AuditRecorder
+	logger *slog.Logger
+}
+
+// NewInnovationService creates a new InnovationService
+func NewInnovationService(repo Repository, audit AuditRecorder, logger *slog.Logger) InnovationService {
+	return &innovationService{
+		repo:   repo,
+		audit:  audit,
+		logger: logger,
+	}
+}
+
+// ListInnovations returns every innovation of the event, archived ones included
+func (s *innovationService) ListInnovations(ctx context.Context, eventID string) ([]*Innovation, error) {
+	return s.repo.ListAllInnovations(ctx, eventID)
+}
+
+func (s *innovationService) GetInnovation(ctx context.Context, id string) (*Innovation, error) {
+	return s.repo.GetInnovationByID(ctx, id)
+}
+
+func (s *innovationService) CreateInnovation(ctx context.Context, input InnovationInput) (*Innovation, error) {
+	if _, err := s.repo.GetEventByID(ctx, input.EventID); err != nil {
+		return nil, err
+	}
+
+	innovation := &Innovation{EventID: input.EventID}
+	if err := s.apply(ctx, innovation, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateInnovation(ctx, innovation); err != nil {
+		s.logger.ErrorContext(ctx, "failed to create innovation",
+			"event_id", innovation.EventID,
+			"group_slug", innovation.GroupSlug,
+			"slug", innovation.Slug,
+			"error", err)
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "innovation created",
+		"innovation_id", innovation.ID,
+		"group_slug", innovation.GroupSlug,
+		"slug", innovation.Slug)
+	s.audit.Record(ctx, AuditInnovationCreated, "innovation", innovation.ID, nil, innovation)
+	return innovation, nil
+}
+
+func (s *innovationService) UpdateInnovation(ctx context.Context, id string, input InnovationInput) (*Innovation, error) {
+	innovation, err := s.repo.GetInnovationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *innovation
+
+	if err := s.apply(ctx, innovation, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateInnovation(ctx, innovation); err != nil {
+		s.logger.ErrorContext(ctx, "failed to update innovation",
+			"innovation_id", id,
+			"error", err)
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "innovation updated",
+		"innovation_id", innovation.ID,
+		"group_slug", innovation.GroupSlug,
+		"slug", innovation.Slug)
+	s.audit.Record(ctx, AuditInnovationUpdated, "innovation", innovation.ID, before, innovation)
+	return innovation, nil
+}
+
+// ReorderInnovations sets the display order of a group. ids must list every
+// innovation of the group exactly once.
+func (s *innovationService) ReorderInnovations(ctx context.Context, eventID, groupSlug string, ids []string) error {
+	innovations, err := s.repo.ListAllInnovations(ctx, eventID)
+	if err != nil {
+		return err
+	}
+
+	inGroup := make(map[string]bool)
+	var previous []string
+	for _, innovation := range innovations {
+		if innovation.GroupSlug == groupSlug {
+			inGroup[innovation.ID] = true
+			previous = append(previous, innovation.ID)
+		}
+	}
+
+	if len(ids) != len(inGroup) {
+		return fmt.Errorf("%w: expected %d innovations for group %s, got %d", ErrInvalidInput, len(inGroup), groupSlug, len(ids))
+	}
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if !inGroup[id] || seen[id] {
+			return fmt.Errorf("%w: innovation %s is not in group %s or listed twice", ErrInvalidInput, id, groupSlug)
+		}
+		seen[id] = true
+	}
+
+	if err := s.repo.SetInnovationPositions(ctx, ids); err != nil {
+		s.logger.ErrorContext(ctx, "failed to reorder innovations",
+			"event_id", eventID,
+			"group_slug", groupSlug,
+			"error", err)
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "innovations reordered",
+		"event_id", eventID,
+		"group_slug", groupSlug)
+	s.audit.Record(ctx, AuditInnovationsReordered, "group", eventID+"/"+groupSlug, previous, ids)
+	return nil
+}
+
+func (s *innovationService) ArchiveInnovation(ctx context.Context, id string) error {
+	return s.setArchived(ctx, id, true)
+}
+
+func (s *innovationService) RestoreInnovation(ctx context.Context, id string) error {
+	return s.setArchived(ctx, id, false)
+}
+
+func (s *innovationService) setArchived(ctx context.Context, id string, archived bool) error {
+	if err := s.repo.SetInnovationArchived(ctx, id, archived); err != nil {
+		if !errors.Is(err, ErrInnovationNotFound) {
+			s.logger.ErrorContext(ctx, "failed to change innovation archive state",
+				"innovation_id", id,
+				"archived", archived,
+				"error", err)
+		}
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "innovation archive state changed",
+		"innovation_id", id,
+		"archived", archived)
+
+	action := AuditInnovationRestored
+	if archived {
+		action = AuditInnovationArchived
+	}
+	s.audit.Record(ctx, action, "innovation", id, nil, nil)
+	return nil
+}
+
+// apply validates input and copies it onto innovation, resolving the slug
+func (s *innovationService) apply(ctx context.Context, innovation *Innovation, input InnovationInput) error {
+	name := strings.TrimSpace(input.Name)
+	groupSlug := strings.TrimSpace(input.GroupSlug)
+	problems := validateInnovationInput(input)
+
+	groups, err := s.repo.ListGroups(ctx, innovation.EventID)
+	if err != nil {
+		return err
+	}
+	groupExists := false
+	for _, group := range groups {
+		if group.Slug == groupSlug {
+			groupExists = true
+			break
+		}
+	}
+	if !groupExists {
+		problems = append(problems, fmt.Sprintf("unknown group %q", groupSlug))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidInput, strings.Join(problems, "; "))
+	}
+
+	slug, err := s.resolveSlug(ctx, innovation, groupSlug, input.Slug, name)
+	if err != nil {
+		return err
+	}
+
+	innovation.GroupSlug = groupSlug
+	innovation.Slug = slug
+	innovation.Name = name
+	setOptionalFields(innovation, input)
+	return nil
+}
+
+// validateInnovationInput returns the problems of input that can be found
+// without looking at stored data
+func validateInnovationInput(input InnovationInput) []string {
+	var problems []string
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		problems = append(problems, "name is required")
+	} else if utf8.RuneCountInString(name) > maxNameLength {
+		problems = append(problems, fmt.Sprintf("name must be at most %d characters", maxNameLength))
+	}
+	if utf8.RuneCountInString(input.Description) > maxTextLength {
+		problems = append(problems, fmt.Sprintf("description must be at most %d characters", maxTextLength))
+	}
+	if input.Slug != "" && util.Slugify(input.Slug) != input.Slug {
+		problems = append(problems, "slug may only contain lowercase letters, digits and hyphens")
+	}
+
+	for _, field := range []struct{ name, value string }{
+		{"logo_innovation_url", input.LogoInnovationURL},
+		{"logo_entity_url", input.LogoEntityURL},
+		{"hero_url", input.HeroURL},
+		{"hero_mobile_url", input.HeroMobileURL},
+	} {
+		if !isAssetURL(field.value) {
+			problems = append(problems, field.name+" must be an http(s) URL or a /static/ path")
+		}
+	}
+	for _, field := range []struct{ name, value string }{
+		{"video_url", input.VideoURL},
+		{"slide_url", input.SlideURL},
+		{"ig_url", input.IgURL},
+		{"yt_url", input.YtURL},
+	} {
+		if !isWebURL(field.value) {
+			problems = append(problems, field.name+" must be an http(s) URL")
+		}
+	}
+	return problems
+}
+
+// setOptionalFields copies the optional fields of input onto innovation
+func setOptionalFields(innovation *Innovation, input InnovationInput) {
+	innovation.Division = optional(input.Division)
+	innovation.EntityName = optional(input.EntityName)
+	innovation.PIC = optional(input.PIC)
+	innovation.Description = optional(input.Description)
+	innovation.LogoInnovationURL = optional(input.LogoInnovationURL)
+	innovation.LogoEntityURL = optional(input.LogoEntityURL)
+	innovation.VideoURL = optional(input.VideoURL)
+	innovation.SlideURL = optional(input.SlideURL)
+	innovation.IgURL = optional(input.IgURL)
+	innovation.YtURL = optional(input.YtURL)
+	innovation.HeroURL = optional(input.HeroURL)
+	innovation.HeroMobileURL = optional(input.HeroMobileURL)
+}
+
+// resolveSlug returns a slug that is unique within the group. An explicit slug
+// must be free; a slug generated from the name gets a numeric suffix if needed.
+func (s *innovationService) resolveSlug(ctx context.Context, innovation *Innovation, groupSlug, requested, name string) (string, error) {
+	if requested != "" {
+		taken, err := s.repo.SlugExists(ctx, innovation.EventID, groupSlug, requested, innovation.ID)
+		if err != nil {
+			return "", err
+		}
+		if taken {
+			return "", fmt.Errorf("%w: %s/%s", ErrSlugTaken, groupSlug, requested)
+		}
+		return requested, nil
+	}
+
+	base := util.Slugify(name)
+	if base == "" {
+		return "", fmt.Errorf("%w: name must contain letters or digits", ErrInvalidInput)
+	}
+
+	// Keep the current slug when editing without moving group or renaming
+	if innovation.ID != "" && innovation.GroupSlug == groupSlug && hasSlugBase(innovation.Slug, base) {
+		return innovation.Slug, nil
+	}
+
+	for attempt := 1; attempt <= maxSlugSuffixAttempt; attempt++ {
+		candidate := base
+		if attempt > 1 {
+			candidate = fmt.Sprintf("%s-%d", base, attempt)
+		}
+		taken, err := s.repo.SlugExists(ctx, innovation.EventID, groupSlug, candidate, innovation.ID)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s/%s", ErrSlugTaken, groupSlug, base)
+}
+
+// hasSlugBase reports whether slug is base or base with a numeric suffix
+func hasSlugBase(slug, base string) bool {
+	if slug == base {
+		return true
+	}
+	suffix, ok := strings.CutPrefix(slug, base+"-")
+	if !ok || suffix == "" {
+		return false
+	}
+	_, err := strconv.Atoi(suffix)
+	return err == nil
+}
+
+// isAssetURL reports whether value is empty, an absolute http(s) URL or a /static/ path
+func isAssetURL(value string) bool {
+	return value == "" || strings.HasPrefix(value, "/static/") || isWebURL(value)
+}
+
+// isWebURL reports whether value is empty or an absolute http(s) URL
+func isWebURL(value string) bool {
+	if value == "" {
+		return true
+	}
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// optional converts a trimmed empty string to nil
+func optional(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
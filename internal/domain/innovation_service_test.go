@@ -0,0 +1,221 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+)
+
+func newTestInnovationService() (*mockRepository, InnovationService) {
+	repo := newMockRepository()
+	repo.groups = []*Group{
+		{EventID: testEventID, Slug: "digital", Name: "Digital", Position: 1},
+		{EventID: testEventID, Slug: "operasional", Name: "Operasional", Position: 2},
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	return repo, NewInnovationService(repo, &mockAuditRecorder{}, logger)
+}
+
+func TestInnovationService_CreateInnovation(t *testing.T) {
+	repo, service := newTestInnovationService()
+	ctx := context.Background()
+
+	first, err := service.CreateInnovation(ctx, InnovationInput{
+		EventID:   testEventID,
+		GroupSlug: "digital",
+		Name:      "Smart Meter Reader",
+		VideoURL:  "https://youtube.com/watch?v=abc",
+		HeroURL:   "/static/hero/smart.png",
+	})
+	if err != nil {
+		t.Fatalf("CreateInnovation() error = %v", err)
+	}
+	if first.Slug != "smart-meter-reader" {
+		t.Errorf("Slug = %q, want %q", first.Slug, "smart-meter-reader")
+	}
+	if first.Position != 1 {
+		t.Errorf("Position = %d, want 1", first.Position)
+	}
+	if first.Division != nil {
+		t.Errorf("Division = %v, want nil for empty input", *first.Division)
+	}
+
+	second, err := service.CreateInnovation(ctx, InnovationInput{
+		EventID:   testEventID,
+		GroupSlug: "digital",
+		Name:      "Smart Meter Reader",
+	})
+	if err != nil {
+		t.Fatalf("CreateInnovation() second error = %v", err)
+	}
+	if second.Slug != "smart-meter-reader-2" {
+		t.Errorf("second Slug = %q, want %q", second.Slug, "smart-meter-reader-2")
+	}
+	if second.Position != 2 {
+		t.Errorf("second Position = %d, want 2", second.Position)
+	}
+
+	// Same name in another group does not need a suffix
+	other, err := service.CreateInnovation(ctx, InnovationInput{
+		EventID:   testEventID,
+		GroupSlug: "operasional",
+		Name:      "Smart Meter Reader",
+	})
+	if err != nil {
+		t.Fatalf("CreateInnovation() other group error = %v", err)
+	}
+	if other.Slug != "smart-meter-reader" {
+		t.Errorf("other group Slug = %q, want %q", other.Slug, "smart-meter-reader")
+	}
+
+	_, err = service.CreateInnovation(ctx, InnovationInput{
+		EventID:   testEventID,
+		GroupSlug: "digital",
+		Slug:      "smart-meter-reader",
+		Name:      "Another Reader",
+	})
+	if !errors.Is(err, ErrSlugTaken) {
+		t.Errorf("explicit duplicate slug error = %v, want %v", err, ErrSlugTaken)
+	}
+
+	if len(repo.innovations) != 3 {
+		t.Errorf("stored innovations = %d, want 3", len(repo.innovations))
+	}
+}
+
+func TestInnovationService_Validation(t *testing.T) {
+	tests := []struct {
+		name  string
+		input InnovationInput
+	}{
+		{
+			name:  "missing name",
+			input: InnovationInput{EventID: testEventID, GroupSlug: "digital"},
+		},
+		{
+			name:  "unknown group",
+			input: InnovationInput{EventID: testEventID, GroupSlug: "unknown", Name: "Inovasi"},
+		},
+		{
+			name:  "invalid slug",
+			input: InnovationInput{EventID: testEventID, GroupSlug: "digital", Slug: "Bad Slug", Name: "Inovasi"},
+		},
+		{
+			name:  "relative asset url",
+			input: InnovationInput{EventID: testEventID, GroupSlug: "digital", Name: "Inovasi", LogoInnovationURL: "logo.png"},
+		},
+		{
+			name:  "javascript video url",
+			input: InnovationInput{EventID: testEventID, GroupSlug: "digital", Name: "Inovasi", VideoURL: "javascript:alert(1)"},
+		},
+		{
+			name:  "static path for video url",
+			input: InnovationInput{EventID: testEventID, GroupSlug: "digital", Name: "Inovasi", VideoURL: "/static/video.mp4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, service := newTestInnovationService()
+
+			_, err := service.CreateInnovation(context.Background(), tt.input)
+			if !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("CreateInnovation() error = %v, want %v", err, ErrInvalidInput)
+			}
+			if len(repo.innovations) != 0 {
+				t.Errorf("stored innovations = %d, want 0", len(repo.innovations))
+			}
+		})
+	}
+}
+
+func TestInnovationService_UpdateInnovation(t *testing.T) {
+	repo, service := newTestInnovationService()
+	ctx := context.Background()
+
+	repo.addInnovation(&Innovation{ID: "a", GroupSlug: "digital", Slug: "alpha", Name: "Alpha", Position: 1})
+	repo.addInnovation(&Innovation{ID: "b", GroupSlug: "digital", Slug: "beta", Name: "Beta", Position: 2})
+
+	updated, err := service.UpdateInnovation(ctx, "a", InnovationInput{GroupSlug: "digital", Name: "Alpha"})
+	if err != nil {
+		t.Fatalf("UpdateInnovation() error = %v", err)
+	}
+	if updated.Slug != "alpha" {
+		t.Errorf("Slug = %q, want unchanged %q", updated.Slug, "alpha")
+	}
+
+	_, err = service.UpdateInnovation(ctx, "a", InnovationInput{GroupSlug: "digital", Slug: "beta", Name: "Alpha"})
+	if !errors.Is(err, ErrSlugTaken) {
+		t.Errorf("UpdateInnovation() to taken slug error = %v, want %v", err, ErrSlugTaken)
+	}
+	if repo.innovations["a"].Slug != "alpha" {
+		t.Errorf("stored slug changed to %q after failed update", repo.innovations["a"].Slug)
+	}
+
+	_, err = service.UpdateInnovation(ctx, "missing", InnovationInput{GroupSlug: "digital", Name: "Missing"})
+	if !errors.Is(err, ErrInnovationNotFound) {
+		t.Errorf("UpdateInnovation() missing error = %v, want %v", err, ErrInnovationNotFound)
+	}
+}
+
+func TestInnovationService_ReorderInnovations(t *testing.T) {
+	repo, service := newTestInnovationService()
+	ctx := context.Background()
+
+	repo.addInnovation(&Innovation{ID: "a", GroupSlug: "digital", Slug: "alpha", Name: "Alpha", Position: 1})
+	repo.addInnovation(&Innovation{ID: "b", GroupSlug: "digital", Slug: "beta", Name: "Beta", Position: 2})
+	repo.addInnovation(&Innovation{ID: "c", GroupSlug: "operasional", Slug: "gamma", Name: "Gamma", Position: 1})
+
+	if err := service.ReorderInnovations(ctx, testEventID, "digital", []string{"b", "a"}); err != nil {
+		t.Fatalf("ReorderInnovations() error = %v", err)
+	}
+	if repo.innovations["b"].Position != 1 || repo.innovations["a"].Position != 2 {
+		t.Errorf("positions = a:%d b:%d, want a:2 b:1", repo.innovations["a"].Position, repo.innovations["b"].Position)
+	}
+
+	invalid := [][]string{
+		{"a"},
+		{"a", "a"},
+		{"a", "c"},
+	}
+	for _, ids := range invalid {
+		if err := service.ReorderInnovations(ctx, testEventID, "digital", ids); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("ReorderInnovations(%v) error = %v, want %v", ids, err, ErrInvalidInput)
+		}
+	}
+}
+
+func TestInnovationService_ArchiveInnovation(t *testing.T) {
+	repo, service := newTestInnovationService()
+	ctx := context.Background()
+
+	repo.addInnovation(&Innovation{ID: "a", GroupSlug: "digital", Slug: "alpha", Name: "Alpha", Position: 1})
+
+	if err := service.ArchiveInnovation(ctx, "a"); err != nil {
+		t.Fatalf("ArchiveInnovation() error = %v", err)
+	}
+	if _, err := repo.GetInnovationBySlug(ctx, testEventID, "digital", "alpha"); !errors.Is(err, ErrInnovationNotFound) {
+		t.Errorf("archived innovation still public, error = %v", err)
+	}
+
+	all, err := service.ListInnovations(ctx, testEventID)
+	if err != nil {
+		t.Fatalf("ListInnovations() error = %v", err)
+	}
+	if len(all) != 1 || all[0].ArchivedAt == nil {
+		t.Errorf("ListInnovations() should include the archived innovation")
+	}
+
+	if err := service.RestoreInnovation(ctx, "a"); err != nil {
+		t.Fatalf("RestoreInnovation() error = %v", err)
+	}
+	if _, err := repo.GetInnovationBySlug(ctx, testEventID, "digital", "alpha"); err != nil {
+		t.Errorf("restored innovation not public, error = %v", err)
+	}
+
+	if err := service.ArchiveInnovation(ctx, "missing"); !errors.Is(err, ErrInnovationNotFound) {
+		t.Errorf("ArchiveInnovation() missing error = %v, want %v", err, ErrInnovationNotFound)
+	}
+}
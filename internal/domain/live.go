@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// DefaultLiveBuffer is the number of updates queued per live client before it
+// is considered too slow and dropped
+const DefaultLiveBuffer = 64
+
+// VoteUpdate is pushed to live dashboards when a vote is recorded. Updates
+// can arrive out of order under concurrent votes, so clients keep the highest
+// count seen per innovation.
+type VoteUpdate struct {
+	EventID      string    `json:"event_id"`
+	InnovationID string    `json:"innovation_id"`
+	GroupSlug    string    `json:"group_slug"`
+	Slug         string    `json:"slug"`
+	VoteCount    int64     `json:"vote_count"`
+	RecordedAt   time.Time `json:"recorded_at"`
+}
+
+// VotePublisher receives updates from the vote path. PublishVote must not block.
+type VotePublisher interface {
+	PublishVote(update VoteUpdate)
+}
+
+// VoteBroadcaster fans vote updates out to live subscribers in this process.
+// Each subscriber has its own buffer; a subscriber whose buffer is full is
+// dropped rather than slowing down voting.
+type VoteBroadcaster struct {
+	buffer int
+	logger *slog.Logger
+
+	mu          sync.Mutex
+	subscribers map[*VoteSubscription]struct{}
+	closed      bool
+}
+
+// VoteSubscription receives the updates of one event
+type VoteSubscription struct {
+	eventID string
+	updates chan VoteUpdate
+	dropped bool
+}
+
+// NewVoteBroadcaster creates a VoteBroadcaster with the given per-subscriber
+// buffer. A buffer of 0 uses DefaultLiveBuffer.
+func NewVoteBroadcaster(buffer int, logger *slog.Logger) *VoteBroadcaster {
+	if buffer <= 0 {
+		buffer = DefaultLiveBuffer
+	}
+	return &VoteBroadcaster{
+		buffer:      buffer,
+		logger:      logger,
+		subscribers: make(map[*VoteSubscription]struct{}),
+	}
+}
+
+// Subscribe registers a subscriber for the event's updates. The subscription's
+// channel is closed when it is dropped, unsubscribed or the broadcaster closes.
+func (b *VoteBroadcaster) Subscribe(eventID string) *VoteSubscription {
+	sub := &VoteSubscription{
+		eventID: eventID,
+		updates: make(chan VoteUpdate, b.buffer),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		close(sub.updates)
+		return sub
+	}
+	b.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// Unsubscribe removes the subscriber; it is safe to call more than once
+func (b *VoteBroadcaster) Unsubscribe(sub *VoteSubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.remove(sub)
+}
+
+// PublishVote queues the update for every subscriber of its event
+func (b *VoteBroadcaster) PublishVote(update VoteUpdate) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers {
+		if sub.eventID != update.EventID {
+			continue
+		}
+		select {
+		case sub.updates <- update:
+		default:
+			sub.dropped = true
+			b.remove(sub)
+			b.logger.Warn("dropped slow live subscriber",
+				"event_id", sub.eventID,
+				"buffer", b.buffer)
+		}
+	}
+}
+
+// Subscribers returns the number of live subscribers
+func (b *VoteBroadcaster) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers)
+}
+
+// Close ends every subscription so streaming handlers can return on shutdown
+func (b *VoteBroadcaster) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subscribers {
+		b.remove(sub)
+	}
+}
+
+// remove closes and forgets a subscriber; b.mu must be held
+func (b *VoteBroadcaster) remove(sub *VoteSubscription) {
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+	delete(b.subscribers, sub)
+	close(sub.updates)
+}
+
+// Updates returns the channel of queued updates
+func (s *VoteSubscription) Updates() <-chan VoteUpdate {
+	return s.updates
+}
+
+// Dropped reports whether the subscription was ended for falling behind. It
+// is meaningful once Updates is closed.
+func (s *VoteSubscription) Dropped() bool {
+	return s.dropped
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+)
+
+func TestVoteBroadcaster(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	t.Run("delivers updates of the subscribed event", func(t *testing.T) {
+		b := NewVoteBroadcaster(4, logger)
+		sub := b.Subscribe("e1")
+		other := b.Subscribe("e2")
+
+		b.PublishVote(VoteUpdate{EventID: "e1", InnovationID: "a", VoteCount: 1})
+
+		select {
+		case update := <-sub.Updates():
+			if update.InnovationID != "a" || update.VoteCount != 1 {
+				t.Errorf("update = %+v, want innovation a with 1 vote", update)
+			}
+		default:
+			t.Fatal("expected an update for e1")
+		}
+		if len(other.Updates()) != 0 {
+			t.Error("e2 subscriber received an e1 update")
+		}
+	})
+
+	t.Run("drops a slow subscriber", func(t *testing.T) {
+		b := NewVoteBroadcaster(2, logger)
+		slow := b.Subscribe("e1")
+		fast := b.Subscribe("e1")
+
+		for i := int64(1); i <= 3; i++ {
+			b.PublishVote(VoteUpdate{EventID: "e1", VoteCount: i})
+			<-fast.Updates()
+		}
+
+		var received int
+		for range slow.Updates() {
+			received++
+		}
+		if received != 2 {
+			t.Errorf("slow subscriber received %d updates, want the 2 buffered ones", received)
+		}
+		if !slow.Dropped() {
+			t.Error("expected slow subscriber to be dropped")
+		}
+		if fast.Dropped() || b.Subscribers() != 1 {
+			t.Errorf("subscribers = %d, fast dropped = %v; want 1 and false", b.Subscribers(), fast.Dropped())
+		}
+	})
+
+	t.Run("unsubscribe and close end subscriptions", func(t *testing.T) {
+		b := NewVoteBroadcaster(0, logger)
+		sub := b.Subscribe("e1")
+		b.Unsubscribe(sub)
+		b.Unsubscribe(sub)
+		if _, ok := <-sub.Updates(); ok {
+			t.Error("expected closed channel after Unsubscribe")
+		}
+
+		open := b.Subscribe("e1")
+		b.Close()
+		if _, ok := <-open.Updates(); ok {
+			t.Error("expected closed channel after Close")
+		}
+		if open.Dropped() {
+			t.Error("Close should not mark subscribers as dropped")
+		}
+
+		late := b.Subscribe("e1")
+		if _, ok := <-late.Updates(); ok {
+			t.Error("expected closed channel when subscribing after Close")
+		}
+		b.PublishVote(VoteUpdate{EventID: "e1"})
+	})
+}
+
+func TestVoteService_SubmitVote_PublishesUpdate(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	repo := newMockRepository()
+	live := &mockVotePublisher{}
+	service := NewVoteService(repo, &mockIPHasher{}, &mockAuditRecorder{}, live, VoterDedupe{}, logger)
+	ctx := context.Background()
+
+	repo.addInnovation(&Innovation{ID: "a", GroupSlug: "g1", Slug: "a", Name: "A"})
+
+	req := VoteRequest{EventID: testEventID, GroupSlug: "g1", Slug: "a", ClientIP: "10.0.0.1"}
+	if _, err := service.SubmitVote(ctx, req); err != nil {
+		t.Fatalf("SubmitVote() error = %v", err)
+	}
+	// A duplicate vote changes nothing and is not published
+	if _, err := service.SubmitVote(ctx, req); err != nil {
+		t.Fatalf("SubmitVote() error = %v", err)
+	}
+
+	if len(live.updates) != 1 {
+		t.Fatalf("published %d updates, want 1", len(live.updates))
+	}
+	update := live.updates[0]
+	if update.EventID != testEventID || update.InnovationID != "a" || update.GroupSlug != "g1" || update.VoteCount != 1 {
+		t.Errorf("update = %+v, want event %s, innovation a in g1 with 1 vote", update, testEventID)
+	}
+}
@@ -1,42 +1,118 @@
 package domain
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event represents a competition round that owns groups, innovations and votes
+type Event struct {
+	ID                string       `json:"id"`
+	Slug              string       `json:"slug"`
+	Name              string       `json:"name"`
+	IsDefault         bool         `json:"is_default"`
+	VotePolicy        string       `json:"vote_policy"`
+	VoterVerification string       `json:"voter_verification"`
+	Window            VotingWindow `json:"window"`
+	CreatedAt         time.Time    `json:"created_at"`
+	UpdatedAt         time.Time    `json:"updated_at"`
+}
+
+// Group represents a category of innovations within an event
+type Group struct {
+	EventID  string `json:"event_id"`
+	Slug     string `json:"slug"`
+	Name     string `json:"name"`
+	Position int    `json:"position"`
+}
 
 // Innovation represents an innovation entry
 type Innovation struct {
-	ID                string    `json:"id"`
-	GroupSlug         string    `json:"group_slug"`
-	Slug              string    `json:"slug"`
-	Name              string    `json:"name"`
-	Division          *string   `json:"division,omitempty"`
-	EntityName        *string   `json:"entity_name,omitempty"`
-	PIC               *string   `json:"pic,omitempty"`
-	Description       *string   `json:"description,omitempty"`
-	LogoInnovationURL *string   `json:"logo_innovation_url,omitempty"`
-	LogoEntityURL     *string   `json:"logo_entity_url,omitempty"`
-	VideoURL          *string   `json:"video_url,omitempty"`
-	SlideURL          *string   `json:"slide_url,omitempty"`
-	IgURL             *string   `json:"ig_url,omitempty"`
-	YtURL             *string   `json:"yt_url,omitempty"`
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
-}
-
-// Vote represents a vote record
+	ID                string     `json:"id"`
+	EventID           string     `json:"event_id"`
+	GroupSlug         string     `json:"group_slug"`
+	Slug              string     `json:"slug"`
+	Name              string     `json:"name"`
+	Division          *string    `json:"division,omitempty"`
+	EntityName        *string    `json:"entity_name,omitempty"`
+	PIC               *string    `json:"pic,omitempty"`
+	Description       *string    `json:"description,omitempty"`
+	LogoInnovationURL *string    `json:"logo_innovation_url,omitempty"`
+	LogoEntityURL     *string    `json:"logo_entity_url,omitempty"`
+	VideoURL          *string    `json:"video_url,omitempty"`
+	SlideURL          *string    `json:"slide_url,omitempty"`
+	IgURL             *string    `json:"ig_url,omitempty"`
+	YtURL             *string    `json:"yt_url,omitempty"`
+	HeroURL           *string    `json:"hero_url,omitempty"`
+	HeroMobileURL     *string    `json:"hero_mobile_url,omitempty"`
+	Position          int        `json:"position"`
+	ArchivedAt        *time.Time `json:"archived_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// Vote represents a vote record. VoterKey deduplicates votes: the contact
+// hash for verified voters, the voter-token hash when the dedupe mode uses
+// tokens, the IP hash otherwise. VoterTokenHash is nil when the voter sent no
+// token. SubnetHash groups votes by network for fraud analysis.
 type Vote struct {
-	ID           int64     `json:"id"`
-	InnovationID string    `json:"innovation_id"`
-	VoterIPHash  []byte    `json:"-"`
-	UserAgent    string    `json:"user_agent,omitempty"`
-	CreatedAt    time.Time `json:"created_at"`
+	ID             int64     `json:"id"`
+	EventID        string    `json:"event_id"`
+	InnovationID   string    `json:"innovation_id"`
+	ScopeKey       string    `json:"scope_key"`
+	VoterKey       []byte    `json:"-"`
+	VoterIPHash    []byte    `json:"-"`
+	VoterTokenHash []byte    `json:"-"`
+	SubnetHash     []byte    `json:"-"`
+	UserAgent      string    `json:"user_agent,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// InnovationResult is an innovation with its vote tally. The count fields keep
+// their Go names in JSON because the dashboard reads them that way.
+type InnovationResult struct {
+	*Innovation
+	VoteCount int64
+	// VotePercentage is VoteCount relative to the event's highest count
+	VotePercentage float64
+}
+
+// EventResults holds the vote tallies of an event's active innovations
+type EventResults struct {
+	TotalInnovations int64
+	TotalVotes       int64
+	// TotalVoters counts distinct voter IP hashes across the event, even
+	// when the results are limited to one group
+	TotalVoters int64
+	MaxVotes    int64
+	Innovations []*InnovationResult
+	// Groups ranks the innovations within each group, in group order
+	Groups []*GroupRanking
 }
 
-// VoteRequest represents a vote submission request
+// VoteRequest represents a vote submission request. ContactHash is the
+// voter's verified contact, required by events that verify voters with
+// one-time codes. VoterToken is the verified voter-token cookie, if any.
 type VoteRequest struct {
-	GroupSlug string
-	Slug      string
-	ClientIP  string
-	UserAgent string
+	EventID     string
+	GroupSlug   string
+	Slug        string
+	ClientIP    string
+	ContactHash []byte
+	VoterToken  string
+	UserAgent   string
+}
+
+// Voter returns the identity of the voter behind the request
+func (r VoteRequest) Voter() VoterIdentity {
+	return VoterIdentity{ClientIP: r.ClientIP, ContactHash: r.ContactHash, Token: r.VoterToken}
+}
+
+// VoterIdentity holds what is known about the voter behind a request
+type VoterIdentity struct {
+	ClientIP    string
+	ContactHash []byte
+	Token       string
 }
 
 // VoteResponse represents the result of a vote operation
@@ -46,3 +122,123 @@ type VoteResponse struct {
 	VoteCount    int64  `json:"vote_count"`
 	Message      string `json:"message,omitempty"`
 }
+
+// VotingStatus describes the state of the voting window at a point in time
+type VotingStatus string
+
+const (
+	VotingStatusOpen       VotingStatus = "open"
+	VotingStatusPaused     VotingStatus = "paused"
+	VotingStatusNotStarted VotingStatus = "not_started"
+	VotingStatusEnded      VotingStatus = "ended"
+)
+
+// VotingWindow represents the period during which votes are accepted
+type VotingWindow struct {
+	OpensAt   *time.Time `json:"opens_at,omitempty"`
+	ClosesAt  *time.Time `json:"closes_at,omitempty"`
+	Paused    bool       `json:"paused"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// Status returns the state of the window at the given time
+func (w *VotingWindow) Status(now time.Time) VotingStatus {
+	switch {
+	case w.Paused:
+		return VotingStatusPaused
+	case w.OpensAt != nil && now.Before(*w.OpensAt):
+		return VotingStatusNotStarted
+	case w.ClosesAt != nil && !now.Before(*w.ClosesAt):
+		return VotingStatusEnded
+	default:
+		return VotingStatusOpen
+	}
+}
+
+// IsOpen reports whether votes are accepted at the given time
+func (w *VotingWindow) IsOpen(now time.Time) bool {
+	return w.Status(now) == VotingStatusOpen
+}
+
+// AdminRole is the permission level of an admin account
+type AdminRole string
+
+const (
+	// AdminRoleViewer can read analytics
+	AdminRoleViewer AdminRole = "viewer"
+	// AdminRoleOperator can also open/close voting and edit innovations
+	AdminRoleOperator AdminRole = "operator"
+	// AdminRoleSuperadmin can also manage admin accounts
+	AdminRoleSuperadmin AdminRole = "superadmin"
+)
+
+// IsValid reports whether r is a known role
+func (r AdminRole) IsValid() bool {
+	return r.rank() > 0
+}
+
+// Allows reports whether r grants at least the permissions of required
+func (r AdminRole) Allows(required AdminRole) bool {
+	return r.rank() > 0 && r.rank() >= required.rank()
+}
+
+func (r AdminRole) rank() int {
+	switch r {
+	case AdminRoleViewer:
+		return 1
+	case AdminRoleOperator:
+		return 2
+	case AdminRoleSuperadmin:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// AdminUser represents an admin account
+type AdminUser struct {
+	ID           string     `json:"id"`
+	Username     string     `json:"username"`
+	PasswordHash string     `json:"-"`
+	Role         AdminRole  `json:"role"`
+	DisabledAt   *time.Time `json:"disabled_at,omitempty"`
+	LastLoginAt  *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// AdminSession represents a logged-in admin browser. Username and Role are
+// read from the account so role changes apply to open sessions.
+type AdminSession struct {
+	ID         string     `json:"id"`
+	TokenHash  []byte     `json:"-"`
+	UserID     string     `json:"user_id"`
+	Username   string     `json:"username"`
+	Role       AdminRole  `json:"role"`
+	UserAgent  string     `json:"user_agent"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// IsActive reports whether the session can still be used at the given time
+func (s *AdminSession) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// AuditEvent is one entry of the append-only audit log. Before and After hold
+// the JSON state of the target around the change, when there is one.
+type AuditEvent struct {
+	ID         int64           `json:"id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	ActorID    *string         `json:"actor_id,omitempty"`
+	Actor      string          `json:"actor"`
+	Action     AuditAction     `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   string          `json:"target_id"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+	RequestID  string          `json:"request_id,omitempty"`
+	IPHash     []byte          `json:"ip_hash,omitempty"`
+}
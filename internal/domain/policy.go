@@ -0,0 +1,92 @@
+package domain
+
+import "fmt"
+
+// Vote policy names as stored in events.vote_policy
+const (
+	VotePolicyGlobal        = "global"
+	VotePolicyPerGroup      = "per-group"
+	VotePolicyPerInnovation = "per-innovation"
+)
+
+// VotePolicy decides the scope a vote counts against. A voter may cast one
+// vote per scope within an event; the database enforces this with a unique
+// constraint on (event_id, scope_key, voter_key). The voter key is the IP hash,
+// voter token or verified contact, so messages speak of voters, not IPs.
+type VotePolicy interface {
+	// Name returns the policy name as stored on the event
+	Name() string
+	// ScopeKey returns the key of the scope a vote for the innovation counts against
+	ScopeKey(innovation *Innovation) string
+	// Rule describes the limit to voters, e.g. "1 vote per kategori"
+	Rule() string
+	// AlreadyVotedMessage explains the limit to a voter who already voted for
+	// the given innovation within the scope (nil when unknown)
+	AlreadyVotedMessage(voted *Innovation) string
+}
+
+// NewVotePolicy returns the policy with the given name
+func NewVotePolicy(name string) (VotePolicy, error) {
+	switch name {
+	case VotePolicyGlobal, "":
+		return globalPolicy{}, nil
+	case VotePolicyPerGroup:
+		return perGroupPolicy{}, nil
+	case VotePolicyPerInnovation:
+		return perInnovationPolicy{}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown vote policy %q", ErrInvalidInput, name)
+	}
+}
+
+// globalPolicy allows one vote per voter in the whole event
+type globalPolicy struct{}
+
+func (globalPolicy) Name() string { return VotePolicyGlobal }
+
+func (globalPolicy) ScopeKey(*Innovation) string { return "global" }
+
+func (globalPolicy) Rule() string { return "1 vote per pengguna" }
+
+func (globalPolicy) AlreadyVotedMessage(voted *Innovation) string {
+	if voted == nil {
+		return "Anda sudah pernah vote untuk inovasi lain. Anda hanya dapat memberikan 1 suara."
+	}
+	return fmt.Sprintf("Anda sudah pernah vote untuk '%s'. Anda hanya dapat memberikan 1 suara.", voted.Name)
+}
+
+// perGroupPolicy allows one vote per voter in each group (category)
+type perGroupPolicy struct{}
+
+func (perGroupPolicy) Name() string { return VotePolicyPerGroup }
+
+func (perGroupPolicy) ScopeKey(innovation *Innovation) string {
+	return "group:" + innovation.GroupSlug
+}
+
+func (perGroupPolicy) Rule() string { return "1 vote per kategori" }
+
+func (perGroupPolicy) AlreadyVotedMessage(voted *Innovation) string {
+	if voted == nil {
+		return "Anda sudah pernah vote di kategori ini. Anda hanya dapat memberikan 1 suara per kategori."
+	}
+	return fmt.Sprintf("Anda sudah pernah vote untuk '%s' di kategori ini. Anda hanya dapat memberikan 1 suara per kategori.", voted.Name)
+}
+
+// perInnovationPolicy allows one vote per voter for each innovation
+type perInnovationPolicy struct{}
+
+func (perInnovationPolicy) Name() string { return VotePolicyPerInnovation }
+
+func (perInnovationPolicy) ScopeKey(innovation *Innovation) string {
+	return "innovation:" + innovation.ID
+}
+
+func (perInnovationPolicy) Rule() string { return "1 vote per inovasi" }
+
+func (perInnovationPolicy) AlreadyVotedMessage(voted *Innovation) string {
+	if voted == nil {
+		return "Anda sudah pernah vote untuk inovasi ini. Anda hanya dapat memberikan 1 suara per inovasi."
+	}
+	return fmt.Sprintf("Anda sudah pernah vote untuk '%s'. Anda hanya dapat memberikan 1 suara per inovasi.", voted.Name)
+}
@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Proof-of-work defaults
+const (
+	DefaultPoWDifficulty = 16
+	DefaultPoWTTL        = 5 * time.Minute
+)
+
+// ProofOfWork is a self-hosted ChallengeVerifier. The client searches for an
+// answer such that SHA-256(token + ":" + answer) starts with Difficulty zero
+// bits. Tokens are signed and bound to the client IP, so issuing them needs no
+// storage; solved tokens are recorded in the repository until they expire so
+// each one buys a single vote across every replica. The signer must not sign
+// any other kind of token.
+type ProofOfWork struct {
+	repo       ChallengeRepository
+	signer     TokenSigner
+	difficulty int
+	ttl        time.Duration
+	now        func() time.Time
+}
+
+// NewProofOfWork creates a ProofOfWork verifier. Zero difficulty or ttl use
+// the defaults.
+func NewProofOfWork(repo ChallengeRepository, signer TokenSigner, difficulty int, ttl time.Duration) *ProofOfWork {
+	if difficulty <= 0 {
+		difficulty = DefaultPoWDifficulty
+	}
+	if ttl <= 0 {
+		ttl = DefaultPoWTTL
+	}
+	return &ProofOfWork{
+		repo:       repo,
+		signer:     signer,
+		difficulty: difficulty,
+		ttl:        ttl,
+		now:        time.Now,
+	}
+}
+
+func (p *ProofOfWork) Issue(ctx context.Context, clientIP string) (*Challenge, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate challenge nonce: %w", err)
+	}
+
+	expiresAt := p.now().Add(p.ttl)
+	payload := fmt.Sprintf("pow.%s.%d.%d.%s", base64.RawURLEncoding.EncodeToString(nonce), p.difficulty, expiresAt.Unix(), powClient(clientIP))
+
+	return &Challenge{
+		Kind:       ChallengeProofOfWork,
+		Token:      p.signer.Sign(payload),
+		Difficulty: p.difficulty,
+		ExpiresAt:  expiresAt,
+	}, nil
+}
+
+func (p *ProofOfWork) Verify(ctx context.Context, solution ChallengeSolution, clientIP string) error {
+	payload, ok := p.signer.Verify(solution.Token)
+	if !ok {
+		return fmt.Errorf("%w: invalid token", ErrChallengeFailed)
+	}
+
+	// payload is pow.<nonce>.<difficulty>.<expiry>.<client>
+	parts := strings.Split(payload, ".")
+	if len(parts) != 5 || parts[0] != "pow" {
+		return fmt.Errorf("%w: invalid token", ErrChallengeFailed)
+	}
+	if parts[4] != powClient(clientIP) {
+		return fmt.Errorf("%w: issued to another client", ErrChallengeFailed)
+	}
+	difficulty, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return fmt.Errorf("%w: invalid token", ErrChallengeFailed)
+	}
+	expiry, err := strconv.ParseInt(parts[3], 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid token", ErrChallengeFailed)
+	}
+	expiresAt := time.Unix(expiry, 0)
+
+	now := p.now()
+	if !now.Before(expiresAt) {
+		return fmt.Errorf("%w: expired", ErrChallengeFailed)
+	}
+	if leadingZeroBits(sha256.Sum256([]byte(solution.Token+":"+solution.Answer))) < difficulty {
+		return fmt.Errorf("%w: wrong answer", ErrChallengeFailed)
+	}
+
+	tokenHash := sha256.Sum256([]byte(solution.Token))
+	fresh, err := p.repo.UseChallengeToken(ctx, tokenHash[:], expiresAt)
+	if err != nil {
+		return fmt.Errorf("record solved challenge: %w", err)
+	}
+	if !fresh {
+		return fmt.Errorf("%w: already used", ErrChallengeFailed)
+	}
+	return nil
+}
+
+// Release forgets a solved token so it can be sent again
+func (p *ProofOfWork) Release(ctx context.Context, solution ChallengeSolution) error {
+	tokenHash := sha256.Sum256([]byte(solution.Token))
+	if err := p.repo.ReleaseChallengeToken(ctx, tokenHash[:]); err != nil {
+		return fmt.Errorf("release solved challenge: %w", err)
+	}
+	return nil
+}
+
+// powClient identifies the client a token is issued to without putting its
+// IP in the token
+func powClient(clientIP string) string {
+	sum := sha256.Sum256([]byte(clientIP))
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+func leadingZeroBits(sum [sha256.Size]byte) int {
+	n := 0
+	for _, b := range sum {
+		if b != 0 {
+			return n + bits.LeadingZeros8(b)
+		}
+		n += 8
+	}
+	return n
+}
@@ -0,0 +1,105 @@
+package domain
+
+import "sort"
+
+// GroupRanking ranks the innovations of one group. Awards are given per
+// group, so shares and gaps are relative to the group, not the event.
+type GroupRanking struct {
+	GroupSlug  string `json:"group_slug"`
+	GroupName  string `json:"group_name"`
+	TotalVotes int64  `json:"total_votes"`
+	// LeadMargin is how many votes first place is ahead of the next place;
+	// 0 when first place is tied
+	LeadMargin int64               `json:"lead_margin"`
+	Entries    []*RankedInnovation `json:"entries"`
+}
+
+// RankedInnovation is an innovation's place within its group
+type RankedInnovation struct {
+	*InnovationResult
+	// Rank uses standard competition ranking: tied entries share a rank and
+	// the next rank is skipped (1, 1, 3)
+	Rank int `json:"rank"`
+	// GroupShare is the percentage of the group's votes
+	GroupShare float64 `json:"group_share"`
+	// GapToNext is how many votes are needed to reach the next better place;
+	// 0 for first place
+	GapToNext int64 `json:"gap_to_next"`
+	Tied      bool  `json:"tied"`
+}
+
+// rankGroups ranks results within each group. Groups follow the given order;
+// groups without innovations are left out, and innovations of unknown groups
+// get a group named after the slug.
+func rankGroups(groups []*Group, results []*InnovationResult) []*GroupRanking {
+	byGroup := make(map[string]*GroupRanking)
+	var rankings []*GroupRanking
+
+	for _, group := range groups {
+		ranking := &GroupRanking{GroupSlug: group.Slug, GroupName: group.Name}
+		byGroup[group.Slug] = ranking
+		rankings = append(rankings, ranking)
+	}
+
+	for _, result := range results {
+		ranking, ok := byGroup[result.GroupSlug]
+		if !ok {
+			ranking = &GroupRanking{GroupSlug: result.GroupSlug, GroupName: result.GroupSlug}
+			byGroup[result.GroupSlug] = ranking
+			rankings = append(rankings, ranking)
+		}
+		ranking.TotalVotes += result.VoteCount
+		ranking.Entries = append(ranking.Entries, &RankedInnovation{InnovationResult: result})
+	}
+
+	nonEmpty := rankings[:0]
+	for _, ranking := range rankings {
+		if len(ranking.Entries) > 0 {
+			ranking.rank()
+			nonEmpty = append(nonEmpty, ranking)
+		}
+	}
+	return nonEmpty
+}
+
+// rank orders the entries by votes and fills rank, share, gap and ties
+func (g *GroupRanking) rank() {
+	entries := g.Entries
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].VoteCount != entries[j].VoteCount {
+			return entries[i].VoteCount > entries[j].VoteCount
+		}
+		if entries[i].Position != entries[j].Position {
+			return entries[i].Position < entries[j].Position
+		}
+		return entries[i].Name < entries[j].Name
+	})
+
+	// betterCount is the vote count of the closest better place
+	var betterCount int64
+	for i, entry := range entries {
+		switch {
+		case i == 0:
+			entry.Rank = 1
+		case entry.VoteCount == entries[i-1].VoteCount:
+			entry.Rank = entries[i-1].Rank
+			entry.Tied = true
+			entries[i-1].Tied = true
+		default:
+			entry.Rank = i + 1
+			betterCount = entries[i-1].VoteCount
+		}
+
+		if entry.Rank > 1 {
+			entry.GapToNext = betterCount - entry.VoteCount
+		}
+		if g.TotalVotes > 0 {
+			entry.GroupShare = float64(entry.VoteCount) / float64(g.TotalVotes) * 100
+		}
+	}
+
+	// A tie for first place leads by nothing
+	if len(entries) > 1 {
+		g.LeadMargin = entries[0].VoteCount - entries[1].VoteCount
+	}
+}
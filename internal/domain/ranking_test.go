@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"os"
+	"testing"
+)
+
+func rankingResult(id, group string, position int, votes int64) *InnovationResult {
+	return &InnovationResult{
+		Innovation: &Innovation{ID: id, GroupSlug: group, Slug: id, Name: id, Position: position},
+		VoteCount:  votes,
+	}
+}
+
+func TestRankGroups(t *testing.T) {
+	groups := []*Group{
+		{Slug: "g1", Name: "Group 1", Position: 1},
+		{Slug: "g2", Name: "Group 2", Position: 2},
+		{Slug: "empty", Name: "Empty", Position: 3},
+		{Slug: "g3", Name: "Group 3", Position: 4},
+	}
+	results := []*InnovationResult{
+		rankingResult("a", "g1", 1, 3),
+		rankingResult("b", "g1", 2, 5),
+		rankingResult("c", "g1", 3, 3),
+		rankingResult("d", "g1", 4, 1),
+		rankingResult("e", "g2", 1, 0),
+		rankingResult("f", "g2", 2, 0),
+		rankingResult("g", "g3", 1, 5),
+		rankingResult("h", "g3", 2, 3),
+		rankingResult("i", "g3", 3, 5),
+		rankingResult("x", "orphan", 1, 2),
+	}
+
+	rankings := rankGroups(groups, results)
+
+	if len(rankings) != 4 {
+		t.Fatalf("len(rankings) = %d, want 4 (empty group left out)", len(rankings))
+	}
+	if rankings[0].GroupSlug != "g1" || rankings[1].GroupSlug != "g2" || rankings[2].GroupSlug != "g3" ||
+		rankings[3].GroupSlug != "orphan" {
+		t.Errorf("group order = %s, %s, %s, %s; want g1, g2, g3, orphan",
+			rankings[0].GroupSlug, rankings[1].GroupSlug, rankings[2].GroupSlug, rankings[3].GroupSlug)
+	}
+
+	g1 := rankings[0]
+	if g1.TotalVotes != 12 {
+		t.Errorf("g1 TotalVotes = %d, want 12", g1.TotalVotes)
+	}
+	if g1.LeadMargin != 2 {
+		t.Errorf("g1 LeadMargin = %d, want 2", g1.LeadMargin)
+	}
+
+	tests := []struct {
+		id    string
+		rank  int
+		share float64
+		gap   int64
+		tied  bool
+	}{
+		{"b", 1, 5.0 / 12 * 100, 0, false},
+		{"a", 2, 25, 2, true},
+		{"c", 2, 25, 2, true},
+		{"d", 4, 1.0 / 12 * 100, 2, false},
+	}
+	if len(g1.Entries) != len(tests) {
+		t.Fatalf("len(g1.Entries) = %d, want %d", len(g1.Entries), len(tests))
+	}
+	for i, tt := range tests {
+		entry := g1.Entries[i]
+		if entry.ID != tt.id {
+			t.Errorf("Entries[%d] = %s, want %s", i, entry.ID, tt.id)
+			continue
+		}
+		if entry.Rank != tt.rank {
+			t.Errorf("%s Rank = %d, want %d", tt.id, entry.Rank, tt.rank)
+		}
+		if math.Abs(entry.GroupShare-tt.share) > 1e-9 {
+			t.Errorf("%s GroupShare = %.2f, want %.2f", tt.id, entry.GroupShare, tt.share)
+		}
+		if entry.GapToNext != tt.gap {
+			t.Errorf("%s GapToNext = %d, want %d", tt.id, entry.GapToNext, tt.gap)
+		}
+		if entry.Tied != tt.tied {
+			t.Errorf("%s Tied = %v, want %v", tt.id, entry.Tied, tt.tied)
+		}
+	}
+
+	g2 := rankings[1]
+	for _, entry := range g2.Entries {
+		if entry.Rank != 1 || !entry.Tied || entry.GroupShare != 0 {
+			t.Errorf("g2 %s = rank %d, tied %v, share %.1f; want 1, true, 0",
+				entry.ID, entry.Rank, entry.Tied, entry.GroupShare)
+		}
+	}
+	if g2.LeadMargin != 0 {
+		t.Errorf("g2 LeadMargin = %d, want 0 for a tie", g2.LeadMargin)
+	}
+
+	// First place tied above a third entry: [5, 5, 3]
+	g3 := rankings[2]
+	if g3.LeadMargin != 0 {
+		t.Errorf("g3 LeadMargin = %d, want 0 for a tie for first place", g3.LeadMargin)
+	}
+	if ranks := []int{g3.Entries[0].Rank, g3.Entries[1].Rank, g3.Entries[2].Rank}; ranks[0] != 1 || ranks[1] != 1 || ranks[2] != 3 {
+		t.Errorf("g3 ranks = %v, want [1 1 3]", ranks)
+	}
+	if entry := g3.Entries[2]; entry.ID != "h" || entry.GapToNext != 2 || entry.Tied {
+		t.Errorf("g3 third = %s, gap %d, tied %v; want h, 2, false", entry.ID, entry.GapToNext, entry.Tied)
+	}
+
+	if orphan := rankings[3]; len(orphan.Entries) != 1 || orphan.LeadMargin != 0 {
+		t.Errorf("orphan = %d entries, lead %d; want 1 entry leading by 0", len(orphan.Entries), orphan.LeadMargin)
+	}
+}
+
+func TestVoteService_GetResults_GroupFilter(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	repo := newMockRepository()
+	service := NewVoteService(repo, &mockIPHasher{}, &mockAuditRecorder{}, &mockVotePublisher{}, VoterDedupe{}, logger)
+	ctx := context.Background()
+
+	repo.addInnovation(&Innovation{ID: "a", GroupSlug: "g1", Slug: "a", Name: "A"})
+	repo.addInnovation(&Innovation{ID: "b", GroupSlug: "g2", Slug: "b", Name: "B"})
+	repo.votes = append(repo.votes,
+		&Vote{EventID: testEventID, InnovationID: "a", VoterIPHash: []byte("10.0.0.1")},
+		&Vote{EventID: testEventID, InnovationID: "b", VoterIPHash: []byte("10.0.0.2")},
+		&Vote{EventID: testEventID, InnovationID: "b", VoterIPHash: []byte("10.0.0.3")},
+	)
+
+	results, err := service.GetResults(ctx, testEventID, "g1")
+	if err != nil {
+		t.Fatalf("GetResults() error = %v", err)
+	}
+	if len(results.Innovations) != 1 || results.Innovations[0].ID != "a" {
+		t.Fatalf("Innovations = %d entries, want only a", len(results.Innovations))
+	}
+	if results.TotalVotes != 1 || results.MaxVotes != 1 {
+		t.Errorf("totals = %d votes, max %d; want 1 and 1", results.TotalVotes, results.MaxVotes)
+	}
+	if len(results.Groups) != 1 || results.Groups[0].GroupSlug != "g1" {
+		t.Errorf("Groups = %d, want only g1", len(results.Groups))
+	}
+
+	if _, err := service.GetResults(ctx, testEventID, "missing"); !errors.Is(err, ErrGroupNotFound) {
+		t.Errorf("GetResults(missing) error = %v, want ErrGroupNotFound", err)
+	}
+}
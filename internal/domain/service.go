@@ -2,39 +2,83 @@ package domain
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 )
 
 // VoteService handles the business logic for voting
 type VoteService interface {
-	GetInnovation(ctx context.Context, groupSlug, slug string) (*Innovation, error)
+	GetEvent(ctx context.Context, eventSlug string) (*Event, error)
+	ListEvents(ctx context.Context) ([]*Event, error)
+	ListGroups(ctx context.Context, eventID string) ([]*Group, error)
+	GetInnovation(ctx context.Context, eventID, groupSlug, slug string) (*Innovation, error)
 	SubmitVote(ctx context.Context, req VoteRequest) (*VoteResponse, error)
 	GetVoteCount(ctx context.Context, innovationID string) (int64, error)
-	ListInnovations(ctx context.Context) ([]*Innovation, error)
-	CheckHasVoted(ctx context.Context, innovationID, clientIP string) (bool, error)
-	GetTotalVoters(ctx context.Context) (int64, error)
+	ListInnovations(ctx context.Context, eventID string) ([]*Innovation, error)
+	CheckHasVoted(ctx context.Context, event *Event, innovation *Innovation, voter VoterIdentity) (bool, error)
+	VotePolicy(event *Event) (VotePolicy, error)
+	GetTotalVoters(ctx context.Context, eventID string) (int64, error)
+	// GetResults returns every active innovation of the event with its vote
+	// count, totals, percentages and per-group rankings. A non-empty groupSlug
+	// limits the results to that group.
+	GetResults(ctx context.Context, eventID, groupSlug string) (*EventResults, error)
+	// GetVoteSeries returns bucketed vote counts of the event's active
+	// innovations with running totals
+	GetVoteSeries(ctx context.Context, event *Event, query VoteSeriesQuery) (*VoteSeries, error)
+	// StreamVotes calls fn for every vote of the event, oldest first
+	StreamVotes(ctx context.Context, eventID string, fn func(*VoteRecord) error) error
+	IsVotingOpen(event *Event) bool
+	UpdateVotingWindow(ctx context.Context, eventID string, window *VotingWindow) error
 }
 
 type voteService struct {
 	repo   Repository
 	hasher IPHasher
+	audit  AuditRecorder
+	live   VotePublisher
+	dedupe VoterDedupe
 	logger *slog.Logger
+	now    func() time.Time
 }
 
-// NewVoteService creates a new VoteService
-func NewVoteService(repo Repository, hasher IPHasher, logger *slog.Logger) VoteService {
+// NewVoteService creates a new VoteService. Recorded votes are published to
+// live; dedupe decides how voters are told apart.
+func NewVoteService(repo Repository, hasher IPHasher, audit AuditRecorder, live VotePublisher, dedupe VoterDedupe, logger *slog.Logger) VoteService {
 	return &voteService{
 		repo:   repo,
 		hasher: hasher,
+		audit:  audit,
+		live:   live,
+		dedupe: dedupe,
 		logger: logger,
+		now:    time.Now,
 	}
 }
 
-func (s *voteService) GetInnovation(ctx context.Context, groupSlug, slug string) (*Innovation, error) {
-	innovation, err := s.repo.GetInnovationBySlug(ctx, groupSlug, slug)
+// GetEvent returns the event with the given slug, or the default event when
+// the slug is empty
+func (s *voteService) GetEvent(ctx context.Context, eventSlug string) (*Event, error) {
+	if eventSlug == "" {
+		return s.repo.GetDefaultEvent(ctx)
+	}
+	return s.repo.GetEventBySlug(ctx, eventSlug)
+}
+
+func (s *voteService) ListEvents(ctx context.Context) ([]*Event, error) {
+	return s.repo.ListEvents(ctx)
+}
+
+func (s *voteService) ListGroups(ctx context.Context, eventID string) ([]*Group, error) {
+	return s.repo.ListGroups(ctx, eventID)
+}
+
+func (s *voteService) GetInnovation(ctx context.Context, eventID, groupSlug, slug string) (*Innovation, error) {
+	innovation, err := s.repo.GetInnovationBySlug(ctx, eventID, groupSlug, slug)
 	if err != nil {
 		s.logger.ErrorContext(ctx, "failed to get innovation",
+			"event_id", eventID,
 			"group_slug", groupSlug,
 			"slug", slug,
 			"error", err)
@@ -44,60 +88,91 @@ func (s *voteService) GetInnovation(ctx context.Context, groupSlug, slug string)
 }
 
 func (s *voteService) SubmitVote(ctx context.Context, req VoteRequest) (*VoteResponse, error) {
-	// Hash IP
-	ipHash := s.hasher.HashIP(req.ClientIP)
-
-	// Check if IP has already voted (globally - one vote per IP)
-	hasVoted, err := s.repo.HasVotedGlobally(ctx, ipHash)
+	// Reject votes outside the event's voting window
+	event, err := s.repo.GetEventByID(ctx, req.EventID)
 	if err != nil {
-		s.logger.ErrorContext(ctx, "failed to check global vote status",
+		s.logger.ErrorContext(ctx, "failed to get event",
+			"event_id", req.EventID,
 			"error", err)
-		return nil, fmt.Errorf("failed to check vote status: %w", err)
+		return nil, err
+	}
+	if !s.IsVotingOpen(event) {
+		return nil, ErrVotingClosed
 	}
 
-	if hasVoted {
-		// Get the innovation they already voted for
-		votedInnovation, err := s.repo.GetVotedInnovation(ctx, ipHash)
-		if err != nil {
-			s.logger.ErrorContext(ctx, "failed to get voted innovation",
-				"error", err)
-			return &VoteResponse{
-				Success:      false,
-				AlreadyVoted: true,
-				VoteCount:    0,
-				Message:      "Anda sudah pernah vote untuk inovasi lain. Hanya 1 vote per IP.",
-			}, nil
-		}
-
-		s.logger.InfoContext(ctx, "duplicate vote attempt - already voted globally",
-			"group_slug", req.GroupSlug,
-			"slug", req.Slug)
-		return &VoteResponse{
-			Success:      false,
-			AlreadyVoted: true,
-			VoteCount:    0,
-			Message:      fmt.Sprintf("Anda sudah pernah vote untuk '%s'. Hanya 1 vote per IP yang diizinkan.", votedInnovation.Name),
-		}, nil
+	policy, err := s.VotePolicy(event)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "invalid vote policy",
+			"event_id", event.ID,
+			"vote_policy", event.VotePolicy,
+			"error", err)
+		return nil, err
 	}
 
 	// Get innovation to vote for
-	innovation, err := s.repo.GetInnovationBySlug(ctx, req.GroupSlug, req.Slug)
+	innovation, err := s.repo.GetInnovationBySlug(ctx, event.ID, req.GroupSlug, req.Slug)
 	if err != nil {
 		s.logger.ErrorContext(ctx, "innovation not found",
+			"event_id", event.ID,
 			"group_slug", req.GroupSlug,
 			"slug", req.Slug,
 			"error", err)
 		return nil, err
 	}
 
+	// Hash IP; verified contacts or voter tokens may identify the voter instead
+	ipHash := s.hasher.HashIP(req.ClientIP)
+	voterKey, err := s.dedupe.voterKey(event, req.Voter(), ipHash)
+	if err != nil {
+		return nil, err
+	}
+	scopeKey := policy.ScopeKey(innovation)
+
+	// Check if the voter has already voted within the policy's scope
+	hasVoted, err := s.repo.HasVotedInScope(ctx, event.ID, scopeKey, voterKey)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to check vote status",
+			"event_id", event.ID,
+			"scope_key", scopeKey,
+			"error", err)
+		return nil, fmt.Errorf("failed to check vote status: %w", err)
+	}
+
+	if hasVoted {
+		s.logger.InfoContext(ctx, "duplicate vote attempt - already voted in scope",
+			"event_id", event.ID,
+			"vote_policy", policy.Name(),
+			"group_slug", req.GroupSlug,
+			"slug", req.Slug)
+		return s.alreadyVoted(ctx, policy, innovation, voterKey)
+	}
+
 	// Insert vote
 	vote := &Vote{
-		InnovationID: innovation.ID,
-		VoterIPHash:  ipHash,
-		UserAgent:    req.UserAgent,
+		EventID:        event.ID,
+		InnovationID:   innovation.ID,
+		ScopeKey:       scopeKey,
+		VoterKey:       voterKey,
+		VoterIPHash:    ipHash,
+		VoterTokenHash: voterTokenHash(req.VoterToken),
+		SubnetHash:     s.hasher.HashSubnet(req.ClientIP),
+		UserAgent:      req.UserAgent,
 	}
 
-	inserted, err := s.repo.InsertVote(ctx, vote)
+	var inserted bool
+	if s.dedupe.capsIP() && event.VoterVerification != VoterVerificationOTP {
+		// One network can only hand out so many voter tokens
+		inserted, err = s.repo.InsertVoteCapped(ctx, vote, s.dedupe.MaxPerIP)
+	} else {
+		inserted, err = s.repo.InsertVote(ctx, vote)
+	}
+	if errors.Is(err, ErrIPVoteCapReached) {
+		s.logger.InfoContext(ctx, "vote rejected - ip vote cap reached",
+			"event_id", event.ID,
+			"scope_key", scopeKey,
+			"max_per_ip", s.dedupe.MaxPerIP)
+		return s.ipCapReached(ctx, innovation)
+	}
 	if err != nil {
 		s.logger.ErrorContext(ctx, "failed to insert vote",
 			"innovation_id", innovation.ID,
@@ -106,12 +181,8 @@ func (s *voteService) SubmitVote(ctx context.Context, req VoteRequest) (*VoteRes
 	}
 
 	if !inserted {
-		return &VoteResponse{
-			Success:      false,
-			AlreadyVoted: true,
-			VoteCount:    0,
-			Message:      "Vote gagal diproses",
-		}, nil
+		// A concurrent request from the same voter won the race
+		return s.alreadyVoted(ctx, policy, innovation, voterKey)
 	}
 
 	// Get current vote count for this innovation
@@ -124,11 +195,21 @@ func (s *voteService) SubmitVote(ctx context.Context, req VoteRequest) (*VoteRes
 	}
 
 	s.logger.InfoContext(ctx, "vote recorded successfully",
+		"event_id", event.ID,
 		"innovation_id", innovation.ID,
 		"group_slug", req.GroupSlug,
 		"slug", req.Slug,
 		"vote_count", count)
 
+	s.live.PublishVote(VoteUpdate{
+		EventID:      event.ID,
+		InnovationID: innovation.ID,
+		GroupSlug:    innovation.GroupSlug,
+		Slug:         innovation.Slug,
+		VoteCount:    count,
+		RecordedAt:   s.now(),
+	})
+
 	return &VoteResponse{
 		Success:      true,
 		AlreadyVoted: false,
@@ -137,36 +218,227 @@ func (s *voteService) SubmitVote(ctx context.Context, req VoteRequest) (*VoteRes
 	}, nil
 }
 
+// alreadyVoted builds the response for a voter who has used their vote in the
+// innovation's scope, reporting the current count of the requested innovation
+func (s *voteService) alreadyVoted(ctx context.Context, policy VotePolicy, innovation *Innovation, voterKey []byte) (*VoteResponse, error) {
+	count, err := s.repo.GetVoteCount(ctx, innovation.ID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to get vote count",
+			"innovation_id", innovation.ID,
+			"error", err)
+		return nil, fmt.Errorf("failed to get vote count: %w", err)
+	}
+
+	votedInnovation, err := s.repo.GetVotedInnovation(ctx, innovation.EventID, policy.ScopeKey(innovation), voterKey)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to get voted innovation",
+			"error", err)
+		votedInnovation = nil
+	}
+	message := policy.AlreadyVotedMessage(votedInnovation)
+
+	return &VoteResponse{
+		Success:      false,
+		AlreadyVoted: true,
+		VoteCount:    count,
+		Message:      message,
+	}, nil
+}
+
+// ipCapReached builds the response for a vote refused because the voter's
+// network has used up its votes in the scope
+func (s *voteService) ipCapReached(ctx context.Context, innovation *Innovation) (*VoteResponse, error) {
+	count, err := s.repo.GetVoteCount(ctx, innovation.ID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to get vote count",
+			"innovation_id", innovation.ID,
+			"error", err)
+		return nil, fmt.Errorf("failed to get vote count: %w", err)
+	}
+
+	return &VoteResponse{
+		Success:      false,
+		AlreadyVoted: true,
+		VoteCount:    count,
+		Message:      "Batas vote dari jaringan Anda sudah tercapai",
+	}, nil
+}
+
 func (s *voteService) GetVoteCount(ctx context.Context, innovationID string) (int64, error) {
 	return s.repo.GetVoteCount(ctx, innovationID)
 }
 
-func (s *voteService) ListInnovations(ctx context.Context) ([]*Innovation, error) {
-	return s.repo.ListInnovations(ctx)
+func (s *voteService) ListInnovations(ctx context.Context, eventID string) ([]*Innovation, error) {
+	return s.repo.ListInnovations(ctx, eventID)
+}
+
+// CheckHasVoted reports whether the voter has used their vote in the scope
+// the innovation belongs to. An unverified voter of an event that verifies
+// voters has not voted.
+func (s *voteService) CheckHasVoted(ctx context.Context, event *Event, innovation *Innovation, voter VoterIdentity) (bool, error) {
+	policy, err := s.VotePolicy(event)
+	if err != nil {
+		return false, err
+	}
+	key, err := s.dedupe.voterKey(event, voter, s.hasher.HashIP(voter.ClientIP))
+	if err != nil {
+		if errors.Is(err, ErrVerificationRequired) {
+			return false, nil
+		}
+		return false, err
+	}
+	return s.repo.HasVotedInScope(ctx, event.ID, policy.ScopeKey(innovation), key)
+}
+
+// VotePolicy returns the vote policy configured for the event
+func (s *voteService) VotePolicy(event *Event) (VotePolicy, error) {
+	return NewVotePolicy(event.VotePolicy)
 }
 
-func (s *voteService) CheckHasVoted(ctx context.Context, innovationID, clientIP string) (bool, error) {
-	ipHash := s.hasher.HashIP(clientIP)
-	return s.repo.HasVotedGlobally(ctx, ipHash)
+func (s *voteService) GetTotalVoters(ctx context.Context, eventID string) (int64, error) {
+	return s.repo.GetTotalVoters(ctx, eventID)
 }
 
-func (s *voteService) GetTotalVoters(ctx context.Context) (int64, error) {
-	return s.repo.GetTotalVoters(ctx)
+func (s *voteService) GetResults(ctx context.Context, eventID, groupSlug string) (*EventResults, error) {
+	groups, err := s.repo.ListGroups(ctx, eventID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to load groups",
+			"event_id", eventID,
+			"error", err)
+		return nil, err
+	}
+
+	if groupSlug != "" {
+		groups = filterGroups(groups, groupSlug)
+		if len(groups) == 0 {
+			return nil, ErrGroupNotFound
+		}
+	}
+
+	results, err := s.repo.GetEventResults(ctx, eventID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to load results",
+			"event_id", eventID,
+			"error", err)
+		return nil, err
+	}
+
+	if groupSlug != "" {
+		filtered := results.Innovations[:0]
+		for _, result := range results.Innovations {
+			if result.GroupSlug == groupSlug {
+				filtered = append(filtered, result)
+			}
+		}
+		results.Innovations = filtered
+	}
+
+	summarizeResults(results)
+	results.Groups = rankGroups(groups, results.Innovations)
+	return results, nil
+}
+
+// filterGroups returns the group with the given slug, if any
+func filterGroups(groups []*Group, slug string) []*Group {
+	for _, group := range groups {
+		if group.Slug == slug {
+			return []*Group{group}
+		}
+	}
+	return nil
+}
+
+// summarizeResults fills the totals, maximum and percentages from the
+// per-innovation counts
+func summarizeResults(results *EventResults) {
+	results.TotalInnovations = int64(len(results.Innovations))
+	results.TotalVotes = 0
+	results.MaxVotes = 0
+	for _, result := range results.Innovations {
+		results.TotalVotes += result.VoteCount
+		if result.VoteCount > results.MaxVotes {
+			results.MaxVotes = result.VoteCount
+		}
+	}
+
+	for _, result := range results.Innovations {
+		result.VotePercentage = 0
+		if results.MaxVotes > 0 {
+			result.VotePercentage = float64(result.VoteCount) / float64(results.MaxVotes) * 100
+		}
+	}
+}
+
+// IsVotingOpen reports whether the event accepts votes right now
+func (s *voteService) IsVotingOpen(event *Event) bool {
+	return event.Window.IsOpen(s.now())
+}
+
+func (s *voteService) UpdateVotingWindow(ctx context.Context, eventID string, window *VotingWindow) error {
+	if window.OpensAt != nil && window.ClosesAt != nil && !window.OpensAt.Before(*window.ClosesAt) {
+		return fmt.Errorf("%w: opens_at must be before closes_at", ErrInvalidInput)
+	}
+
+	event, err := s.repo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	before := event.Window
+
+	if err := s.repo.UpdateEventWindow(ctx, eventID, window); err != nil {
+		s.logger.ErrorContext(ctx, "failed to update voting window",
+			"event_id", eventID,
+			"error", err)
+		return fmt.Errorf("failed to update voting window: %w", err)
+	}
+
+	s.logger.InfoContext(ctx, "voting window updated",
+		"event_id", eventID,
+		"status", window.Status(s.now()),
+		"paused", window.Paused)
+	s.audit.Record(ctx, AuditVotingWindowUpdated, "event", eventID, before, window)
+	return nil
 }
 
 // Repository defines the data access interface
 type Repository interface {
-	GetInnovationBySlug(ctx context.Context, groupSlug, slug string) (*Innovation, error)
+	GetEventBySlug(ctx context.Context, slug string) (*Event, error)
+	GetEventByID(ctx context.Context, id string) (*Event, error)
+	GetDefaultEvent(ctx context.Context) (*Event, error)
+	ListEvents(ctx context.Context) ([]*Event, error)
+	UpdateEventWindow(ctx context.Context, eventID string, window *VotingWindow) error
+	ListGroups(ctx context.Context, eventID string) ([]*Group, error)
+	GetInnovationBySlug(ctx context.Context, eventID, groupSlug, slug string) (*Innovation, error)
 	InsertVote(ctx context.Context, vote *Vote) (bool, error)
+	// InsertVoteCapped inserts vote like InsertVote unless its IP hash already
+	// cast maxPerIP votes in the scope, and then returns ErrIPVoteCapReached.
+	// The count and the insert are atomic.
+	InsertVoteCapped(ctx context.Context, vote *Vote, maxPerIP int) (bool, error)
 	GetVoteCount(ctx context.Context, innovationID string) (int64, error)
-	ListInnovations(ctx context.Context) ([]*Innovation, error)
-	HasVoted(ctx context.Context, innovationID string, voterIPHash []byte) (bool, error)
-	GetTotalVoters(ctx context.Context) (int64, error)
-	HasVotedGlobally(ctx context.Context, voterIPHash []byte) (bool, error)
-	GetVotedInnovation(ctx context.Context, voterIPHash []byte) (*Innovation, error)
+	ListInnovations(ctx context.Context, eventID string) ([]*Innovation, error)
+	GetTotalVoters(ctx context.Context, eventID string) (int64, error)
+	// GetEventResults returns the vote count of every active innovation and
+	// the number of distinct voters; totals and percentages are left to the caller
+	GetEventResults(ctx context.Context, eventID string) (*EventResults, error)
+	// GetVoteBuckets counts the event's votes per innovation and bucket for
+	// votes cast before until; votes before since are returned with a zero Start
+	GetVoteBuckets(ctx context.Context, eventID string, since, until time.Time, bucket time.Duration) ([]*VoteBucket, error)
+	// StreamVotes calls fn for each vote of the event without buffering the result
+	StreamVotes(ctx context.Context, eventID string, fn func(*VoteRecord) error) error
+	HasVotedInScope(ctx context.Context, eventID, scopeKey string, voterKey []byte) (bool, error)
+	GetVotedInnovation(ctx context.Context, eventID, scopeKey string, voterKey []byte) (*Innovation, error)
+	GetInnovationByID(ctx context.Context, id string) (*Innovation, error)
+	ListAllInnovations(ctx context.Context, eventID string) ([]*Innovation, error)
+	SlugExists(ctx context.Context, eventID, groupSlug, slug, excludeID string) (bool, error)
+	CreateInnovation(ctx context.Context, innovation *Innovation) error
+	UpdateInnovation(ctx context.Context, innovation *Innovation) error
+	SetInnovationPositions(ctx context.Context, ids []string) error
+	SetInnovationArchived(ctx context.Context, id string, archived bool) error
 }
 
 // IPHasher defines the interface for IP hashing
 type IPHasher interface {
 	HashIP(ip string) []byte
+	// HashSubnet hashes the /24 (IPv4) or /64 (IPv6) the address belongs to
+	HashSubnet(ip string) []byte
 }
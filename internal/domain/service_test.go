@@ -2,46 +2,330 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
 	"os"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 )
 
+const testEventID = "test-event-id"
+
 // Mock repository for testing
 type mockRepository struct {
-	innovations map[string]*Innovation
-	votes       map[string]bool // key: innovationID + ipHash
-	voteCounts  map[string]int64
+	events      map[string]*Event
+	groups      []*Group
+	innovations map[string]*Innovation // key: innovation ID
+	votes       []*Vote
+	nextID      int
 }
 
 func newMockRepository() *mockRepository {
 	return &mockRepository{
+		events: map[string]*Event{
+			testEventID: {ID: testEventID, Slug: "test-event", Name: "Test Event", IsDefault: true},
+		},
 		innovations: make(map[string]*Innovation),
-		votes:       make(map[string]bool),
-		voteCounts:  make(map[string]int64),
 	}
 }
 
-func (m *mockRepository) GetInnovationBySlug(ctx context.Context, groupSlug, slug string) (*Innovation, error) {
-	key := groupSlug + ":" + slug
-	if innovation, ok := m.innovations[key]; ok {
-		return innovation, nil
+func (m *mockRepository) addInnovation(innovation *Innovation) {
+	if innovation.EventID == "" {
+		innovation.EventID = testEventID
+	}
+	m.innovations[innovation.ID] = innovation
+}
+
+func (m *mockRepository) GetEventBySlug(ctx context.Context, slug string) (*Event, error) {
+	for _, event := range m.events {
+		if event.Slug == slug {
+			return event, nil
+		}
+	}
+	return nil, ErrEventNotFound
+}
+
+func (m *mockRepository) GetEventByID(ctx context.Context, id string) (*Event, error) {
+	if event, ok := m.events[id]; ok {
+		return event, nil
+	}
+	return nil, ErrEventNotFound
+}
+
+func (m *mockRepository) GetDefaultEvent(ctx context.Context) (*Event, error) {
+	for _, event := range m.events {
+		if event.IsDefault {
+			return event, nil
+		}
+	}
+	return nil, ErrEventNotFound
+}
+
+func (m *mockRepository) ListEvents(ctx context.Context) ([]*Event, error) {
+	var events []*Event
+	for _, event := range m.events {
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func (m *mockRepository) UpdateEventWindow(ctx context.Context, eventID string, window *VotingWindow) error {
+	event, ok := m.events[eventID]
+	if !ok {
+		return ErrEventNotFound
+	}
+	event.Window = *window
+	return nil
+}
+
+func (m *mockRepository) ListGroups(ctx context.Context, eventID string) ([]*Group, error) {
+	seen := make(map[string]bool)
+	var groups []*Group
+	for _, group := range m.groups {
+		if group.EventID == eventID {
+			seen[group.Slug] = true
+			groups = append(groups, group)
+		}
+	}
+	for _, innovation := range m.innovations {
+		if innovation.EventID == eventID && !seen[innovation.GroupSlug] {
+			seen[innovation.GroupSlug] = true
+			groups = append(groups, &Group{EventID: eventID, Slug: innovation.GroupSlug, Name: innovation.GroupSlug})
+		}
+	}
+	return groups, nil
+}
+
+func (m *mockRepository) GetInnovationBySlug(ctx context.Context, eventID, groupSlug, slug string) (*Innovation, error) {
+	for _, innovation := range m.innovations {
+		if innovation.EventID == eventID && innovation.GroupSlug == groupSlug && innovation.Slug == slug && innovation.ArchivedAt == nil {
+			return innovation, nil
+		}
 	}
 	return nil, ErrInnovationNotFound
 }
 
 func (m *mockRepository) InsertVote(ctx context.Context, vote *Vote) (bool, error) {
-	key := vote.InnovationID + ":" + string(vote.VoterIPHash)
-	if m.votes[key] {
-		return false, nil // Already voted
+	if m.findVote(vote.EventID, vote.ScopeKey, vote.VoterKey) != nil {
+		return false, nil // Already voted in scope
 	}
-	m.votes[key] = true
-	m.voteCounts[vote.InnovationID]++
+	m.votes = append(m.votes, vote)
 	return true, nil
 }
 
+func (m *mockRepository) InsertVoteCapped(ctx context.Context, vote *Vote, maxPerIP int) (bool, error) {
+	if m.findVote(vote.EventID, vote.ScopeKey, vote.VoterKey) != nil {
+		return false, nil
+	}
+	ipVotes := 0
+	for _, stored := range m.votes {
+		if stored.EventID == vote.EventID && stored.ScopeKey == vote.ScopeKey && string(stored.VoterIPHash) == string(vote.VoterIPHash) {
+			ipVotes++
+		}
+	}
+	if ipVotes >= maxPerIP {
+		return false, ErrIPVoteCapReached
+	}
+	return m.InsertVote(ctx, vote)
+}
+
+func (m *mockRepository) findVote(eventID, scopeKey string, voterKey []byte) *Vote {
+	for _, vote := range m.votes {
+		if vote.EventID == eventID && vote.ScopeKey == scopeKey && string(vote.VoterKey) == string(voterKey) {
+			return vote
+		}
+	}
+	return nil
+}
+
 func (m *mockRepository) GetVoteCount(ctx context.Context, innovationID string) (int64, error) {
-	return m.voteCounts[innovationID], nil
+	var count int64
+	for _, vote := range m.votes {
+		if vote.InnovationID == innovationID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *mockRepository) ListInnovations(ctx context.Context, eventID string) ([]*Innovation, error) {
+	var innovations []*Innovation
+	for _, innovation := range m.innovations {
+		if innovation.EventID == eventID && innovation.ArchivedAt == nil {
+			innovations = append(innovations, innovation)
+		}
+	}
+	return innovations, nil
+}
+
+func (m *mockRepository) ListAllInnovations(ctx context.Context, eventID string) ([]*Innovation, error) {
+	var innovations []*Innovation
+	for _, innovation := range m.innovations {
+		if innovation.EventID == eventID {
+			innovations = append(innovations, innovation)
+		}
+	}
+	return innovations, nil
+}
+
+func (m *mockRepository) GetInnovationByID(ctx context.Context, id string) (*Innovation, error) {
+	if innovation, ok := m.innovations[id]; ok {
+		copied := *innovation
+		return &copied, nil
+	}
+	return nil, ErrInnovationNotFound
+}
+
+func (m *mockRepository) SlugExists(ctx context.Context, eventID, groupSlug, slug, excludeID string) (bool, error) {
+	for _, innovation := range m.innovations {
+		if innovation.EventID == eventID && innovation.GroupSlug == groupSlug && innovation.Slug == slug && innovation.ID != excludeID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockRepository) CreateInnovation(ctx context.Context, innovation *Innovation) error {
+	m.nextID++
+	innovation.ID = fmt.Sprintf("created-%d", m.nextID)
+	for _, existing := range m.innovations {
+		if existing.EventID == innovation.EventID && existing.GroupSlug == innovation.GroupSlug && existing.Position >= innovation.Position {
+			innovation.Position = existing.Position + 1
+		}
+	}
+	if innovation.Position == 0 {
+		innovation.Position = 1
+	}
+	m.innovations[innovation.ID] = innovation
+	return nil
+}
+
+func (m *mockRepository) UpdateInnovation(ctx context.Context, innovation *Innovation) error {
+	if _, ok := m.innovations[innovation.ID]; !ok {
+		return ErrInnovationNotFound
+	}
+	m.innovations[innovation.ID] = innovation
+	return nil
+}
+
+func (m *mockRepository) SetInnovationPositions(ctx context.Context, ids []string) error {
+	for i, id := range ids {
+		innovation, ok := m.innovations[id]
+		if !ok {
+			return ErrInnovationNotFound
+		}
+		innovation.Position = i + 1
+	}
+	return nil
+}
+
+func (m *mockRepository) SetInnovationArchived(ctx context.Context, id string, archived bool) error {
+	innovation, ok := m.innovations[id]
+	if !ok {
+		return ErrInnovationNotFound
+	}
+	innovation.ArchivedAt = nil
+	if archived {
+		now := time.Now()
+		innovation.ArchivedAt = &now
+	}
+	return nil
+}
+
+func (m *mockRepository) GetTotalVoters(ctx context.Context, eventID string) (int64, error) {
+	voters := make(map[string]bool)
+	for _, vote := range m.votes {
+		if vote.EventID == eventID {
+			voters[string(vote.VoterIPHash)] = true
+		}
+	}
+	return int64(len(voters)), nil
+}
+
+func (m *mockRepository) GetEventResults(ctx context.Context, eventID string) (*EventResults, error) {
+	innovations, _ := m.ListInnovations(ctx, eventID)
+	sort.Slice(innovations, func(i, j int) bool { return innovations[i].ID < innovations[j].ID })
+
+	results := &EventResults{}
+	for _, innovation := range innovations {
+		count, _ := m.GetVoteCount(ctx, innovation.ID)
+		results.Innovations = append(results.Innovations, &InnovationResult{Innovation: innovation, VoteCount: count})
+	}
+	results.TotalVoters, _ = m.GetTotalVoters(ctx, eventID)
+	return results, nil
+}
+
+func (m *mockRepository) GetVoteBuckets(ctx context.Context, eventID string, since, until time.Time, bucket time.Duration) ([]*VoteBucket, error) {
+	counts := make(map[VoteBucket]int64)
+	for _, vote := range m.votes {
+		if vote.EventID != eventID || !vote.CreatedAt.Before(until) {
+			continue
+		}
+		key := VoteBucket{InnovationID: vote.InnovationID}
+		if !vote.CreatedAt.Before(since) {
+			key.Start = since.Add(vote.CreatedAt.Sub(since) / bucket * bucket)
+		}
+		counts[key]++
+	}
+
+	var buckets []*VoteBucket
+	for key, count := range counts {
+		buckets = append(buckets, &VoteBucket{InnovationID: key.InnovationID, Start: key.Start, Count: count})
+	}
+	return buckets, nil
+}
+
+func (m *mockRepository) StreamVotes(ctx context.Context, eventID string, fn func(*VoteRecord) error) error {
+	for i, vote := range m.votes {
+		if vote.EventID != eventID {
+			continue
+		}
+		record := &VoteRecord{
+			ID:           int64(i + 1),
+			InnovationID: vote.InnovationID,
+			CreatedAt:    vote.CreatedAt,
+			VoterIPHash:  vote.VoterIPHash,
+			UserAgent:    vote.UserAgent,
+		}
+		if innovation, ok := m.innovations[vote.InnovationID]; ok {
+			record.GroupSlug = innovation.GroupSlug
+			record.InnovationSlug = innovation.Slug
+			record.InnovationName = innovation.Name
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockRepository) HasVotedInScope(ctx context.Context, eventID, scopeKey string, voterKey []byte) (bool, error) {
+	return m.findVote(eventID, scopeKey, voterKey) != nil, nil
+}
+
+func (m *mockRepository) GetVotedInnovation(ctx context.Context, eventID, scopeKey string, voterKey []byte) (*Innovation, error) {
+	vote := m.findVote(eventID, scopeKey, voterKey)
+	if vote == nil {
+		return nil, ErrInnovationNotFound
+	}
+	for _, innovation := range m.innovations {
+		if innovation.ID == vote.InnovationID {
+			return innovation, nil
+		}
+	}
+	return nil, ErrInnovationNotFound
+}
+
+// Mock vote publisher
+type mockVotePublisher struct {
+	updates []VoteUpdate
+}
+
+func (m *mockVotePublisher) PublishVote(update VoteUpdate) {
+	m.updates = append(m.updates, update)
 }
 
 // Mock IP hasher
@@ -51,25 +335,29 @@ func (m *mockIPHasher) HashIP(ip string) []byte {
 	return []byte(ip) // Simple mock - just use IP as hash
 }
 
+func (m *mockIPHasher) HashSubnet(ip string) []byte {
+	return []byte("subnet:" + ip)
+}
+
 func TestVoteService_SubmitVote(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	repo := newMockRepository()
 	hasher := &mockIPHasher{}
-	service := NewVoteService(repo, hasher, logger)
+	service := NewVoteService(repo, hasher, &mockAuditRecorder{}, &mockVotePublisher{}, VoterDedupe{}, logger)
 
 	// Add test innovation
-	innovation := &Innovation{
+	repo.addInnovation(&Innovation{
 		ID:        "test-id-1",
 		GroupSlug: "test-group",
 		Slug:      "test-innovation",
 		Name:      "Test Innovation",
-	}
-	repo.innovations["test-group:test-innovation"] = innovation
+	})
 
 	ctx := context.Background()
 
 	t.Run("first vote succeeds", func(t *testing.T) {
 		req := VoteRequest{
+			EventID:   testEventID,
 			GroupSlug: "test-group",
 			Slug:      "test-innovation",
 			ClientIP:  "192.168.1.1",
@@ -96,6 +384,7 @@ func TestVoteService_SubmitVote(t *testing.T) {
 
 	t.Run("duplicate vote from same IP", func(t *testing.T) {
 		req := VoteRequest{
+			EventID:   testEventID,
 			GroupSlug: "test-group",
 			Slug:      "test-innovation",
 			ClientIP:  "192.168.1.1",
@@ -122,6 +411,7 @@ func TestVoteService_SubmitVote(t *testing.T) {
 
 	t.Run("vote from different IP succeeds", func(t *testing.T) {
 		req := VoteRequest{
+			EventID:   testEventID,
 			GroupSlug: "test-group",
 			Slug:      "test-innovation",
 			ClientIP:  "192.168.1.2",
@@ -144,6 +434,7 @@ func TestVoteService_SubmitVote(t *testing.T) {
 
 	t.Run("innovation not found", func(t *testing.T) {
 		req := VoteRequest{
+			EventID:   testEventID,
 			GroupSlug: "nonexistent",
 			Slug:      "nonexistent",
 			ClientIP:  "192.168.1.1",
@@ -157,5 +448,292 @@ func TestVoteService_SubmitVote(t *testing.T) {
 	})
 }
 
+func TestVoteService_VotingWindow(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	repo := newMockRepository()
+	service := NewVoteService(repo, &mockIPHasher{}, &mockAuditRecorder{}, &mockVotePublisher{}, VoterDedupe{}, logger)
+
+	now := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	service.(*voteService).now = func() time.Time { return now }
 
+	repo.addInnovation(&Innovation{
+		ID:        "test-id-1",
+		GroupSlug: "test-group",
+		Slug:      "test-innovation",
+		Name:      "Test Innovation",
+	})
+
+	hourBefore := now.Add(-time.Hour)
+	hourAfter := now.Add(time.Hour)
 
+	tests := []struct {
+		name   string
+		window VotingWindow
+		want   VotingStatus
+	}{
+		{
+			name:   "unbounded window is open",
+			window: VotingWindow{},
+			want:   VotingStatusOpen,
+		},
+		{
+			name:   "inside window is open",
+			window: VotingWindow{OpensAt: &hourBefore, ClosesAt: &hourAfter},
+			want:   VotingStatusOpen,
+		},
+		{
+			name:   "paused window is closed",
+			window: VotingWindow{OpensAt: &hourBefore, ClosesAt: &hourAfter, Paused: true},
+			want:   VotingStatusPaused,
+		},
+		{
+			name:   "before opening is closed",
+			window: VotingWindow{OpensAt: &hourAfter},
+			want:   VotingStatusNotStarted,
+		},
+		{
+			name:   "after closing is closed",
+			window: VotingWindow{ClosesAt: &hourBefore},
+			want:   VotingStatusEnded,
+		},
+	}
+
+	ctx := context.Background()
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo.events[testEventID].Window = tt.window
+
+			if got := tt.window.Status(now); got != tt.want {
+				t.Errorf("Status() = %v, want %v", got, tt.want)
+			}
+
+			req := VoteRequest{
+				EventID:   testEventID,
+				GroupSlug: "test-group",
+				Slug:      "test-innovation",
+				ClientIP:  fmt.Sprintf("10.0.0.%d", i+1),
+			}
+
+			_, err := service.SubmitVote(ctx, req)
+			if tt.want == VotingStatusOpen && err != nil {
+				t.Errorf("SubmitVote() error = %v, want nil", err)
+			}
+			if tt.want != VotingStatusOpen && !errors.Is(err, ErrVotingClosed) {
+				t.Errorf("SubmitVote() error = %v, want ErrVotingClosed", err)
+			}
+		})
+	}
+
+	t.Run("rejects inverted window", func(t *testing.T) {
+		err := service.UpdateVotingWindow(ctx, testEventID, &VotingWindow{OpensAt: &hourAfter, ClosesAt: &hourBefore})
+		if !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("UpdateVotingWindow() error = %v, want ErrInvalidInput", err)
+		}
+	})
+}
+
+func TestVoteService_UpdateVotingWindow_Audited(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	repo := newMockRepository()
+	audit := &mockAuditRecorder{}
+	service := NewVoteService(repo, &mockIPHasher{}, audit, &mockVotePublisher{}, VoterDedupe{}, logger)
+	ctx := context.Background()
+
+	if err := service.UpdateVotingWindow(ctx, testEventID, &VotingWindow{Paused: true}); err != nil {
+		t.Fatalf("UpdateVotingWindow() error = %v", err)
+	}
+
+	if len(audit.entries) != 1 || audit.entries[0].action != AuditVotingWindowUpdated {
+		t.Fatalf("audit actions = %v, want [%s]", audit.actions(), AuditVotingWindowUpdated)
+	}
+	entry := audit.entries[0]
+	if before, ok := entry.before.(VotingWindow); !ok || before.Paused {
+		t.Errorf("audit before = %+v, want the open window", entry.before)
+	}
+	if after, ok := entry.after.(*VotingWindow); !ok || !after.Paused {
+		t.Errorf("audit after = %+v, want the paused window", entry.after)
+	}
+
+	// Rejected changes are not audited
+	hourAgo := time.Now().Add(-time.Hour)
+	_ = service.UpdateVotingWindow(ctx, testEventID, &VotingWindow{OpensAt: &hourAgo, ClosesAt: &hourAgo})
+	if len(audit.entries) != 1 {
+		t.Errorf("audit entries = %d after rejected update, want 1", len(audit.entries))
+	}
+}
+
+func TestVoteService_SubmitVote_PerEvent(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	repo := newMockRepository()
+	service := NewVoteService(repo, &mockIPHasher{}, &mockAuditRecorder{}, &mockVotePublisher{}, VoterDedupe{}, logger)
+
+	repo.events["next-event-id"] = &Event{ID: "next-event-id", Slug: "next-event", Name: "Next Event"}
+	repo.addInnovation(&Innovation{ID: "test-id-1", GroupSlug: "test-group", Slug: "test-innovation", Name: "Test Innovation"})
+	repo.addInnovation(&Innovation{
+		ID:        "test-id-2",
+		EventID:   "next-event-id",
+		GroupSlug: "test-group",
+		Slug:      "test-innovation",
+		Name:      "Test Innovation",
+	})
+
+	ctx := context.Background()
+
+	for _, eventID := range []string{testEventID, "next-event-id"} {
+		result, err := service.SubmitVote(ctx, VoteRequest{
+			EventID:   eventID,
+			GroupSlug: "test-group",
+			Slug:      "test-innovation",
+			ClientIP:  "192.168.1.1",
+		})
+		if err != nil {
+			t.Fatalf("SubmitVote(%s) error = %v", eventID, err)
+		}
+		if !result.Success {
+			t.Errorf("SubmitVote(%s) expected success, same IP may vote once per event", eventID)
+		}
+	}
+
+	if _, err := service.GetEvent(ctx, "missing"); !errors.Is(err, ErrEventNotFound) {
+		t.Errorf("GetEvent() error = %v, want ErrEventNotFound", err)
+	}
+
+	event, err := service.GetEvent(ctx, "")
+	if err != nil || event.ID != testEventID {
+		t.Errorf("GetEvent(\"\") = %v, %v, want default event", event, err)
+	}
+}
+
+func TestVoteService_SubmitVote_Policies(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	ctx := context.Background()
+
+	ballot := []struct {
+		group string
+		slug  string
+	}{
+		{"group-a", "innovation-1"},
+		{"group-a", "innovation-2"},
+		{"group-b", "innovation-3"},
+		{"group-a", "innovation-1"},
+	}
+
+	tests := []struct {
+		policy  string
+		success []bool
+	}{
+		{VotePolicyGlobal, []bool{true, false, false, false}},
+		{VotePolicyPerGroup, []bool{true, false, true, false}},
+		{VotePolicyPerInnovation, []bool{true, true, true, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			repo := newMockRepository()
+			repo.events[testEventID].VotePolicy = tt.policy
+			service := NewVoteService(repo, &mockIPHasher{}, &mockAuditRecorder{}, &mockVotePublisher{}, VoterDedupe{}, logger)
+
+			for _, b := range ballot {
+				repo.addInnovation(&Innovation{
+					ID:        "id-" + b.slug,
+					GroupSlug: b.group,
+					Slug:      b.slug,
+					Name:      b.slug,
+				})
+			}
+
+			for i, b := range ballot {
+				result, err := service.SubmitVote(ctx, VoteRequest{
+					EventID:   testEventID,
+					GroupSlug: b.group,
+					Slug:      b.slug,
+					ClientIP:  "192.168.1.1",
+				})
+				if err != nil {
+					t.Fatalf("vote %d: SubmitVote() error = %v", i, err)
+				}
+				if result.Success != tt.success[i] {
+					t.Errorf("vote %d (%s/%s): Success = %v, want %v", i, b.group, b.slug, result.Success, tt.success[i])
+				}
+				if !result.Success && !strings.Contains(result.Message, "innovation-") {
+					t.Errorf("vote %d: message %q should name the innovation voted for", i, result.Message)
+				}
+				if !result.Success && strings.Contains(result.Message, "IP") {
+					t.Errorf("vote %d: message %q should speak of the voter, not the IP", i, result.Message)
+				}
+			}
+		})
+	}
+
+	t.Run("unknown policy", func(t *testing.T) {
+		if _, err := NewVotePolicy("per-planet"); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("NewVotePolicy() error = %v, want ErrInvalidInput", err)
+		}
+	})
+}
+
+func TestVoteService_GetResults(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	repo := newMockRepository()
+	service := NewVoteService(repo, &mockIPHasher{}, &mockAuditRecorder{}, &mockVotePublisher{}, VoterDedupe{}, logger)
+	ctx := context.Background()
+
+	archivedAt := time.Now()
+	repo.addInnovation(&Innovation{ID: "a", GroupSlug: "g1", Slug: "a", Name: "A"})
+	repo.addInnovation(&Innovation{ID: "b", GroupSlug: "g1", Slug: "b", Name: "B"})
+	repo.addInnovation(&Innovation{ID: "c", GroupSlug: "g2", Slug: "c", Name: "C"})
+	repo.addInnovation(&Innovation{ID: "d", GroupSlug: "g2", Slug: "d", Name: "D", ArchivedAt: &archivedAt})
+
+	votes := map[string][]string{
+		"a": {"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4"},
+		"b": {"10.0.0.1"},
+		"c": {"10.0.0.2", "10.0.0.5"},
+	}
+	for id, ips := range votes {
+		for _, ip := range ips {
+			repo.votes = append(repo.votes, &Vote{EventID: testEventID, InnovationID: id, VoterIPHash: []byte(ip)})
+		}
+	}
+
+	results, err := service.GetResults(ctx, testEventID, "")
+	if err != nil {
+		t.Fatalf("GetResults() error = %v", err)
+	}
+
+	if results.TotalInnovations != 3 {
+		t.Errorf("TotalInnovations = %d, want 3 (archived excluded)", results.TotalInnovations)
+	}
+	if results.TotalVotes != 7 {
+		t.Errorf("TotalVotes = %d, want 7", results.TotalVotes)
+	}
+	if results.TotalVoters != 5 {
+		t.Errorf("TotalVoters = %d, want 5", results.TotalVoters)
+	}
+	if results.MaxVotes != 4 {
+		t.Errorf("MaxVotes = %d, want 4", results.MaxVotes)
+	}
+
+	want := map[string]float64{"a": 100, "b": 25, "c": 50}
+	for _, result := range results.Innovations {
+		if result.VotePercentage != want[result.ID] {
+			t.Errorf("VotePercentage[%s] = %.1f, want %.1f", result.ID, result.VotePercentage, want[result.ID])
+		}
+	}
+}
+
+func TestVoteService_GetResults_NoVotes(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	repo := newMockRepository()
+	service := NewVoteService(repo, &mockIPHasher{}, &mockAuditRecorder{}, &mockVotePublisher{}, VoterDedupe{}, logger)
+
+	repo.addInnovation(&Innovation{ID: "a", GroupSlug: "g1", Slug: "a", Name: "A"})
+
+	results, err := service.GetResults(context.Background(), testEventID, "")
+	if err != nil {
+		t.Fatalf("GetResults() error = %v", err)
+	}
+	if results.MaxVotes != 0 || results.Innovations[0].VotePercentage != 0 {
+		t.Errorf("results = max %d, percentage %.1f; want 0 and 0", results.MaxVotes, results.Innovations[0].VotePercentage)
+	}
+}
@@ -0,0 +1,252 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// maxSeriesBuckets bounds the size of a time-series response
+	maxSeriesBuckets = 1000
+	// autoSeriesBuckets is the number of buckets aimed for when no size is given
+	autoSeriesBuckets = 120
+	// defaultSeriesRange is used when neither the query nor the voting window
+	// says where the series starts
+	defaultSeriesRange = 24 * time.Hour
+)
+
+// seriesBucketSizes are the sizes picked from when no bucket size is given
+var seriesBucketSizes = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	15 * time.Minute,
+	30 * time.Minute,
+	time.Hour,
+	3 * time.Hour,
+	6 * time.Hour,
+	12 * time.Hour,
+	24 * time.Hour,
+}
+
+// VoteSeriesQuery selects the votes of a time series. Zero fields use the
+// defaults: the range follows the voting window (or the last 24 hours) and
+// the bucket size is chosen from the range.
+type VoteSeriesQuery struct {
+	GroupSlug    string
+	InnovationID string
+	Since        time.Time
+	Until        time.Time
+	Bucket       time.Duration
+}
+
+// VoteBucket is the number of votes an innovation received in one bucket.
+// A zero Start holds the votes cast before the series begins.
+type VoteBucket struct {
+	InnovationID string
+	Start        time.Time
+	Count        int64
+}
+
+// SeriesValues holds one count per bucket and the running total at the end
+// of each bucket, including votes cast before the series begins
+type SeriesValues struct {
+	Counts     []int64 `json:"counts"`
+	Cumulative []int64 `json:"cumulative"`
+}
+
+// InnovationSeries is the time series of one innovation
+type InnovationSeries struct {
+	InnovationID string `json:"innovation_id"`
+	GroupSlug    string `json:"group_slug"`
+	Slug         string `json:"slug"`
+	Name         string `json:"name"`
+	SeriesValues
+}
+
+// VoteSeries holds bucketed vote counts. Buckets are the start times; every
+// values slice is aligned with them.
+type VoteSeries struct {
+	Since         time.Time           `json:"since"`
+	Until         time.Time           `json:"until"`
+	BucketSeconds int64               `json:"bucket_seconds"`
+	Buckets       []time.Time         `json:"buckets"`
+	Total         SeriesValues        `json:"total"`
+	Innovations   []*InnovationSeries `json:"innovations"`
+}
+
+func (s *voteService) GetVoteSeries(ctx context.Context, event *Event, query VoteSeriesQuery) (*VoteSeries, error) {
+	since, until, bucket, err := s.seriesRange(event, query)
+	if err != nil {
+		return nil, err
+	}
+
+	innovations, err := s.seriesInnovations(ctx, event.ID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets, err := s.repo.GetVoteBuckets(ctx, event.ID, since, until, bucket)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to load vote buckets",
+			"event_id", event.ID,
+			"error", err)
+		return nil, err
+	}
+
+	count := int(until.Sub(since) / bucket)
+	series := &VoteSeries{
+		Since:         since,
+		Until:         until,
+		BucketSeconds: int64(bucket / time.Second),
+		Buckets:       make([]time.Time, count),
+		Total:         newSeriesValues(count),
+	}
+	for i := range series.Buckets {
+		series.Buckets[i] = since.Add(time.Duration(i) * bucket)
+	}
+
+	byID := make(map[string]*InnovationSeries, len(innovations))
+	for _, innovation := range innovations {
+		entry := &InnovationSeries{
+			InnovationID: innovation.ID,
+			GroupSlug:    innovation.GroupSlug,
+			Slug:         innovation.Slug,
+			Name:         innovation.Name,
+			SeriesValues: newSeriesValues(count),
+		}
+		byID[innovation.ID] = entry
+		series.Innovations = append(series.Innovations, entry)
+	}
+
+	// Votes before the range seed the running totals
+	base := make(map[string]int64)
+	for _, b := range buckets {
+		entry, ok := byID[b.InnovationID]
+		if !ok {
+			continue
+		}
+		if b.Start.IsZero() {
+			base[b.InnovationID] += b.Count
+			continue
+		}
+		i := int(b.Start.Sub(since) / bucket)
+		if i < 0 || i >= count {
+			continue
+		}
+		entry.Counts[i] += b.Count
+		series.Total.Counts[i] += b.Count
+	}
+
+	var totalBase int64
+	for _, entry := range series.Innovations {
+		entry.accumulate(base[entry.InnovationID])
+		totalBase += base[entry.InnovationID]
+	}
+	series.Total.accumulate(totalBase)
+
+	return series, nil
+}
+
+// seriesRange resolves the defaults of a query and validates the result
+func (s *voteService) seriesRange(event *Event, query VoteSeriesQuery) (since, until time.Time, bucket time.Duration, err error) {
+	until = query.Until
+	if until.IsZero() {
+		until = s.now()
+		if closes := event.Window.ClosesAt; closes != nil && closes.Before(until) {
+			until = *closes
+		}
+	}
+
+	since = query.Since
+	if since.IsZero() {
+		since = until.Add(-defaultSeriesRange)
+		if opens := event.Window.OpensAt; opens != nil && opens.Before(until) {
+			since = *opens
+		}
+	}
+
+	if !since.Before(until) {
+		return since, until, 0, fmt.Errorf("%w: since must be before until", ErrInvalidInput)
+	}
+
+	bucket = query.Bucket
+	if bucket == 0 {
+		bucket = autoBucketSize(until.Sub(since))
+	}
+	if bucket < time.Minute || bucket%time.Minute != 0 {
+		return since, until, 0, fmt.Errorf("%w: bucket must be a whole number of minutes", ErrInvalidInput)
+	}
+
+	// Align the range to bucket boundaries so buckets start on round times
+	since = since.Truncate(bucket)
+	if aligned := until.Truncate(bucket); aligned.Before(until) {
+		until = aligned.Add(bucket)
+	}
+
+	if until.Sub(since)/bucket > maxSeriesBuckets {
+		return since, until, 0, fmt.Errorf("%w: range holds more than %d buckets, use a larger bucket", ErrInvalidInput, maxSeriesBuckets)
+	}
+	return since, until, bucket, nil
+}
+
+// seriesInnovations returns the active innovations matching the query filters
+func (s *voteService) seriesInnovations(ctx context.Context, eventID string, query VoteSeriesQuery) ([]*Innovation, error) {
+	if query.GroupSlug != "" {
+		groups, err := s.repo.ListGroups(ctx, eventID)
+		if err != nil {
+			return nil, err
+		}
+		if len(filterGroups(groups, query.GroupSlug)) == 0 {
+			return nil, ErrGroupNotFound
+		}
+	}
+
+	innovations, err := s.repo.ListInnovations(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := innovations[:0]
+	for _, innovation := range innovations {
+		if query.GroupSlug != "" && innovation.GroupSlug != query.GroupSlug {
+			continue
+		}
+		if query.InnovationID != "" && innovation.ID != query.InnovationID {
+			continue
+		}
+		filtered = append(filtered, innovation)
+	}
+
+	if query.InnovationID != "" && len(filtered) == 0 {
+		return nil, ErrInnovationNotFound
+	}
+	return filtered, nil
+}
+
+// autoBucketSize returns the smallest bucket size that keeps the range near
+// autoSeriesBuckets buckets
+func autoBucketSize(span time.Duration) time.Duration {
+	for _, size := range seriesBucketSizes {
+		if span/size <= autoSeriesBuckets {
+			return size
+		}
+	}
+	return seriesBucketSizes[len(seriesBucketSizes)-1]
+}
+
+func newSeriesValues(count int) SeriesValues {
+	return SeriesValues{
+		Counts:     make([]int64, count),
+		Cumulative: make([]int64, count),
+	}
+}
+
+// accumulate fills Cumulative from Counts, starting at base
+func (v *SeriesValues) accumulate(base int64) {
+	running := base
+	for i, count := range v.Counts {
+		running += count
+		v.Cumulative[i] = running
+	}
+}
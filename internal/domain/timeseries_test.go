@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestVoteService_GetVoteSeries(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	repo := newMockRepository()
+	service := NewVoteService(repo, &mockIPHasher{}, &mockAuditRecorder{}, &mockVotePublisher{}, VoterDedupe{}, logger)
+	ctx := context.Background()
+
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	event := &Event{ID: testEventID}
+
+	repo.addInnovation(&Innovation{ID: "a", GroupSlug: "g1", Slug: "a", Name: "A"})
+	repo.addInnovation(&Innovation{ID: "b", GroupSlug: "g2", Slug: "b", Name: "B"})
+
+	vote := func(id string, offset time.Duration) {
+		repo.votes = append(repo.votes, &Vote{EventID: testEventID, InnovationID: id, CreatedAt: start.Add(offset)})
+	}
+	vote("a", -time.Hour) // before the range
+	vote("a", 5*time.Minute)
+	vote("a", 10*time.Minute)
+	vote("b", 70*time.Minute)
+	vote("a", 3*time.Hour) // after the range
+
+	query := VoteSeriesQuery{Since: start, Until: start.Add(2 * time.Hour), Bucket: time.Hour}
+	series, err := service.GetVoteSeries(ctx, event, query)
+	if err != nil {
+		t.Fatalf("GetVoteSeries() error = %v", err)
+	}
+
+	if len(series.Buckets) != 2 || !series.Buckets[1].Equal(start.Add(time.Hour)) {
+		t.Fatalf("Buckets = %v, want 2 hourly buckets from %v", series.Buckets, start)
+	}
+	if series.BucketSeconds != 3600 {
+		t.Errorf("BucketSeconds = %d, want 3600", series.BucketSeconds)
+	}
+
+	want := map[string]SeriesValues{
+		"a": {Counts: []int64{2, 0}, Cumulative: []int64{3, 3}},
+		"b": {Counts: []int64{0, 1}, Cumulative: []int64{0, 1}},
+	}
+	for _, entry := range series.Innovations {
+		assertSeriesValues(t, entry.InnovationID, entry.SeriesValues, want[entry.InnovationID])
+	}
+	assertSeriesValues(t, "total", series.Total, SeriesValues{Counts: []int64{2, 1}, Cumulative: []int64{3, 4}})
+
+	t.Run("group filter", func(t *testing.T) {
+		series, err := service.GetVoteSeries(ctx, event, VoteSeriesQuery{GroupSlug: "g2", Since: query.Since, Until: query.Until, Bucket: query.Bucket})
+		if err != nil {
+			t.Fatalf("GetVoteSeries() error = %v", err)
+		}
+		if len(series.Innovations) != 1 || series.Innovations[0].InnovationID != "b" {
+			t.Fatalf("Innovations = %d, want only b", len(series.Innovations))
+		}
+		assertSeriesValues(t, "total", series.Total, SeriesValues{Counts: []int64{0, 1}, Cumulative: []int64{0, 1}})
+	})
+
+	t.Run("unknown filters", func(t *testing.T) {
+		if _, err := service.GetVoteSeries(ctx, event, VoteSeriesQuery{GroupSlug: "missing"}); !errors.Is(err, ErrGroupNotFound) {
+			t.Errorf("group error = %v, want ErrGroupNotFound", err)
+		}
+		if _, err := service.GetVoteSeries(ctx, event, VoteSeriesQuery{InnovationID: "missing"}); !errors.Is(err, ErrInnovationNotFound) {
+			t.Errorf("innovation error = %v, want ErrInnovationNotFound", err)
+		}
+	})
+}
+
+func TestVoteService_GetVoteSeries_Range(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	service := NewVoteService(newMockRepository(), &mockIPHasher{}, &mockAuditRecorder{}, &mockVotePublisher{}, VoterDedupe{}, logger)
+	ctx := context.Background()
+
+	now := time.Date(2026, 5, 1, 12, 7, 30, 0, time.UTC)
+	service.(*voteService).now = func() time.Time { return now }
+
+	opens := now.Add(-3 * time.Hour)
+	event := &Event{ID: testEventID, Window: VotingWindow{OpensAt: &opens}}
+
+	tests := []struct {
+		name       string
+		query      VoteSeriesQuery
+		wantSince  time.Time
+		wantBucket time.Duration
+		wantErr    bool
+	}{
+		{
+			name:       "defaults to the voting window",
+			wantSince:  time.Date(2026, 5, 1, 9, 5, 0, 0, time.UTC),
+			wantBucket: 5 * time.Minute,
+		},
+		{
+			name:       "explicit bucket aligns the range",
+			query:      VoteSeriesQuery{Since: now.Add(-time.Hour), Bucket: 15 * time.Minute},
+			wantSince:  time.Date(2026, 5, 1, 11, 0, 0, 0, time.UTC),
+			wantBucket: 15 * time.Minute,
+		},
+		{
+			name:    "sub-minute bucket",
+			query:   VoteSeriesQuery{Bucket: 30 * time.Second},
+			wantErr: true,
+		},
+		{
+			name:    "since after until",
+			query:   VoteSeriesQuery{Since: now, Until: now.Add(-time.Hour)},
+			wantErr: true,
+		},
+		{
+			name:    "too many buckets",
+			query:   VoteSeriesQuery{Since: now.Add(-30 * 24 * time.Hour), Bucket: time.Minute},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series, err := service.GetVoteSeries(ctx, event, tt.query)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidInput) {
+					t.Errorf("GetVoteSeries() error = %v, want ErrInvalidInput", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetVoteSeries() error = %v", err)
+			}
+			if !series.Since.Equal(tt.wantSince) {
+				t.Errorf("Since = %v, want %v", series.Since, tt.wantSince)
+			}
+			if series.BucketSeconds != int64(tt.wantBucket/time.Second) {
+				t.Errorf("BucketSeconds = %d, want %d", series.BucketSeconds, int64(tt.wantBucket/time.Second))
+			}
+			if series.Until.Before(now) {
+				t.Errorf("Until = %v, want at or after %v", series.Until, now)
+			}
+		})
+	}
+}
+
+func assertSeriesValues(t *testing.T, name string, got, want SeriesValues) {
+	t.Helper()
+	for i := range want.Counts {
+		if got.Counts[i] != want.Counts[i] || got.Cumulative[i] != want.Cumulative[i] {
+			t.Errorf("%s = counts %v, cumulative %v; want %v, %v", name, got.Counts, got.Cumulative, want.Counts, want.Cumulative)
+			return
+		}
+	}
+}
@@ -0,0 +1,443 @@
+package domain
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// Voter verification modes as stored in events.voter_verification
+const (
+	// VoterVerificationIP identifies voters by their hashed IP
+	VoterVerificationIP = "ip"
+	// VoterVerificationOTP identifies voters by a contact confirmed with a one-time code
+	VoterVerificationOTP = "otp"
+)
+
+// Contact channels a one-time code can be sent over
+const (
+	ContactEmail = "email"
+	ContactPhone = "phone"
+)
+
+// verificationCodeDigits is the length of a one-time code
+const verificationCodeDigits = 6
+
+// VerificationSettings bounds how codes are issued and checked
+type VerificationSettings struct {
+	// Channels lists the contact channels voters may use
+	Channels []string
+	// CodeTTL is how long a code can be entered
+	CodeTTL time.Duration
+	// MaxAttempts is how many wrong guesses a code survives
+	MaxAttempts int
+	// ResendInterval is the minimum time between codes for one contact
+	ResendInterval time.Duration
+	// MaxCodesPerHour caps the codes sent to one contact per hour
+	MaxCodesPerHour int
+	// SessionTTL is how long a verified contact may vote without a new code
+	SessionTTL time.Duration
+}
+
+// DefaultVerificationSettings are used for settings left at zero
+var DefaultVerificationSettings = VerificationSettings{
+	Channels:        []string{ContactEmail},
+	CodeTTL:         10 * time.Minute,
+	MaxAttempts:     5,
+	ResendInterval:  time.Minute,
+	MaxCodesPerHour: 5,
+	SessionTTL:      24 * time.Hour,
+}
+
+// VerificationCode is a one-time code sent to a voter's contact
+type VerificationCode struct {
+	ID          string
+	EventID     string
+	ContactHash []byte
+	Channel     string
+	CodeHash    []byte
+	Attempts    int
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+	UsedAt      *time.Time
+}
+
+// VoterSession is a contact verified for an event
+type VoterSession struct {
+	TokenHash   []byte
+	EventID     string
+	ContactHash []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+// CodeRequest describes a code that was sent
+type CodeRequest struct {
+	Channel string `json:"channel"`
+	// Destination is the contact with most characters masked
+	Destination string    `json:"destination"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	ResendAt    time.Time `json:"resend_at"`
+}
+
+// VerificationMessage is handed to a CodeSender
+type VerificationMessage struct {
+	Channel   string
+	To        string
+	Code      string
+	EventName string
+	ExpiresAt time.Time
+}
+
+// CodeSender delivers one-time codes
+type CodeSender interface {
+	SendCode(ctx context.Context, msg VerificationMessage) error
+}
+
+// ContactHasher hashes voter contacts and codes with a server secret
+type ContactHasher interface {
+	HashValue(value string) []byte
+}
+
+// VerificationRepository stores one-time codes and verified voter sessions
+type VerificationRepository interface {
+	CreateVerificationCode(ctx context.Context, code *VerificationCode) error
+	// GetLatestVerificationCode returns the newest code for the contact, or
+	// ErrInvalidCode when there is none
+	GetLatestVerificationCode(ctx context.Context, eventID string, contactHash []byte) (*VerificationCode, error)
+	CountVerificationCodes(ctx context.Context, eventID string, contactHash []byte, since time.Time) (int, error)
+	// RecordVerificationAttempt counts a wrong guess and returns the new total
+	RecordVerificationAttempt(ctx context.Context, id string) (int, error)
+	// UseVerificationCode marks an unused code used; false if it already was
+	UseVerificationCode(ctx context.Context, id string, at time.Time) (bool, error)
+	CreateVoterSession(ctx context.Context, session *VoterSession) error
+	// GetVoterSession returns ErrVerificationRequired for an unknown token
+	GetVoterSession(ctx context.Context, tokenHash []byte) (*VoterSession, error)
+}
+
+// VerificationService confirms voter contacts with one-time codes
+type VerificationService interface {
+	// RequestCode sends a new code to the contact
+	RequestCode(ctx context.Context, event *Event, contact string) (*CodeRequest, error)
+	// ConfirmCode checks a code and returns the session and its signed cookie value
+	ConfirmCode(ctx context.Context, event *Event, contact, code string) (*VoterSession, string, error)
+	// Voter resolves a signed cookie value to a verified contact of the event
+	Voter(ctx context.Context, event *Event, signedToken string) (*VoterSession, error)
+}
+
+type verificationService struct {
+	repo     VerificationRepository
+	sender   CodeSender
+	hasher   ContactHasher
+	signer   TokenSigner
+	settings VerificationSettings
+	logger   *slog.Logger
+	now      func() time.Time
+}
+
+// NewVerificationService creates a new VerificationService
+func NewVerificationService(repo VerificationRepository, sender CodeSender, hasher ContactHasher, signer TokenSigner, settings VerificationSettings, logger *slog.Logger) VerificationService {
+	return &verificationService{
+		repo:     repo,
+		sender:   sender,
+		hasher:   hasher,
+		signer:   signer,
+		settings: settings.withDefaults(),
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+func (s VerificationSettings) withDefaults() VerificationSettings {
+	d := DefaultVerificationSettings
+	if len(s.Channels) == 0 {
+		s.Channels = d.Channels
+	}
+	if s.CodeTTL <= 0 {
+		s.CodeTTL = d.CodeTTL
+	}
+	if s.MaxAttempts <= 0 {
+		s.MaxAttempts = d.MaxAttempts
+	}
+	if s.ResendInterval <= 0 {
+		s.ResendInterval = d.ResendInterval
+	}
+	if s.MaxCodesPerHour <= 0 {
+		s.MaxCodesPerHour = d.MaxCodesPerHour
+	}
+	if s.SessionTTL <= 0 {
+		s.SessionTTL = d.SessionTTL
+	}
+	return s
+}
+
+func (s *verificationService) RequestCode(ctx context.Context, event *Event, contact string) (*CodeRequest, error) {
+	if event.VoterVerification != VoterVerificationOTP {
+		return nil, fmt.Errorf("%w: event does not verify voters", ErrInvalidInput)
+	}
+
+	channel, contact, err := NormalizeContact(contact)
+	if err != nil {
+		return nil, err
+	}
+	if !s.allowsChannel(channel) {
+		return nil, fmt.Errorf("%w: %s verification is not available", ErrInvalidInput, channel)
+	}
+
+	now := s.now()
+	contactHash := s.hasher.HashValue(contact)
+
+	latest, err := s.repo.GetLatestVerificationCode(ctx, event.ID, contactHash)
+	if err != nil && !errors.Is(err, ErrInvalidCode) {
+		s.logger.ErrorContext(ctx, "failed to load verification code",
+			"event_id", event.ID,
+			"error", err)
+		return nil, err
+	}
+	if latest != nil && now.Sub(latest.CreatedAt) < s.settings.ResendInterval {
+		return nil, ErrCodeRequestLimited
+	}
+
+	sent, err := s.repo.CountVerificationCodes(ctx, event.ID, contactHash, now.Add(-time.Hour))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to count verification codes",
+			"event_id", event.ID,
+			"error", err)
+		return nil, err
+	}
+	if sent >= s.settings.MaxCodesPerHour {
+		return nil, ErrCodeRequestLimited
+	}
+
+	code, err := newVerificationCode()
+	if err != nil {
+		return nil, err
+	}
+
+	record := &VerificationCode{
+		EventID:     event.ID,
+		ContactHash: contactHash,
+		Channel:     channel,
+		CodeHash:    s.hashCode(contactHash, code),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.settings.CodeTTL),
+	}
+	if err := s.repo.CreateVerificationCode(ctx, record); err != nil {
+		s.logger.ErrorContext(ctx, "failed to store verification code",
+			"event_id", event.ID,
+			"error", err)
+		return nil, err
+	}
+
+	err = s.sender.SendCode(ctx, VerificationMessage{
+		Channel:   channel,
+		To:        contact,
+		Code:      code,
+		EventName: event.Name,
+		ExpiresAt: record.ExpiresAt,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to send verification code",
+			"event_id", event.ID,
+			"channel", channel,
+			"error", err)
+		return nil, fmt.Errorf("send verification code: %w", err)
+	}
+
+	s.logger.InfoContext(ctx, "verification code sent",
+		"event_id", event.ID,
+		"channel", channel)
+
+	return &CodeRequest{
+		Channel:     channel,
+		Destination: MaskContact(channel, contact),
+		ExpiresAt:   record.ExpiresAt,
+		ResendAt:    now.Add(s.settings.ResendInterval),
+	}, nil
+}
+
+func (s *verificationService) ConfirmCode(ctx context.Context, event *Event, contact, code string) (*VoterSession, string, error) {
+	_, contact, err := NormalizeContact(contact)
+	if err != nil {
+		return nil, "", err
+	}
+	code = strings.TrimSpace(code)
+
+	now := s.now()
+	contactHash := s.hasher.HashValue(contact)
+
+	record, err := s.repo.GetLatestVerificationCode(ctx, event.ID, contactHash)
+	if err != nil {
+		if !errors.Is(err, ErrInvalidCode) {
+			s.logger.ErrorContext(ctx, "failed to load verification code",
+				"event_id", event.ID,
+				"error", err)
+		}
+		return nil, "", err
+	}
+	if record.UsedAt != nil || !now.Before(record.ExpiresAt) {
+		return nil, "", ErrInvalidCode
+	}
+	if record.Attempts >= s.settings.MaxAttempts {
+		return nil, "", ErrTooManyAttempts
+	}
+
+	if !hmac.Equal(record.CodeHash, s.hashCode(contactHash, code)) {
+		attempts, err := s.repo.RecordVerificationAttempt(ctx, record.ID)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "failed to record verification attempt",
+				"event_id", event.ID,
+				"error", err)
+			return nil, "", err
+		}
+		s.logger.WarnContext(ctx, "wrong verification code",
+			"event_id", event.ID,
+			"attempts", attempts)
+		if attempts >= s.settings.MaxAttempts {
+			return nil, "", ErrTooManyAttempts
+		}
+		return nil, "", ErrInvalidCode
+	}
+
+	used, err := s.repo.UseVerificationCode(ctx, record.ID, now)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to mark verification code used",
+			"event_id", event.ID,
+			"error", err)
+		return nil, "", err
+	}
+	if !used {
+		// A concurrent request confirmed the same code
+		return nil, "", ErrInvalidCode
+	}
+
+	token, err := newSessionToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	session := &VoterSession{
+		TokenHash:   hashSessionToken(token),
+		EventID:     event.ID,
+		ContactHash: contactHash,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.settings.SessionTTL),
+	}
+	if err := s.repo.CreateVoterSession(ctx, session); err != nil {
+		s.logger.ErrorContext(ctx, "failed to create voter session",
+			"event_id", event.ID,
+			"error", err)
+		return nil, "", err
+	}
+
+	s.logger.InfoContext(ctx, "voter verified",
+		"event_id", event.ID,
+		"channel", record.Channel)
+	return session, s.signer.Sign(token), nil
+}
+
+func (s *verificationService) Voter(ctx context.Context, event *Event, signedToken string) (*VoterSession, error) {
+	token, ok := s.signer.Verify(signedToken)
+	if !ok {
+		return nil, ErrVerificationRequired
+	}
+
+	session, err := s.repo.GetVoterSession(ctx, hashSessionToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if session.EventID != event.ID || !s.now().Before(session.ExpiresAt) {
+		return nil, ErrVerificationRequired
+	}
+	return session, nil
+}
+
+func (s *verificationService) allowsChannel(channel string) bool {
+	for _, allowed := range s.settings.Channels {
+		if allowed == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// hashCode binds a code to the contact it was sent to, so a stored hash
+// cannot be checked against another contact's code
+func (s *verificationService) hashCode(contactHash []byte, code string) []byte {
+	return s.hasher.HashValue("code:" + hex.EncodeToString(contactHash) + ":" + code)
+}
+
+// newVerificationCode returns a uniformly random numeric code
+func newVerificationCode() (string, error) {
+	limit := big.NewInt(1)
+	for i := 0; i < verificationCodeDigits; i++ {
+		limit.Mul(limit, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", fmt.Errorf("generate verification code: %w", err)
+	}
+	return fmt.Sprintf("%0*d", verificationCodeDigits, n), nil
+}
+
+// NormalizeContact returns the channel and canonical form of an email address
+// or phone number. Emails are lower-cased; phone numbers are reduced to digits
+// with a leading +, and Indonesian numbers starting with 0 get the +62 prefix.
+func NormalizeContact(contact string) (string, string, error) {
+	contact = strings.TrimSpace(contact)
+
+	if strings.Contains(contact, "@") {
+		addr, err := mail.ParseAddress(contact)
+		if err != nil || addr.Address != contact || addr.Name != "" {
+			return "", "", fmt.Errorf("%w: invalid email address", ErrInvalidInput)
+		}
+		return ContactEmail, strings.ToLower(addr.Address), nil
+	}
+
+	digits := strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9':
+			return r
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+			return -1
+		case r == '+':
+			return r
+		default:
+			return 'x'
+		}
+	}, contact)
+
+	switch {
+	case strings.HasPrefix(digits, "+"):
+		digits = digits[1:]
+	case strings.HasPrefix(digits, "0"):
+		digits = "62" + digits[1:]
+	}
+	if len(digits) < 8 || len(digits) > 15 || strings.ContainsAny(digits, "+x") {
+		return "", "", fmt.Errorf("%w: invalid email address or phone number", ErrInvalidInput)
+	}
+	return ContactPhone, "+" + digits, nil
+}
+
+// MaskContact hides most of a contact for display
+func MaskContact(channel, contact string) string {
+	if channel == ContactEmail {
+		local, host, _ := strings.Cut(contact, "@")
+		if len(local) > 1 {
+			local = local[:1] + strings.Repeat("*", len(local)-1)
+		}
+		return local + "@" + host
+	}
+	if len(contact) <= 7 {
+		return contact
+	}
+	return contact[:5] + strings.Repeat("*", len(contact)-8) + contact[len(contact)-3:]
+}
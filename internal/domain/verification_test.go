@@ -0,0 +1,335 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"voteweb/internal/util"
+)
+
+// Mock verification repository
+type mockVerificationRepository struct {
+	codes    []*VerificationCode
+	sessions map[string]*VoterSession
+}
+
+func newMockVerificationRepository() *mockVerificationRepository {
+	return &mockVerificationRepository{sessions: make(map[string]*VoterSession)}
+}
+
+func (m *mockVerificationRepository) CreateVerificationCode(ctx context.Context, code *VerificationCode) error {
+	code.ID = fmt.Sprintf("code-%d", len(m.codes)+1)
+	m.codes = append(m.codes, code)
+	return nil
+}
+
+func (m *mockVerificationRepository) GetLatestVerificationCode(ctx context.Context, eventID string, contactHash []byte) (*VerificationCode, error) {
+	for i := len(m.codes) - 1; i >= 0; i-- {
+		code := m.codes[i]
+		if code.EventID == eventID && string(code.ContactHash) == string(contactHash) {
+			copied := *code
+			return &copied, nil
+		}
+	}
+	return nil, ErrInvalidCode
+}
+
+func (m *mockVerificationRepository) CountVerificationCodes(ctx context.Context, eventID string, contactHash []byte, since time.Time) (int, error) {
+	count := 0
+	for _, code := range m.codes {
+		if code.EventID == eventID && string(code.ContactHash) == string(contactHash) && !code.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *mockVerificationRepository) RecordVerificationAttempt(ctx context.Context, id string) (int, error) {
+	for _, code := range m.codes {
+		if code.ID == id {
+			code.Attempts++
+			return code.Attempts, nil
+		}
+	}
+	return 0, ErrInvalidCode
+}
+
+func (m *mockVerificationRepository) UseVerificationCode(ctx context.Context, id string, at time.Time) (bool, error) {
+	for _, code := range m.codes {
+		if code.ID == id && code.UsedAt == nil {
+			code.UsedAt = &at
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockVerificationRepository) CreateVoterSession(ctx context.Context, session *VoterSession) error {
+	m.sessions[string(session.TokenHash)] = session
+	return nil
+}
+
+func (m *mockVerificationRepository) GetVoterSession(ctx context.Context, tokenHash []byte) (*VoterSession, error) {
+	session, ok := m.sessions[string(tokenHash)]
+	if !ok {
+		return nil, ErrVerificationRequired
+	}
+	return session, nil
+}
+
+// Mock code sender that remembers the last message
+type mockCodeSender struct {
+	sent []VerificationMessage
+	err  error
+}
+
+func (m *mockCodeSender) SendCode(ctx context.Context, msg VerificationMessage) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func (m *mockCodeSender) lastCode() string {
+	if len(m.sent) == 0 {
+		return ""
+	}
+	return m.sent[len(m.sent)-1].Code
+}
+
+func newTestVerificationService(settings VerificationSettings) (*verificationService, *mockVerificationRepository, *mockCodeSender, *time.Time) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	repo := newMockVerificationRepository()
+	sender := &mockCodeSender{}
+	service := NewVerificationService(repo, sender, util.NewIPHasher("test-salt"), util.NewTokenSigner("test-secret"), settings, logger).(*verificationService)
+
+	now := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	return service, repo, sender, &now
+}
+
+func otpEvent() *Event {
+	return &Event{ID: testEventID, Slug: "test-event", Name: "Test Event", VoterVerification: VoterVerificationOTP}
+}
+
+func TestNormalizeContact(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantChannel string
+		want        string
+		wantErr     bool
+	}{
+		{name: "email lower-cased", input: "  Budi@Example.COM ", wantChannel: ContactEmail, want: "budi@example.com"},
+		{name: "email with display name rejected", input: "Budi <budi@example.com>", wantErr: true},
+		{name: "invalid email", input: "budi@", wantErr: true},
+		{name: "local phone gets +62", input: "0812-3456-7890", wantChannel: ContactPhone, want: "+6281234567890"},
+		{name: "international phone", input: "+62 812 3456 7890", wantChannel: ContactPhone, want: "+6281234567890"},
+		{name: "too short", input: "12345", wantErr: true},
+		{name: "letters rejected", input: "0812abc4567", wantErr: true},
+		{name: "plus in the middle rejected", input: "0812+34567890", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channel, got, err := NormalizeContact(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidInput) {
+					t.Errorf("NormalizeContact() error = %v, want ErrInvalidInput", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeContact() error = %v", err)
+			}
+			if channel != tt.wantChannel || got != tt.want {
+				t.Errorf("NormalizeContact() = %q, %q, want %q, %q", channel, got, tt.wantChannel, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskContact(t *testing.T) {
+	if got := MaskContact(ContactEmail, "budi@example.com"); got != "b***@example.com" {
+		t.Errorf("MaskContact(email) = %q", got)
+	}
+	if got := MaskContact(ContactPhone, "+6281234567890"); got != "+6281******890" {
+		t.Errorf("MaskContact(phone) = %q", got)
+	}
+}
+
+func TestVerificationService_RequestAndConfirm(t *testing.T) {
+	service, _, sender, now := newTestVerificationService(VerificationSettings{})
+	ctx := context.Background()
+	event := otpEvent()
+
+	request, err := service.RequestCode(ctx, event, "Budi@Example.com")
+	if err != nil {
+		t.Fatalf("RequestCode() error = %v", err)
+	}
+	if request.Channel != ContactEmail || request.Destination != "b***@example.com" {
+		t.Errorf("RequestCode() = %+v", request)
+	}
+	if len(sender.sent) != 1 || sender.sent[0].To != "budi@example.com" || len(sender.lastCode()) != 6 {
+		t.Fatalf("sent = %+v, want one 6-digit code to the normalized address", sender.sent)
+	}
+
+	*now = now.Add(time.Minute)
+	session, cookie, err := service.ConfirmCode(ctx, event, "budi@example.com", sender.lastCode())
+	if err != nil {
+		t.Fatalf("ConfirmCode() error = %v", err)
+	}
+	if cookie == "" || len(session.ContactHash) == 0 {
+		t.Fatalf("ConfirmCode() = %+v, %q", session, cookie)
+	}
+
+	voter, err := service.Voter(ctx, event, cookie)
+	if err != nil {
+		t.Fatalf("Voter() error = %v", err)
+	}
+	if string(voter.ContactHash) != string(session.ContactHash) {
+		t.Error("Voter() returned a different contact")
+	}
+
+	// A code works once
+	if _, _, err := service.ConfirmCode(ctx, event, "budi@example.com", sender.lastCode()); !errors.Is(err, ErrInvalidCode) {
+		t.Errorf("ConfirmCode() reuse error = %v, want ErrInvalidCode", err)
+	}
+
+	// The session belongs to one event and expires
+	if _, err := service.Voter(ctx, &Event{ID: "other-event"}, cookie); !errors.Is(err, ErrVerificationRequired) {
+		t.Errorf("Voter() other event error = %v, want ErrVerificationRequired", err)
+	}
+	*now = now.Add(DefaultVerificationSettings.SessionTTL)
+	if _, err := service.Voter(ctx, event, cookie); !errors.Is(err, ErrVerificationRequired) {
+		t.Errorf("Voter() expired error = %v, want ErrVerificationRequired", err)
+	}
+	if _, err := service.Voter(ctx, event, "forged"); !errors.Is(err, ErrVerificationRequired) {
+		t.Errorf("Voter() forged error = %v, want ErrVerificationRequired", err)
+	}
+}
+
+func TestVerificationService_Limits(t *testing.T) {
+	ctx := context.Background()
+	event := otpEvent()
+
+	t.Run("code expires", func(t *testing.T) {
+		service, _, sender, now := newTestVerificationService(VerificationSettings{})
+		if _, err := service.RequestCode(ctx, event, "budi@example.com"); err != nil {
+			t.Fatalf("RequestCode() error = %v", err)
+		}
+		*now = now.Add(DefaultVerificationSettings.CodeTTL)
+		if _, _, err := service.ConfirmCode(ctx, event, "budi@example.com", sender.lastCode()); !errors.Is(err, ErrInvalidCode) {
+			t.Errorf("ConfirmCode() error = %v, want ErrInvalidCode", err)
+		}
+	})
+
+	t.Run("wrong guesses lock the code", func(t *testing.T) {
+		service, _, sender, _ := newTestVerificationService(VerificationSettings{MaxAttempts: 3})
+		if _, err := service.RequestCode(ctx, event, "budi@example.com"); err != nil {
+			t.Fatalf("RequestCode() error = %v", err)
+		}
+		wrong := "000000"
+		if sender.lastCode() == wrong {
+			wrong = "111111"
+		}
+		for i, want := range []error{ErrInvalidCode, ErrInvalidCode, ErrTooManyAttempts} {
+			if _, _, err := service.ConfirmCode(ctx, event, "budi@example.com", wrong); !errors.Is(err, want) {
+				t.Errorf("attempt %d error = %v, want %v", i+1, err, want)
+			}
+		}
+		if _, _, err := service.ConfirmCode(ctx, event, "budi@example.com", sender.lastCode()); !errors.Is(err, ErrTooManyAttempts) {
+			t.Errorf("ConfirmCode() after lockout error = %v, want ErrTooManyAttempts", err)
+		}
+	})
+
+	t.Run("resend interval and hourly cap", func(t *testing.T) {
+		service, _, _, now := newTestVerificationService(VerificationSettings{MaxCodesPerHour: 2})
+		if _, err := service.RequestCode(ctx, event, "budi@example.com"); err != nil {
+			t.Fatalf("RequestCode() error = %v", err)
+		}
+		if _, err := service.RequestCode(ctx, event, "budi@example.com"); !errors.Is(err, ErrCodeRequestLimited) {
+			t.Errorf("immediate resend error = %v, want ErrCodeRequestLimited", err)
+		}
+		*now = now.Add(2 * time.Minute)
+		if _, err := service.RequestCode(ctx, event, "budi@example.com"); err != nil {
+			t.Errorf("resend after interval error = %v", err)
+		}
+		*now = now.Add(2 * time.Minute)
+		if _, err := service.RequestCode(ctx, event, "budi@example.com"); !errors.Is(err, ErrCodeRequestLimited) {
+			t.Errorf("third code in an hour error = %v, want ErrCodeRequestLimited", err)
+		}
+	})
+
+	t.Run("channel not enabled", func(t *testing.T) {
+		service, _, _, _ := newTestVerificationService(VerificationSettings{Channels: []string{ContactEmail}})
+		if _, err := service.RequestCode(ctx, event, "081234567890"); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("RequestCode(phone) error = %v, want ErrInvalidInput", err)
+		}
+	})
+
+	t.Run("event without verification", func(t *testing.T) {
+		service, _, _, _ := newTestVerificationService(VerificationSettings{})
+		if _, err := service.RequestCode(ctx, &Event{ID: testEventID}, "budi@example.com"); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("RequestCode() error = %v, want ErrInvalidInput", err)
+		}
+	})
+}
+
+func TestVoteService_SubmitVote_VerifiedVoters(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	repo := newMockRepository()
+	repo.events[testEventID].VoterVerification = VoterVerificationOTP
+	repo.addInnovation(&Innovation{ID: "test-id-1", GroupSlug: "test-group", Slug: "test-innovation", Name: "Test Innovation"})
+	service := NewVoteService(repo, &mockIPHasher{}, &mockAuditRecorder{}, &mockVotePublisher{}, VoterDedupe{}, logger)
+	ctx := context.Background()
+
+	vote := func(contact string) (*VoteResponse, error) {
+		req := VoteRequest{
+			EventID:   testEventID,
+			GroupSlug: "test-group",
+			Slug:      "test-innovation",
+			ClientIP:  "203.0.113.7", // one school NAT
+		}
+		if contact != "" {
+			req.ContactHash = []byte("contact:" + contact)
+		}
+		return service.SubmitVote(ctx, req)
+	}
+
+	if _, err := vote(""); !errors.Is(err, ErrVerificationRequired) {
+		t.Fatalf("SubmitVote() unverified error = %v, want ErrVerificationRequired", err)
+	}
+
+	for _, contact := range []string{"budi", "siti"} {
+		result, err := vote(contact)
+		if err != nil || !result.Success {
+			t.Fatalf("SubmitVote(%s) = %+v, %v, want success behind a shared IP", contact, result, err)
+		}
+	}
+
+	result, err := vote("budi")
+	if err != nil || !result.AlreadyVoted {
+		t.Errorf("SubmitVote(budi) again = %+v, %v, want already voted", result, err)
+	}
+	if got := repo.votes[0]; string(got.VoterIPHash) != "203.0.113.7" || string(got.VoterKey) != "contact:budi" {
+		t.Errorf("vote keys = %q, %q, want the IP hash kept and the contact as voter key", got.VoterIPHash, got.VoterKey)
+	}
+
+	event := repo.events[testEventID]
+	innovation := repo.innovations["test-id-1"]
+	voted, err := service.CheckHasVoted(ctx, event, innovation, VoterIdentity{ClientIP: "203.0.113.7"})
+	if err != nil || voted {
+		t.Errorf("CheckHasVoted(unverified) = %v, %v, want false", voted, err)
+	}
+	voted, err = service.CheckHasVoted(ctx, event, innovation, VoterIdentity{ClientIP: "203.0.113.7", ContactHash: []byte("contact:siti")})
+	if err != nil || !voted {
+		t.Errorf("CheckHasVoted(siti) = %v, %v, want true", voted, err)
+	}
+}
@@ -0,0 +1,57 @@
+package domain
+
+// Voter dedupe modes: what identifies a voter of an event that does not
+// verify voters with one-time codes
+const (
+	// VoterDedupeIP allows one vote per IP identity in each scope
+	VoterDedupeIP = "ip"
+	// VoterDedupeToken allows one vote per voter-token cookie in each scope
+	VoterDedupeToken = "token"
+	// VoterDedupeTokenIP allows one vote per voter token in each scope and at
+	// most MaxPerIP votes per IP identity
+	VoterDedupeTokenIP = "token+ip"
+)
+
+// VoterDedupe decides how votes without a verified contact are deduplicated.
+// The zero value deduplicates by IP.
+type VoterDedupe struct {
+	Mode string
+	// MaxPerIP caps the votes one IP identity can cast in a scope in
+	// token+ip mode
+	MaxPerIP int
+}
+
+// usesToken reports whether votes are deduplicated by voter token
+func (d VoterDedupe) usesToken() bool {
+	return d.Mode == VoterDedupeToken || d.Mode == VoterDedupeTokenIP
+}
+
+// capsIP reports whether votes per IP identity are capped
+func (d VoterDedupe) capsIP() bool {
+	return d.Mode == VoterDedupeTokenIP && d.MaxPerIP > 0
+}
+
+// voterKey returns what a vote is deduplicated by: the verified contact for
+// events with one-time codes, then the voter token when the dedupe mode uses
+// one, the IP hash otherwise. A voter whose browser does not keep the token
+// cookie falls back to the IP hash.
+func (d VoterDedupe) voterKey(event *Event, voter VoterIdentity, ipHash []byte) ([]byte, error) {
+	if event.VoterVerification == VoterVerificationOTP {
+		if len(voter.ContactHash) == 0 {
+			return nil, ErrVerificationRequired
+		}
+		return voter.ContactHash, nil
+	}
+	if d.usesToken() && voter.Token != "" {
+		return voterTokenHash(voter.Token), nil
+	}
+	return ipHash, nil
+}
+
+// voterTokenHash returns the digest of a voter token stored with votes
+func voterTokenHash(token string) []byte {
+	if token == "" {
+		return nil
+	}
+	return hashSessionToken(token)
+}
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+)
+
+func TestVoteService_SubmitVote_VoterDedupe(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	ctx := context.Background()
+
+	// Three voters behind one school NAT, one of them without cookies
+	voters := []VoteRequest{
+		{ClientIP: "203.0.113.7", VoterToken: "token-a"},
+		{ClientIP: "203.0.113.7", VoterToken: "token-b"},
+		{ClientIP: "203.0.113.7", VoterToken: "token-c"},
+		{ClientIP: "203.0.113.7"},
+	}
+
+	tests := []struct {
+		name   string
+		dedupe VoterDedupe
+		// want is whether each voter's first vote counts
+		want []bool
+	}{
+		{
+			name:   "ip",
+			dedupe: VoterDedupe{},
+			want:   []bool{true, false, false, false},
+		},
+		{
+			name:   "token",
+			dedupe: VoterDedupe{Mode: VoterDedupeToken},
+			want:   []bool{true, true, true, true},
+		},
+		{
+			name:   "token with ip cap",
+			dedupe: VoterDedupe{Mode: VoterDedupeTokenIP, MaxPerIP: 2},
+			want:   []bool{true, true, false, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockRepository()
+			repo.addInnovation(&Innovation{ID: "test-id-1", GroupSlug: "test-group", Slug: "test-innovation", Name: "Test Innovation"})
+			service := NewVoteService(repo, &mockIPHasher{}, &mockAuditRecorder{}, &mockVotePublisher{}, tt.dedupe, logger)
+
+			for i, voter := range voters {
+				voter.EventID = testEventID
+				voter.GroupSlug = "test-group"
+				voter.Slug = "test-innovation"
+
+				result, err := service.SubmitVote(ctx, voter)
+				if err != nil {
+					t.Fatalf("SubmitVote(voter %d) error = %v", i, err)
+				}
+				if result.Success != tt.want[i] {
+					t.Errorf("SubmitVote(voter %d) success = %v, want %v", i, result.Success, tt.want[i])
+				}
+				if !result.Success && !result.AlreadyVoted {
+					t.Errorf("SubmitVote(voter %d) rejected without already_voted", i)
+				}
+			}
+
+			// A second vote with the same token, or again without one, never
+			// counts
+			for _, i := range []int{0, 3} {
+				again := voters[i]
+				again.EventID, again.GroupSlug, again.Slug = testEventID, "test-group", "test-innovation"
+				if result, err := service.SubmitVote(ctx, again); err != nil || result.Success {
+					t.Errorf("SubmitVote(voter %d) again = %+v, %v, want already voted", i, result, err)
+				}
+			}
+
+			for _, vote := range repo.votes {
+				if string(vote.VoterIPHash) != "203.0.113.7" {
+					t.Errorf("vote IP hash = %q, want the IP hash kept in every mode", vote.VoterIPHash)
+				}
+			}
+		})
+	}
+}
+
+func TestVoteService_CheckHasVoted_VoterToken(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	repo := newMockRepository()
+	repo.addInnovation(&Innovation{ID: "test-id-1", GroupSlug: "test-group", Slug: "test-innovation", Name: "Test Innovation"})
+	service := NewVoteService(repo, &mockIPHasher{}, &mockAuditRecorder{}, &mockVotePublisher{}, VoterDedupe{Mode: VoterDedupeToken}, logger)
+	ctx := context.Background()
+
+	_, err := service.SubmitVote(ctx, VoteRequest{
+		EventID:    testEventID,
+		GroupSlug:  "test-group",
+		Slug:       "test-innovation",
+		ClientIP:   "203.0.113.7",
+		VoterToken: "token-a",
+	})
+	if err != nil {
+		t.Fatalf("SubmitVote() error = %v", err)
+	}
+	if got := repo.votes[0]; string(got.VoterTokenHash) != string(voterTokenHash("token-a")) || string(got.VoterKey) != string(got.VoterTokenHash) {
+		t.Errorf("vote keys = %x, %x, want the token hash as voter key", got.VoterKey, got.VoterTokenHash)
+	}
+
+	event := repo.events[testEventID]
+	innovation := repo.innovations["test-id-1"]
+	tests := []struct {
+		name  string
+		voter VoterIdentity
+		want  bool
+	}{
+		{"same token", VoterIdentity{ClientIP: "198.51.100.1", Token: "token-a"}, true},
+		{"other token, same IP", VoterIdentity{ClientIP: "203.0.113.7", Token: "token-b"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			voted, err := service.CheckHasVoted(ctx, event, innovation, tt.voter)
+			if err != nil || voted != tt.want {
+				t.Errorf("CheckHasVoted() = %v, %v, want %v", voted, err, tt.want)
+			}
+		})
+	}
+}
@@ -1,25 +1,137 @@
 package handlers
 
 import (
+	"errors"
+	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+
+	"voteweb/internal/domain"
+	"voteweb/internal/http/middleware"
 )
 
-type AdminHandler struct{}
+type AdminHandler struct {
+	auth          domain.AdminAuthService
+	secureCookies bool
+	logger        *slog.Logger
+}
+
+func NewAdminHandler(auth domain.AdminAuthService, secureCookies bool, logger *slog.Logger) *AdminHandler {
+	return &AdminHandler{
+		auth:          auth,
+		secureCookies: secureCookies,
+		logger:        logger,
+	}
+}
 
-func NewAdminHandler() *AdminHandler {
-	return &AdminHandler{}
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Next     string `json:"next"`
 }
 
 func (h *AdminHandler) ShowLogin(c *gin.Context) {
 	c.HTML(http.StatusOK, "admin_login.tmpl.html", gin.H{
-		"Title": "Admin Login",
+		"Title":     "Admin Login",
+		"CSRFToken": middleware.GetCSRFToken(c),
+		"Next":      safeAdminRedirect(c.Query("next")),
 	})
 }
 
 func (h *AdminHandler) ShowDashboardViewer(c *gin.Context) {
 	c.HTML(http.StatusOK, "analytics_viewer.tmpl.html", gin.H{
-		"Title": "Analytics Dashboard",
+		"Title":          "Analytics Dashboard",
+		"CSRFToken":      middleware.GetCSRFToken(c),
+		"CanOperate":     hasAdminRole(c, domain.AdminRoleOperator),
+		"CanManageUsers": hasAdminRole(c, domain.AdminRoleSuperadmin),
+	})
+}
+
+// Login exchanges a username and password for a session cookie
+func (h *AdminHandler) Login(c *gin.Context) {
+	var req loginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	session, cookie, err := h.auth.Login(c.Request.Context(), req.Username, req.Password, c.GetHeader("User-Agent"))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidCredentials) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Username atau password salah"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log in"})
+		return
+	}
+
+	middleware.SetAdminSessionCookie(c, cookie, session.ExpiresAt, h.secureCookies)
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"expires_at": session.ExpiresAt,
+		"redirect":   safeAdminRedirect(req.Next),
+	})
+}
+
+// Logout revokes the current session and clears the cookie
+func (h *AdminHandler) Logout(c *gin.Context) {
+	if cookie, err := c.Cookie(middleware.AdminSessionCookieName); err == nil {
+		if err := h.auth.Logout(c.Request.Context(), cookie); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out"})
+			return
+		}
+	}
+
+	middleware.ClearAdminSessionCookie(c, h.secureCookies)
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// ListSessions returns the active admin sessions
+func (h *AdminHandler) ListSessions(c *gin.Context) {
+	sessions, err := h.auth.ListSessions(c.Request.Context())
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to list admin sessions", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load sessions"})
+		return
+	}
+
+	currentID := ""
+	if current := middleware.GetAdminSession(c); current != nil {
+		currentID = current.ID
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sessions":   sessions,
+		"current_id": currentID,
 	})
 }
+
+// RevokeSession ends another admin session immediately
+func (h *AdminHandler) RevokeSession(c *gin.Context) {
+	if err := h.auth.RevokeSession(c.Request.Context(), c.Param("id")); err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// hasAdminRole reports whether the logged-in admin has at least the given role
+func hasAdminRole(c *gin.Context, role domain.AdminRole) bool {
+	session := middleware.GetAdminSession(c)
+	return session != nil && session.Role.Allows(role)
+}
+
+// safeAdminRedirect only allows redirects to local admin pages
+func safeAdminRedirect(next string) string {
+	if strings.HasPrefix(next, "/admin/") && !strings.HasPrefix(next, "/admin/login") && !strings.Contains(next, "\\") {
+		return next
+	}
+	return "/admin/dashboard"
+}
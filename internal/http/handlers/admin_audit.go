@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"voteweb/internal/domain"
+	"voteweb/internal/http/middleware"
+)
+
+// AdminAuditHandler serves the audit log to superadmins
+type AdminAuditHandler struct {
+	audit  domain.AuditService
+	logger *slog.Logger
+}
+
+func NewAdminAuditHandler(audit domain.AuditService, logger *slog.Logger) *AdminAuditHandler {
+	return &AdminAuditHandler{
+		audit:  audit,
+		logger: logger,
+	}
+}
+
+// ShowAudit renders the client-side audit log viewer
+func (h *AdminAuditHandler) ShowAudit(c *gin.Context) {
+	c.HTML(http.StatusOK, "admin_audit.tmpl.html", gin.H{
+		"Title":     "Audit Log",
+		"CSRFToken": middleware.GetCSRFToken(c),
+	})
+}
+
+// ListEvents returns one page of audit events, newest first
+func (h *AdminAuditHandler) ListEvents(c *gin.Context) {
+	filter, err := auditFilterFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.audit.ListEvents(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load audit log"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// ExportEvents streams every matching event as a JSON array download
+func (h *AdminAuditHandler) ExportEvents(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	filter, err := auditFilterFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Record the export first so it appears in its own output
+	h.audit.Record(ctx, domain.AuditLogExported, "audit_log", "", nil, c.Request.URL.Query())
+
+	filename := fmt.Sprintf("audit-%s.json", time.Now().UTC().Format("20060102-150405"))
+	c.Header("Content-Type", "application/json")
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+
+	encoder := json.NewEncoder(c.Writer)
+	first := true
+	c.Writer.WriteString("[\n")
+	err = h.audit.ExportEvents(ctx, filter, func(event *domain.AuditEvent) error {
+		if !first {
+			if _, err := c.Writer.WriteString(","); err != nil {
+				return err
+			}
+		}
+		first = false
+		return encoder.Encode(event)
+	})
+	if err != nil {
+		// Headers are already sent; a truncated array tells the client it failed
+		h.logger.ErrorContext(ctx, "failed to export audit log", "error", err)
+		return
+	}
+	c.Writer.WriteString("]\n")
+}
+
+// auditFilterFromQuery reads the filter from the query string. Times are RFC 3339.
+func auditFilterFromQuery(c *gin.Context) (domain.AuditFilter, error) {
+	filter := domain.AuditFilter{
+		Action:     domain.AuditAction(c.Query("action")),
+		Actor:      c.Query("actor"),
+		TargetType: c.Query("target_type"),
+		TargetID:   c.Query("target_id"),
+	}
+
+	for _, param := range []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"since", &filter.Since},
+		{"until", &filter.Until},
+	} {
+		if value := c.Query(param.name); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return filter, fmt.Errorf("%s must be an RFC 3339 time", param.name)
+			}
+			*param.dst = &t
+		}
+	}
+
+	if value := c.Query("before_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || id <= 0 {
+			return filter, fmt.Errorf("before_id must be a positive integer")
+		}
+		filter.BeforeID = id
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return filter, fmt.Errorf("limit must be a positive integer")
+		}
+		filter.Limit = limit
+	}
+
+	return filter, nil
+}
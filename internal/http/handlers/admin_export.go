@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"voteweb/internal/domain"
+	"voteweb/internal/util"
+)
+
+// AdminExportHandler serves spreadsheet downloads of the results
+type AdminExportHandler struct {
+	service domain.VoteService
+	audit   domain.AuditRecorder
+	logger  *slog.Logger
+}
+
+func NewAdminExportHandler(service domain.VoteService, audit domain.AuditRecorder, logger *slog.Logger) *AdminExportHandler {
+	return &AdminExportHandler{
+		service: service,
+		audit:   audit,
+		logger:  logger,
+	}
+}
+
+// exportFormats maps the format query parameter to its content type
+var exportFormats = map[string]string{
+	"csv":  "text/csv; charset=utf-8",
+	"xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// ExportTotals downloads every active innovation with its vote count
+func (h *AdminExportHandler) ExportTotals(c *gin.Context) {
+	h.export(c, domain.ReportTotals)
+}
+
+// ExportRankings downloads the per-group rankings
+func (h *AdminExportHandler) ExportRankings(c *gin.Context) {
+	h.export(c, domain.ReportRankings)
+}
+
+// ExportVotes downloads every raw vote, streamed from the database
+func (h *AdminExportHandler) ExportVotes(c *gin.Context) {
+	h.export(c, domain.ReportVotes)
+}
+
+// export resolves the event and format, sends the download headers and writes
+// the report. Errors after the headers are sent can only truncate the file.
+func (h *AdminExportHandler) export(c *gin.Context, report string) {
+	ctx := c.Request.Context()
+
+	format := c.DefaultQuery("format", "csv")
+	contentType, ok := exportFormats[format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or xlsx"})
+		return
+	}
+
+	event, err := h.service.GetEvent(ctx, eventSlugFromRequest(c))
+	if err != nil {
+		if errors.Is(err, domain.ErrEventNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+			return
+		}
+		h.logger.ErrorContext(ctx, "failed to get event", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export results"})
+		return
+	}
+
+	var w util.TableWriter
+	if format == "xlsx" {
+		w, err = util.NewXLSXTableWriter(c.Writer, report)
+		if err != nil {
+			h.logger.ErrorContext(ctx, "failed to create workbook", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export results"})
+			return
+		}
+	} else {
+		w = util.NewCSVTableWriter(c.Writer)
+	}
+
+	clearWriteDeadline(c, h.logger)
+	h.audit.Record(ctx, domain.AuditResultsExported, "event", event.ID, nil, gin.H{
+		"report": report,
+		"format": format,
+	})
+
+	filename := fmt.Sprintf("%s-%s-%s.%s", event.Slug, report, time.Now().UTC().Format("20060102-150405"), format)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+
+	if err := domain.WriteReport(ctx, h.service, event.ID, report, w); err != nil {
+		h.logger.ErrorContext(ctx, "failed to export results",
+			"report", report,
+			"format", format,
+			"error", err)
+		return
+	}
+	if err := w.Close(); err != nil {
+		h.logger.ErrorContext(ctx, "failed to finish export",
+			"report", report,
+			"format", format,
+			"error", err)
+	}
+}
+
+// clearWriteDeadline lets a long download or stream outlive the server's
+// write timeout
+func clearWriteDeadline(c *gin.Context, logger *slog.Logger) {
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		logger.WarnContext(c.Request.Context(), "failed to clear write deadline", "error", err)
+	}
+}
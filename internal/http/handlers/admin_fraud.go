@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"voteweb/internal/domain"
+	"voteweb/internal/http/middleware"
+)
+
+// AdminFraudHandler serves the suspicious votes review queue to operators
+type AdminFraudHandler struct {
+	service domain.VoteService
+	fraud   domain.FraudService
+	logger  *slog.Logger
+}
+
+func NewAdminFraudHandler(service domain.VoteService, fraud domain.FraudService, logger *slog.Logger) *AdminFraudHandler {
+	return &AdminFraudHandler{
+		service: service,
+		fraud:   fraud,
+		logger:  logger,
+	}
+}
+
+type reviewVotesRequest struct {
+	IDs    []int64 `json:"ids"`
+	Reason string  `json:"reason"`
+}
+
+// ShowFraud renders the client-side review queue
+func (h *AdminFraudHandler) ShowFraud(c *gin.Context) {
+	c.HTML(http.StatusOK, "admin_fraud.tmpl.html", gin.H{
+		"Title":     "Vote Mencurigakan",
+		"CSRFToken": middleware.GetCSRFToken(c),
+	})
+}
+
+// ListVotes returns one page of flagged votes, newest first
+func (h *AdminFraudHandler) ListVotes(c *gin.Context) {
+	event, ok := resolveEvent(c, h.service, h.logger)
+	if !ok {
+		return
+	}
+
+	filter, err := flaggedVoteFilterFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.EventID = event.ID
+
+	page, err := h.fraud.ListFlaggedVotes(c.Request.Context(), filter)
+	if err != nil {
+		writeError(c, h.logger, err, "Failed to load flagged votes")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// Analyse flags the event's recent votes now instead of waiting for the next run
+func (h *AdminFraudHandler) Analyse(c *gin.Context) {
+	event, ok := resolveEvent(c, h.service, h.logger)
+	if !ok {
+		return
+	}
+
+	added, err := h.fraud.Analyse(c.Request.Context(), event.ID)
+	if err != nil {
+		writeError(c, h.logger, err, "Failed to analyse votes")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"new_flags": added})
+}
+
+// InvalidateVotes excludes the selected votes from the counts; a reason is required
+func (h *AdminFraudHandler) InvalidateVotes(c *gin.Context) {
+	h.review(c, true)
+}
+
+// RestoreVotes counts the selected votes again and marks them reviewed
+func (h *AdminFraudHandler) RestoreVotes(c *gin.Context) {
+	h.review(c, false)
+}
+
+func (h *AdminFraudHandler) review(c *gin.Context, invalidate bool) {
+	ctx := c.Request.Context()
+
+	event, ok := resolveEvent(c, h.service, h.logger)
+	if !ok {
+		return
+	}
+
+	var req reviewVotesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	var changed int
+	var err error
+	if invalidate {
+		changed, err = h.fraud.InvalidateVotes(ctx, event.ID, req.IDs, req.Reason)
+	} else {
+		changed, err = h.fraud.RestoreVotes(ctx, event.ID, req.IDs)
+	}
+	if err != nil {
+		writeError(c, h.logger, err, "Failed to review votes")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"changed": changed})
+}
+
+// flaggedVoteFilterFromQuery reads status, rule, before_id and limit from the query string
+func flaggedVoteFilterFromQuery(c *gin.Context) (domain.FlaggedVoteFilter, error) {
+	filter := domain.FlaggedVoteFilter{
+		Status: c.Query("status"),
+		Rule:   c.Query("rule"),
+	}
+
+	if value := c.Query("before_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || id <= 0 {
+			return filter, fmt.Errorf("before_id must be a positive integer")
+		}
+		filter.BeforeID = id
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return filter, fmt.Errorf("limit must be a positive integer")
+		}
+		filter.Limit = limit
+	}
+
+	return filter, nil
+}
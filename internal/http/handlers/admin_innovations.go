@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"voteweb/internal/domain"
+	"voteweb/internal/http/middleware"
+)
+
+type AdminInnovationHandler struct {
+	service     domain.VoteService
+	innovations domain.InnovationService
+	logger      *slog.Logger
+}
+
+func NewAdminInnovationHandler(service domain.VoteService, innovations domain.InnovationService, logger *slog.Logger) *AdminInnovationHandler {
+	return &AdminInnovationHandler{
+		service:     service,
+		innovations: innovations,
+		logger:      logger,
+	}
+}
+
+type reorderRequest struct {
+	GroupSlug string   `json:"group_slug"`
+	IDs       []string `json:"ids"`
+}
+
+// ShowInnovations renders the client-side innovation manager
+func (h *AdminInnovationHandler) ShowInnovations(c *gin.Context) {
+	c.HTML(http.StatusOK, "admin_innovations.tmpl.html", gin.H{
+		"Title":     "Kelola Inovasi",
+		"CSRFToken": middleware.GetCSRFToken(c),
+	})
+}
+
+// ListInnovations returns the groups and all innovations of the event, archived included
+func (h *AdminInnovationHandler) ListInnovations(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	event, ok := resolveEvent(c, h.service, h.logger)
+	if !ok {
+		return
+	}
+
+	groups, err := h.service.ListGroups(ctx, event.ID)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list groups", "event_id", event.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load innovations"})
+		return
+	}
+
+	innovations, err := h.innovations.ListInnovations(ctx, event.ID)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to list innovations", "event_id", event.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load innovations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"event":       event,
+		"groups":      groups,
+		"innovations": innovations,
+	})
+}
+
+func (h *AdminInnovationHandler) GetInnovation(c *gin.Context) {
+	innovation, err := h.innovations.GetInnovation(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		writeError(c, h.logger, err, "Failed to load innovation")
+		return
+	}
+
+	c.JSON(http.StatusOK, innovation)
+}
+
+func (h *AdminInnovationHandler) CreateInnovation(c *gin.Context) {
+	event, ok := resolveEvent(c, h.service, h.logger)
+	if !ok {
+		return
+	}
+
+	var input domain.InnovationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	input.EventID = event.ID
+
+	innovation, err := h.innovations.CreateInnovation(c.Request.Context(), input)
+	if err != nil {
+		writeError(c, h.logger, err, "Failed to create innovation")
+		return
+	}
+
+	c.JSON(http.StatusCreated, innovation)
+}
+
+func (h *AdminInnovationHandler) UpdateInnovation(c *gin.Context) {
+	var input domain.InnovationInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	innovation, err := h.innovations.UpdateInnovation(c.Request.Context(), c.Param("id"), input)
+	if err != nil {
+		writeError(c, h.logger, err, "Failed to update innovation")
+		return
+	}
+
+	c.JSON(http.StatusOK, innovation)
+}
+
+func (h *AdminInnovationHandler) ReorderInnovations(c *gin.Context) {
+	event, ok := resolveEvent(c, h.service, h.logger)
+	if !ok {
+		return
+	}
+
+	var req reorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	if err := h.innovations.ReorderInnovations(c.Request.Context(), event.ID, req.GroupSlug, req.IDs); err != nil {
+		writeError(c, h.logger, err, "Failed to reorder innovations")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (h *AdminInnovationHandler) ArchiveInnovation(c *gin.Context) {
+	if err := h.innovations.ArchiveInnovation(c.Request.Context(), c.Param("id")); err != nil {
+		writeError(c, h.logger, err, "Failed to archive innovation")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (h *AdminInnovationHandler) RestoreInnovation(c *gin.Context) {
+	if err := h.innovations.RestoreInnovation(c.Request.Context(), c.Param("id")); err != nil {
+		writeError(c, h.logger, err, "Failed to restore innovation")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
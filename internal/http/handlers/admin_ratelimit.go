@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"voteweb/internal/http/middleware"
+)
+
+// RateLimitHandler reports the vote API rate limit counters
+type RateLimitHandler struct {
+	limit *middleware.RateLimit
+}
+
+func NewRateLimitHandler(limit *middleware.RateLimit) *RateLimitHandler {
+	return &RateLimitHandler{
+		limit: limit,
+	}
+}
+
+// GetStats returns how many vote requests were rejected
+func (h *RateLimitHandler) GetStats(c *gin.Context) {
+	c.JSON(http.StatusOK, h.limit.Stats())
+}
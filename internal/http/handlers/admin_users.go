@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"voteweb/internal/domain"
+	"voteweb/internal/http/middleware"
+)
+
+// AdminUserHandler lets superadmins manage admin accounts
+type AdminUserHandler struct {
+	users  domain.AdminUserService
+	logger *slog.Logger
+}
+
+func NewAdminUserHandler(users domain.AdminUserService, logger *slog.Logger) *AdminUserHandler {
+	return &AdminUserHandler{
+		users:  users,
+		logger: logger,
+	}
+}
+
+// ShowUsers renders the client-side account manager
+func (h *AdminUserHandler) ShowUsers(c *gin.Context) {
+	c.HTML(http.StatusOK, "admin_users.tmpl.html", gin.H{
+		"Title":     "Kelola Admin",
+		"CSRFToken": middleware.GetCSRFToken(c),
+		"CurrentID": middleware.GetAdminSession(c).UserID,
+	})
+}
+
+func (h *AdminUserHandler) ListUsers(c *gin.Context) {
+	users, err := h.users.ListUsers(c.Request.Context())
+	if err != nil {
+		writeError(c, h.logger, err, "Failed to load admin users")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"users": users})
+}
+
+func (h *AdminUserHandler) CreateUser(c *gin.Context) {
+	var input domain.AdminUserInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	user, err := h.users.CreateUser(c.Request.Context(), input)
+	if err != nil {
+		writeError(c, h.logger, err, "Failed to create admin user")
+		return
+	}
+
+	c.JSON(http.StatusCreated, user)
+}
+
+func (h *AdminUserHandler) UpdateUser(c *gin.Context) {
+	var input domain.AdminUserUpdate
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	user, err := h.users.UpdateUser(c.Request.Context(), c.Param("id"), input)
+	if err != nil {
+		writeError(c, h.logger, err, "Failed to update admin user")
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
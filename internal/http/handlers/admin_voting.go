@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"voteweb/internal/domain"
+)
+
+// AdminVotingHandler lets operators pause and resume voting for an event
+type AdminVotingHandler struct {
+	service domain.VoteService
+	logger  *slog.Logger
+}
+
+func NewAdminVotingHandler(service domain.VoteService, logger *slog.Logger) *AdminVotingHandler {
+	return &AdminVotingHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetVoting returns the voting window and current status of the event
+func (h *AdminVotingHandler) GetVoting(c *gin.Context) {
+	event, ok := resolveEvent(c, h.service, h.logger)
+	if !ok {
+		return
+	}
+
+	h.writeWindow(c, event)
+}
+
+// OpenVoting resumes a paused event. The opens_at/closes_at window still applies.
+func (h *AdminVotingHandler) OpenVoting(c *gin.Context) {
+	h.setPaused(c, false)
+}
+
+// CloseVoting pauses voting for the event until it is opened again
+func (h *AdminVotingHandler) CloseVoting(c *gin.Context) {
+	h.setPaused(c, true)
+}
+
+func (h *AdminVotingHandler) setPaused(c *gin.Context, paused bool) {
+	event, ok := resolveEvent(c, h.service, h.logger)
+	if !ok {
+		return
+	}
+
+	event.Window.Paused = paused
+	if err := h.service.UpdateVotingWindow(c.Request.Context(), event.ID, &event.Window); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update voting"})
+		return
+	}
+
+	h.writeWindow(c, event)
+}
+
+func (h *AdminVotingHandler) writeWindow(c *gin.Context, event *domain.Event) {
+	c.JSON(http.StatusOK, gin.H{
+		"event":  event.Slug,
+		"window": event.Window,
+		"status": event.Window.Status(time.Now()),
+	})
+}
@@ -1,161 +1,267 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"voteweb/internal/domain"
 )
 
+// liveHeartbeatInterval keeps idle streams open through proxies
+const liveHeartbeatInterval = 15 * time.Second
+
 type AnalyticsHandler struct {
 	service domain.VoteService
+	audit   domain.AuditRecorder
+	live    *domain.VoteBroadcaster
 	logger  *slog.Logger
 }
 
-func NewAnalyticsHandler(service domain.VoteService, logger *slog.Logger) *AnalyticsHandler {
+func NewAnalyticsHandler(service domain.VoteService, audit domain.AuditRecorder, live *domain.VoteBroadcaster, logger *slog.Logger) *AnalyticsHandler {
 	return &AnalyticsHandler{
 		service: service,
+		audit:   audit,
+		live:    live,
 		logger:  logger,
 	}
 }
 
-// AnalyticsData represents the data structure for analytics page
-type AnalyticsData struct {
-	TotalInnovations int64
-	TotalVotes       int64
-	Innovations      []*InnovationStats
-}
-
-// InnovationStats represents statistics for a single innovation
-type InnovationStats struct {
-	*domain.Innovation
-	VoteCount      int64
-	VotePercentage float64
-}
-
 func (h *AnalyticsHandler) ShowAnalytics(c *gin.Context) {
-	// Get all innovations with their vote counts
-	innovations, err := h.service.ListInnovations(c.Request.Context())
+	event, err := h.service.GetEvent(c.Request.Context(), eventSlugFromRequest(c))
 	if err != nil {
-		h.logger.ErrorContext(c.Request.Context(), "failed to list innovations", "error", err)
-		c.HTML(http.StatusInternalServerError, "error.tmpl.html", gin.H{
+		status := http.StatusInternalServerError
+		message := "An error occurred while loading analytics."
+		if errors.Is(err, domain.ErrEventNotFound) {
+			status = http.StatusNotFound
+			message = "The event you're looking for does not exist."
+		} else {
+			h.logger.ErrorContext(c.Request.Context(), "failed to get event", "error", err)
+		}
+		c.HTML(status, "error.tmpl.html", gin.H{
 			"Title":   "Error",
-			"Message": "An error occurred while loading analytics.",
+			"Message": message,
 		})
 		return
 	}
 
-	// Fetch vote counts for each innovation
-	var totalVotes int64
-	var innovationsWithStats []*InnovationStats
-
-	for _, innovation := range innovations {
-		voteCount, err := h.service.GetVoteCount(c.Request.Context(), innovation.ID)
-		if err != nil {
-			h.logger.ErrorContext(c.Request.Context(), "failed to get vote count",
-				"innovation_id", innovation.ID, "error", err)
-			voteCount = 0
+	// Get all innovations with their vote counts and group rankings
+	groupSlug := c.Query("group")
+	results, err := h.service.GetResults(c.Request.Context(), event.ID, groupSlug)
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "An error occurred while loading analytics."
+		if errors.Is(err, domain.ErrGroupNotFound) {
+			status = http.StatusNotFound
+			message = "The group you're looking for does not exist."
 		}
-
-		totalVotes += voteCount
-
-		// Calculate percentage (we'll do this in template based on max votes)
-		innovationsWithStats = append(innovationsWithStats, &InnovationStats{
-			Innovation: innovation,
-			VoteCount:  voteCount,
+		c.HTML(status, "error.tmpl.html", gin.H{
+			"Title":   "Error",
+			"Message": message,
 		})
+		return
 	}
 
-	// Find max vote count for percentage calculation
-	var maxVotes int64
-	for _, stats := range innovationsWithStats {
-		if stats.VoteCount > maxVotes {
-			maxVotes = stats.VoteCount
-		}
-	}
-
-	// Calculate percentages
-	for _, stats := range innovationsWithStats {
-		if maxVotes > 0 {
-			stats.VotePercentage = (float64(stats.VoteCount) / float64(maxVotes)) * 100
-		} else {
-			stats.VotePercentage = 0
-		}
-	}
-
-	analytics := AnalyticsData{
-		TotalInnovations: int64(len(innovations)),
-		TotalVotes:       totalVotes,
-		Innovations:      innovationsWithStats,
+	groups, err := h.service.ListGroups(c.Request.Context(), event.ID)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to list groups", "error", err)
+		c.HTML(http.StatusInternalServerError, "error.tmpl.html", gin.H{
+			"Title":   "Error",
+			"Message": "An error occurred while loading analytics.",
+		})
+		return
 	}
 
+	h.audit.Record(c.Request.Context(), domain.AuditResultsViewed, "event", event.ID, nil, nil)
 	c.HTML(http.StatusOK, "analytics.tmpl.html", gin.H{
 		"Title":     "Analytics Dashboard",
-		"Analytics": analytics,
-		"MaxVotes":  maxVotes,
+		"Event":     event,
+		"Analytics": results,
+		"MaxVotes":  results.MaxVotes,
+		"Groups":    groups,
+		"Group":     groupSlug,
 	})
 }
 
 // GetAnalyticsData returns analytics data as JSON for client-side rendering
 func (h *AnalyticsHandler) GetAnalyticsData(c *gin.Context) {
-	innovations, err := h.service.ListInnovations(c.Request.Context())
+	event, err := h.service.GetEvent(c.Request.Context(), eventSlugFromRequest(c))
 	if err != nil {
-		h.logger.ErrorContext(c.Request.Context(), "failed to list innovations", "error", err)
+		if errors.Is(err, domain.ErrEventNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Event not found",
+			})
+			return
+		}
+		h.logger.ErrorContext(c.Request.Context(), "failed to get event", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to load analytics data",
 		})
 		return
 	}
 
-	var totalVotes int64
-	var innovationsWithStats []*InnovationStats
-
-	for _, innovation := range innovations {
-		voteCount, err := h.service.GetVoteCount(c.Request.Context(), innovation.ID)
-		if err != nil {
-			h.logger.ErrorContext(c.Request.Context(), "failed to get vote count",
-				"innovation_id", innovation.ID, "error", err)
-			voteCount = 0
+	groupSlug := c.Query("group")
+	results, err := h.service.GetResults(c.Request.Context(), event.ID, groupSlug)
+	if err != nil {
+		if errors.Is(err, domain.ErrGroupNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Group not found",
+			})
+			return
 		}
-
-		totalVotes += voteCount
-		innovationsWithStats = append(innovationsWithStats, &InnovationStats{
-			Innovation: innovation,
-			VoteCount:  voteCount,
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load analytics data",
 		})
+		return
 	}
 
-	// Find max vote count for percentage calculation
-	var maxVotes int64
-	for _, stats := range innovationsWithStats {
-		if stats.VoteCount > maxVotes {
-			maxVotes = stats.VoteCount
-		}
+	h.audit.Record(c.Request.Context(), domain.AuditResultsViewed, "event", event.ID, nil, nil)
+	c.JSON(http.StatusOK, gin.H{
+		"event":             event,
+		"total_innovations": results.TotalInnovations,
+		"total_votes":       results.TotalVotes,
+		"total_voters":      results.TotalVoters,
+		"max_votes":         results.MaxVotes,
+		"innovations":       results.Innovations,
+		"group":             groupSlug,
+		"groups":            results.Groups,
+	})
+}
+
+// GetVoteSeries returns bucketed vote counts for the momentum chart
+func (h *AnalyticsHandler) GetVoteSeries(c *gin.Context) {
+	query, err := voteSeriesQueryFromRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	// Calculate percentages
-	for _, stats := range innovationsWithStats {
-		if maxVotes > 0 {
-			stats.VotePercentage = (float64(stats.VoteCount) / float64(maxVotes)) * 100
-		} else {
-			stats.VotePercentage = 0
+	event, err := h.service.GetEvent(c.Request.Context(), eventSlugFromRequest(c))
+	if err != nil {
+		if errors.Is(err, domain.ErrEventNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+			return
 		}
+		h.logger.ErrorContext(c.Request.Context(), "failed to get event", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load vote series"})
+		return
 	}
 
-	// Get total unique voters
-	totalVoters, err := h.service.GetTotalVoters(c.Request.Context())
+	series, err := h.service.GetVoteSeries(c.Request.Context(), event, query)
 	if err != nil {
-		h.logger.ErrorContext(c.Request.Context(), "failed to get total voters", "error", err)
-		totalVoters = 0
+		switch {
+		case errors.Is(err, domain.ErrInvalidInput):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrGroupNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+		case errors.Is(err, domain.ErrInnovationNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Innovation not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load vote series"})
+		}
+		return
 	}
 
+	h.audit.Record(c.Request.Context(), domain.AuditResultsViewed, "event", event.ID, nil, nil)
 	c.JSON(http.StatusOK, gin.H{
-		"total_innovations": len(innovations),
-		"total_votes":       totalVotes,
-		"total_voters":      totalVoters,
-		"max_votes":         maxVotes,
-		"innovations":       innovationsWithStats,
+		"event":  event,
+		"series": series,
 	})
 }
+
+// StreamResults pushes vote updates of the event as Server-Sent Events. A
+// "vote" event carries a domain.VoteUpdate; a "dropped" event means the client
+// fell behind and should reload the full results before reconnecting.
+func (h *AnalyticsHandler) StreamResults(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	event, err := h.service.GetEvent(ctx, eventSlugFromRequest(c))
+	if err != nil {
+		if errors.Is(err, domain.ErrEventNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+			return
+		}
+		h.logger.ErrorContext(ctx, "failed to get event", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open live results"})
+		return
+	}
+
+	sub := h.live.Subscribe(event.ID)
+	defer h.live.Unsubscribe(sub)
+
+	clearWriteDeadline(c, h.logger)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	h.audit.Record(ctx, domain.AuditResultsViewed, "event", event.ID, nil, nil)
+	c.SSEvent("ready", gin.H{"event_id": event.ID})
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(liveHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case update, ok := <-sub.Updates():
+			if !ok {
+				if sub.Dropped() {
+					c.SSEvent("dropped", gin.H{"event_id": event.ID})
+					c.Writer.Flush()
+				}
+				return
+			}
+			c.SSEvent("vote", update)
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			if _, err := c.Writer.WriteString(": ping\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// voteSeriesQueryFromRequest reads the series filters; since and until are
+// RFC 3339 times and bucket is a Go duration such as 5m or 1h
+func voteSeriesQueryFromRequest(c *gin.Context) (domain.VoteSeriesQuery, error) {
+	query := domain.VoteSeriesQuery{
+		GroupSlug:    c.Query("group"),
+		InnovationID: c.Query("innovation"),
+	}
+
+	for _, param := range []struct {
+		name string
+		dst  *time.Time
+	}{
+		{"since", &query.Since},
+		{"until", &query.Until},
+	} {
+		if value := c.Query(param.name); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return query, fmt.Errorf("%s must be an RFC 3339 time", param.name)
+			}
+			*param.dst = t
+		}
+	}
+
+	if value := c.Query("bucket"); value != "" {
+		bucket, err := time.ParseDuration(value)
+		if err != nil || bucket <= 0 {
+			return query, fmt.Errorf("bucket must be a duration such as 5m or 1h")
+		}
+		query.Bucket = bucket
+	}
+
+	return query, nil
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"voteweb/internal/domain"
+)
+
+// VoterSessionCookieName holds the signed token of a verified voter contact
+const VoterSessionCookieName = "voter_session"
+
+// VerificationHandler serves the one-time code API for events that verify voters
+type VerificationHandler struct {
+	service       domain.VoteService
+	verification  domain.VerificationService
+	secureCookies bool
+	logger        *slog.Logger
+}
+
+func NewVerificationHandler(service domain.VoteService, verification domain.VerificationService, secureCookies bool, logger *slog.Logger) *VerificationHandler {
+	return &VerificationHandler{
+		service:       service,
+		verification:  verification,
+		secureCookies: secureCookies,
+		logger:        logger,
+	}
+}
+
+type requestCodeInput struct {
+	Contact string `json:"contact"`
+}
+
+type confirmCodeInput struct {
+	Contact string `json:"contact"`
+	Code    string `json:"code"`
+}
+
+// RequestCode sends a one-time code to the voter's email or phone
+func (h *VerificationHandler) RequestCode(c *gin.Context) {
+	event, ok := h.event(c)
+	if !ok {
+		return
+	}
+
+	var input requestCodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	request, err := h.verification.RequestCode(c.Request.Context(), event, input.Contact)
+	if err != nil {
+		h.verificationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"channel":     request.Channel,
+		"destination": request.Destination,
+		"expires_at":  request.ExpiresAt,
+		"resend_at":   request.ResendAt,
+	})
+}
+
+// ConfirmCode checks the code and stores the verified contact in a cookie
+func (h *VerificationHandler) ConfirmCode(c *gin.Context) {
+	event, ok := h.event(c)
+	if !ok {
+		return
+	}
+
+	var input confirmCodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	session, cookie, err := h.verification.ConfirmCode(c.Request.Context(), event, input.Contact, input.Code)
+	if err != nil {
+		h.verificationError(c, err)
+		return
+	}
+
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     VoterSessionCookieName,
+		Value:    cookie,
+		Path:     "/",
+		Expires:  session.ExpiresAt,
+		MaxAge:   int(time.Until(session.ExpiresAt).Seconds()),
+		Secure:   h.secureCookies,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"expires_at": session.ExpiresAt,
+	})
+}
+
+func (h *VerificationHandler) event(c *gin.Context) (*domain.Event, bool) {
+	event, err := h.service.GetEvent(c.Request.Context(), eventSlugFromRequest(c))
+	if err != nil {
+		if errors.Is(err, domain.ErrEventNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+			return nil, false
+		}
+		h.logger.ErrorContext(c.Request.Context(), "failed to get event", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify voter"})
+		return nil, false
+	}
+	return event, true
+}
+
+func (h *VerificationHandler) verificationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid_contact",
+			"message": "Masukkan alamat email atau nomor HP yang valid.",
+			"detail":  err.Error(),
+		})
+	case errors.Is(err, domain.ErrCodeRequestLimited):
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":   "code_request_limited",
+			"message": "Kode baru saja dikirim. Tunggu sebentar sebelum meminta kode lagi.",
+		})
+	case errors.Is(err, domain.ErrInvalidCode):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "invalid_code",
+			"message": "Kode salah atau sudah kedaluwarsa.",
+		})
+	case errors.Is(err, domain.ErrTooManyAttempts):
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":   "too_many_attempts",
+			"message": "Terlalu banyak percobaan. Silakan minta kode baru.",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "verification_failed",
+			"message": "Gagal memproses verifikasi. Silakan coba lagi.",
+		})
+	}
+}
+
+// voterContact returns the verified contact of the request for events that
+// verify voters; nil when the event does not or the voter has not verified
+func voterContact(c *gin.Context, verification domain.VerificationService, event *domain.Event, logger *slog.Logger) []byte {
+	if event.VoterVerification != domain.VoterVerificationOTP {
+		return nil
+	}
+	cookie, err := c.Cookie(VoterSessionCookieName)
+	if err != nil {
+		return nil
+	}
+
+	session, err := verification.Voter(c.Request.Context(), event, cookie)
+	if err != nil {
+		if !errors.Is(err, domain.ErrVerificationRequired) {
+			logger.ErrorContext(c.Request.Context(), "failed to load voter session",
+				"event_id", event.ID,
+				"error", err)
+		}
+		return nil
+	}
+	return session.ContactHash
+}
+
+// verifyURL returns the base of the event's verification API
+func verifyURL(event *domain.Event) string {
+	return "/api/events/" + event.Slug + "/verify"
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"log/slog"
 	"net/http"
 
@@ -10,28 +12,44 @@ import (
 )
 
 type VoteHandler struct {
-	service domain.VoteService
-	logger  *slog.Logger
+	service      domain.VoteService
+	verification domain.VerificationService
+	challenges   domain.ChallengeService
+	logger       *slog.Logger
 }
 
-func NewVoteHandler(service domain.VoteService, logger *slog.Logger) *VoteHandler {
+func NewVoteHandler(service domain.VoteService, verification domain.VerificationService, challenges domain.ChallengeService, logger *slog.Logger) *VoteHandler {
 	return &VoteHandler{
-		service: service,
-		logger:  logger,
+		service:      service,
+		verification: verification,
+		challenges:   challenges,
+		logger:       logger,
 	}
 }
 
+// voteInput is the optional vote body, sent when answering a challenge
+type voteInput struct {
+	ChallengeToken  string `json:"challenge_token"`
+	ChallengeAnswer string `json:"challenge_answer"`
+}
+
 func (h *VoteHandler) SubmitVote(c *gin.Context) {
-	// Voting system is closed
-	c.JSON(http.StatusForbidden, gin.H{
-		"error":   "voting_closed",
-		"message": "Sistem voting telah ditutup. Terima kasih atas partisipasi Anda.",
-	})
-	return
+	event, err := h.service.GetEvent(c.Request.Context(), eventSlugFromRequest(c))
+	if err != nil {
+		if errors.Is(err, domain.ErrEventNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Event not found",
+			})
+			return
+		}
+		h.logger.ErrorContext(c.Request.Context(), "failed to get event",
+			"error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to submit vote",
+		})
+		return
+	}
 
-	// Code below is disabled while voting is closed
-	// Uncomment to re-enable voting functionality
-	/*
 	groupSlug := c.Param("group")
 	slug := c.Param("slug")
 
@@ -42,14 +60,72 @@ func (h *VoteHandler) SubmitVote(c *gin.Context) {
 	}
 
 	req := domain.VoteRequest{
+		EventID:   event.ID,
 		GroupSlug: groupSlug,
 		Slug:      slug,
 		ClientIP:  clientIP.(string),
 		UserAgent: c.GetHeader("User-Agent"),
+		// Set only for events that verify voters with one-time codes
+		ContactHash: voterContact(c, h.verification, event, h.logger),
+		// Set by the VerifyVoterToken middleware when the cookie is valid
+		VoterToken: c.GetString("voter_token"),
+	}
+
+	var input voteInput
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	// Suspicious requests must solve a challenge first
+	challenge, hold, err := h.challenges.Check(c.Request.Context(), event, req, domain.ChallengeSolution{
+		Token:  input.ChallengeToken,
+		Answer: input.ChallengeAnswer,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrChallengeRequired):
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":     "challenge_required",
+				"message":   "Selesaikan verifikasi keamanan untuk memberikan vote.",
+				"challenge": challenge,
+			})
+		case errors.Is(err, domain.ErrChallengeFailed):
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":     "challenge_failed",
+				"message":   "Verifikasi keamanan gagal. Silakan coba lagi.",
+				"challenge": challenge,
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to submit vote",
+			})
+		}
+		return
 	}
 
 	result, err := h.service.SubmitVote(c.Request.Context(), req)
+	if err != nil || !result.Success {
+		// A vote that did not count leaves the solved challenge usable
+		hold.Release(c.Request.Context())
+	}
 	if err != nil {
+		if errors.Is(err, domain.ErrVotingClosed) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "voting_closed",
+				"message": "Sistem voting telah ditutup. Terima kasih atas partisipasi Anda.",
+			})
+			return
+		}
+
+		if errors.Is(err, domain.ErrVerificationRequired) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error":   "verification_required",
+				"message": "Verifikasi email atau nomor HP Anda terlebih dahulu untuk memberikan vote.",
+			})
+			return
+		}
+
 		if errors.Is(err, domain.ErrInnovationNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{
 				"error": "Innovation not found",
@@ -58,6 +134,7 @@ func (h *VoteHandler) SubmitVote(c *gin.Context) {
 		}
 
 		h.logger.ErrorContext(c.Request.Context(), "failed to submit vote",
+			"event_id", event.ID,
 			"group_slug", groupSlug,
 			"slug", slug,
 			"error", err)
@@ -84,8 +161,4 @@ func (h *VoteHandler) SubmitVote(c *gin.Context) {
 		"message":    result.Message,
 		"vote_count": result.VoteCount,
 	})
-	*/
 }
-
-
-
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"voteweb/internal/domain"
+)
+
+// writeError maps domain errors to JSON responses for the admin APIs. Errors
+// without a mapping are logged and answered with message.
+func writeError(c *gin.Context, logger *slog.Logger, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrSlugTaken),
+		errors.Is(err, domain.ErrUsernameTaken),
+		errors.Is(err, domain.ErrLastSuperadmin):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrEventNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+	case errors.Is(err, domain.ErrInnovationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Innovation not found"})
+	case errors.Is(err, domain.ErrAdminUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Admin user not found"})
+	default:
+		logger.ErrorContext(c.Request.Context(), message, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+
+	"voteweb/internal/domain"
+)
+
+// eventSlugFromRequest returns the event slug from the :event route parameter
+// or the ?event= query string. An empty slug selects the default event.
+func eventSlugFromRequest(c *gin.Context) string {
+	if slug := c.Param("event"); slug != "" {
+		return slug
+	}
+	return c.Query("event")
+}
+
+// resolveEvent looks up the event from the request and writes the error
+// response if it fails
+func resolveEvent(c *gin.Context, service domain.VoteService, logger *slog.Logger) (*domain.Event, bool) {
+	event, err := service.GetEvent(c.Request.Context(), eventSlugFromRequest(c))
+	if err != nil {
+		writeError(c, logger, err, "Failed to load event")
+		return nil, false
+	}
+	return event, true
+}
+
+// eventBasePath returns the URL prefix for an event's public pages. The
+// default event keeps the legacy un-prefixed routes.
+func eventBasePath(event *domain.Event) string {
+	if event.IsDefault {
+		return ""
+	}
+	return "/e/" + event.Slug
+}
+
+// voteURL returns the vote API endpoint for an innovation
+func voteURL(event *domain.Event, innovation *domain.Innovation) string {
+	if event.IsDefault {
+		return "/api/vote/" + innovation.GroupSlug + "/" + innovation.Slug
+	}
+	return "/api/events/" + event.Slug + "/vote/" + innovation.GroupSlug + "/" + innovation.Slug
+}
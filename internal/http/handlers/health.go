@@ -21,6 +21,15 @@ func (h *HealthHandler) HealthCheck(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
 	defer cancel()
 
+	// DEMO_MODE runs without a database
+	if h.pool == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"status":   "ok",
+			"database": "in-memory",
+		})
+		return
+	}
+
 	// Check database connection
 	err := h.pool.Ping(ctx)
 	if err != nil {
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 
@@ -21,8 +22,41 @@ func NewListHandler(service domain.VoteService, logger *slog.Logger) *ListHandle
 	}
 }
 
+// GroupSection is a group with its innovations, in display order
+type GroupSection struct {
+	Group       *domain.Group
+	Innovations []*domain.Innovation
+}
+
 func (h *ListHandler) ShowList(c *gin.Context) {
-	innovations, err := h.service.ListInnovations(c.Request.Context())
+	event, err := h.service.GetEvent(c.Request.Context(), eventSlugFromRequest(c))
+	if err != nil {
+		if errors.Is(err, domain.ErrEventNotFound) {
+			c.HTML(http.StatusNotFound, "error.tmpl.html", gin.H{
+				"Title":   "Event Not Found",
+				"Message": "The event you're looking for does not exist.",
+			})
+			return
+		}
+		h.logger.ErrorContext(c.Request.Context(), "failed to get event", "error", err)
+		c.HTML(http.StatusInternalServerError, "error.tmpl.html", gin.H{
+			"Title":   "Error",
+			"Message": "An error occurred while loading innovations.",
+		})
+		return
+	}
+
+	groups, err := h.service.ListGroups(c.Request.Context(), event.ID)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "failed to list groups", "error", err)
+		c.HTML(http.StatusInternalServerError, "error.tmpl.html", gin.H{
+			"Title":   "Error",
+			"Message": "An error occurred while loading innovations.",
+		})
+		return
+	}
+
+	innovations, err := h.service.ListInnovations(c.Request.Context(), event.ID)
 	if err != nil {
 		h.logger.ErrorContext(c.Request.Context(), "failed to list innovations", "error", err)
 		c.HTML(http.StatusInternalServerError, "error.tmpl.html", gin.H{
@@ -32,18 +66,28 @@ func (h *ListHandler) ShowList(c *gin.Context) {
 		return
 	}
 
-	// Group innovations by group_slug
+	// Group innovations by group_slug, keeping the event's group order
 	grouped := make(map[string][]*domain.Innovation)
 	for _, innovation := range innovations {
-		if grouped[innovation.GroupSlug] == nil {
-			grouped[innovation.GroupSlug] = []*domain.Innovation{}
-		}
 		grouped[innovation.GroupSlug] = append(grouped[innovation.GroupSlug], innovation)
 	}
 
+	var sections []*GroupSection
+	for _, group := range groups {
+		if len(grouped[group.Slug]) == 0 {
+			continue
+		}
+		sections = append(sections, &GroupSection{
+			Group:       group,
+			Innovations: grouped[group.Slug],
+		})
+	}
+
 	c.HTML(http.StatusOK, "list.tmpl.html", gin.H{
 		"Title":       "Innovation Voting System",
+		"Event":       event,
+		"BasePath":    eventBasePath(event),
 		"Innovations": innovations,
-		"Grouped":     grouped,
+		"Sections":    sections,
 	})
 }
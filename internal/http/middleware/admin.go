@@ -1,42 +1,135 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"voteweb/internal/domain"
 )
 
-const AdminHeaderKey = "X-ADMIN-CODE"
+const (
+	AdminSessionCookieName = "admin_session"
+	adminSessionPath       = "/admin"
+)
 
-// AdminAuth validates the X-ADMIN-CODE header for admin access
-func AdminAuth(validCode string) gin.HandlerFunc {
+// AdminAuth protects admin API routes. It requires the admin session cookie
+// and answers 401 JSON otherwise.
+func AdminAuth(auth domain.AdminAuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		providedCode := c.GetHeader(AdminHeaderKey)
-
-		if providedCode == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "X-ADMIN-CODE header is required",
-			})
-			c.Abort()
+		if !authenticateAdmin(c, auth, true) {
 			return
 		}
+		c.Next()
+	}
+}
 
-		// Trim whitespace for both codes
-		providedCode = strings.TrimSpace(providedCode)
-		validCode = strings.TrimSpace(validCode)
+// AdminPage protects admin HTML pages and redirects to the login page when
+// there is no valid session
+func AdminPage(auth domain.AdminAuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !authenticateAdmin(c, auth, false) {
+			return
+		}
+		c.Next()
+	}
+}
 
-		if providedCode != validCode {
-			c.JSON(http.StatusForbidden, gin.H{
-				"error": "Invalid admin code",
-			})
+// RequireRole rejects admins whose role is below the given one. It must run
+// after AdminAuth or AdminPage.
+func RequireRole(role domain.AdminRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		session := GetAdminSession(c)
+		if session == nil || !session.Role.Allows(role) {
+			if isAPIRequest(c) {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+					"error": "Insufficient role",
+				})
+				return
+			}
+			c.String(http.StatusForbidden, "Akses ditolak: akun Anda tidak memiliki izin untuk halaman ini")
 			c.Abort()
 			return
 		}
+		c.Next()
+	}
+}
 
-		// Set admin flag in context for use in handlers
-		c.Set("is_admin", true)
+// GetAdminSession returns the session set by AdminAuth or AdminPage, if any
+func GetAdminSession(c *gin.Context) *domain.AdminSession {
+	if session, exists := c.Get("admin_session"); exists {
+		if s, ok := session.(*domain.AdminSession); ok {
+			return s
+		}
+	}
+	return nil
+}
 
-		c.Next()
+// SetAdminSessionCookie stores the signed session token in an HttpOnly cookie
+func SetAdminSessionCookie(c *gin.Context, value string, expires time.Time, secure bool) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     AdminSessionCookieName,
+		Value:    value,
+		Path:     adminSessionPath,
+		Expires:  expires,
+		MaxAge:   int(time.Until(expires).Seconds()),
+		Secure:   secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// ClearAdminSessionCookie removes the session cookie from the browser
+func ClearAdminSessionCookie(c *gin.Context, secure bool) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     AdminSessionCookieName,
+		Value:    "",
+		Path:     adminSessionPath,
+		MaxAge:   -1,
+		Secure:   secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// authenticateAdmin checks the session cookie. It writes the error response
+// (JSON for APIs, a login redirect for pages) and aborts when it fails.
+func authenticateAdmin(c *gin.Context, auth domain.AdminAuthService, api bool) bool {
+	if cookie, err := c.Cookie(AdminSessionCookieName); err == nil && cookie != "" {
+		session, err := auth.Authenticate(c.Request.Context(), cookie)
+		if err == nil {
+			c.Set("admin_session", session)
+			c.Set("is_admin", true)
+			// Attribute audit events written while serving this request
+			ctx := domain.WithAuditActor(c.Request.Context(), domain.AuditActor{ID: session.UserID, Name: session.Username})
+			c.Request = c.Request.WithContext(ctx)
+			return true
+		}
+		if !errors.Is(err, domain.ErrSessionNotFound) {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error": "Failed to verify session",
+			})
+			return false
+		}
+	}
+
+	if api {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"error": "Admin login required",
+		})
+		return false
 	}
+
+	c.Redirect(http.StatusFound, "/admin/login?next="+url.QueryEscape(c.Request.URL.RequestURI()))
+	c.Abort()
+	return false
+}
+
+// isAPIRequest reports whether the request targets the admin JSON API
+func isAPIRequest(c *gin.Context) bool {
+	return strings.HasPrefix(c.Request.URL.Path, "/admin/api/")
 }
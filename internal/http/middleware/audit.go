@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"voteweb/internal/domain"
+)
+
+// AuditContext copies the request ID and client IP into the request context so
+// services can attach them to audit events. It must run after RequestID and
+// ProxiedIP.
+func AuditContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := domain.WithRequestInfo(c.Request.Context(), domain.RequestInfo{
+			RequestID: c.GetString("request_id"),
+			ClientIP:  c.GetString("client_ip"),
+		})
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
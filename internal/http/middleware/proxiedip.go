@@ -2,42 +2,48 @@ package middleware
 
 import (
 	"net"
-	"strings"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 
 	"voteweb/internal/util"
 )
 
-// ProxiedIP extracts the real client IP from X-Forwarded-For header if behind trusted proxy
-func ProxiedIP(trustProxy bool, allowedCIDRs []*net.IPNet) gin.HandlerFunc {
+// Client IP headers understood by ProxiedIP; any other header name is read as
+// a single address set by the trusted proxy, such as CF-Connecting-IP
+const (
+	HeaderXForwardedFor = "X-Forwarded-For"
+	HeaderForwarded     = "Forwarded"
+)
+
+// ProxiedIP resolves the real client IP when requests arrive through trusted
+// proxies. Only the configured header is read, and only from a peer inside
+// allowedCIDRs; otherwise the connection's address is used.
+func ProxiedIP(trustProxy bool, allowedCIDRs []*net.IPNet, header string) gin.HandlerFunc {
+	header = http.CanonicalHeaderKey(header)
+
 	return func(c *gin.Context) {
-		clientIP := c.ClientIP()
-
-		if trustProxy {
-			// Get X-Forwarded-For header
-			xff := c.GetHeader("X-Forwarded-For")
-			if xff != "" {
-				// Parse the first IP from X-Forwarded-For
-				ips := strings.Split(xff, ",")
-				if len(ips) > 0 {
-					firstIP := strings.TrimSpace(ips[0])
-
-					// Verify the request came from an allowed proxy
-					remoteIP := util.NormalizeIP(c.Request.RemoteAddr)
-					if util.IsIPInCIDRs(remoteIP, allowedCIDRs) {
-						clientIP = firstIP
+		clientIP := util.NormalizeIP(c.Request.RemoteAddr)
+
+		if trustProxy && util.IsIPInCIDRs(clientIP, allowedCIDRs) {
+			values := c.Request.Header.Values(header)
+			switch header {
+			case HeaderXForwardedFor:
+				clientIP = util.ClientIPFromHops(util.ParseXForwardedFor(values...), clientIP, allowedCIDRs)
+			case HeaderForwarded:
+				clientIP = util.ClientIPFromHops(util.ParseForwarded(values...), clientIP, allowedCIDRs)
+			default:
+				// The proxy overwrites this header, so its value is taken as is
+				if len(values) > 0 {
+					if ip := util.NormalizeIP(values[0]); net.ParseIP(ip) != nil {
+						clientIP = ip
 					}
 				}
 			}
 		}
 
-		// Normalize and store the client IP
-		clientIP = util.NormalizeIP(clientIP)
 		c.Set("client_ip", clientIP)
 
 		c.Next()
 	}
 }
-
-
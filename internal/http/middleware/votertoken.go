@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"voteweb/internal/util"
+)
+
+// VoterTokenCookieName holds the signed anonymous token that tells voters
+// behind one IP apart
+const VoterTokenCookieName = "voter_token"
+
+const voterTokenLifetime = 365 * 24 * time.Hour
+
+// VoterToken gives every visitor a signed, HttpOnly voter-token cookie and
+// stores the token in the context as "voter_token". A cookie signed with a
+// previous key is re-signed with the current one; a missing or forged cookie
+// is replaced with a new token. It belongs on page GETs only: see
+// VerifyVoterToken for the vote API.
+func VoterToken(signer *util.TokenSigner, secure bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := verifyVoterToken(c, signer, secure)
+		if token == "" {
+			b := make([]byte, 32)
+			if _, err := rand.Read(b); err != nil {
+				// Without a token the vote is deduplicated by IP
+				c.Next()
+				return
+			}
+			token = base64.RawURLEncoding.EncodeToString(b)
+			setVoterToken(c, signer.Sign(token), secure)
+		}
+
+		c.Set("voter_token", token)
+		c.Next()
+	}
+}
+
+// VerifyVoterToken stores the token of a valid voter-token cookie in the
+// context as "voter_token" without handing out new ones. A token minted in
+// the request that uses it would let a client that drops cookies vote once
+// per request, so votes sent without a cookie are deduplicated by IP.
+func VerifyVoterToken(signer *util.TokenSigner, secure bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token := verifyVoterToken(c, signer, secure); token != "" {
+			c.Set("voter_token", token)
+		}
+		c.Next()
+	}
+}
+
+// verifyVoterToken returns the token of the request's voter-token cookie, or
+// "" when it is missing or forged. A cookie signed with a previous key is
+// re-signed with the current one.
+func verifyVoterToken(c *gin.Context, signer *util.TokenSigner, secure bool) string {
+	cookie, err := c.Cookie(VoterTokenCookieName)
+	if err != nil {
+		return ""
+	}
+	token, ok := signer.Verify(cookie)
+	if !ok {
+		return ""
+	}
+	if !signer.IsCurrent(cookie) {
+		setVoterToken(c, signer.Sign(token), secure)
+	}
+	return token
+}
+
+func setVoterToken(c *gin.Context, value string, secure bool) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     VoterTokenCookieName,
+		Value:    value,
+		Path:     "/",
+		Expires:  time.Now().Add(voterTokenLifetime),
+		MaxAge:   int(voterTokenLifetime.Seconds()),
+		Secure:   secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
@@ -6,21 +6,28 @@ import (
 	"path/filepath"
 
 	"github.com/gin-gonic/gin"
-	"github.com/jackc/pgx/v5/pgxpool"
 
+	"voteweb/internal/app"
 	"voteweb/internal/config"
 	"voteweb/internal/domain"
 	"voteweb/internal/http/handlers"
 	"voteweb/internal/http/middleware"
+	"voteweb/internal/util"
 )
 
 // SetupRouter configures and returns the Gin router
-func SetupRouter(cfg *config.Config, pool *pgxpool.Pool, service domain.VoteService, logger *slog.Logger) *gin.Engine {
+func SetupRouter(a *app.App) *gin.Engine {
+	cfg, pool, service, logger := a.Config, a.Pool, a.Service, a.Logger
+
 	// Set Gin mode
 	gin.SetMode(cfg.GinMode)
 
 	router := gin.New()
 
+	// ProxiedIP decides which forwarding headers to believe; gin's own
+	// ClientIP must not read them
+	_ = router.SetTrustedProxies(nil)
+
 	// Load templates
 	router.SetFuncMap(template.FuncMap{
 		"add": func(a, b int64) int64 { return a + b },
@@ -56,7 +63,8 @@ func SetupRouter(cfg *config.Config, pool *pgxpool.Pool, service domain.VoteServ
 	router.Use(middleware.RequestID())
 	router.Use(middleware.Recover(logger))
 	router.Use(middleware.SecurityHeaders())
-	router.Use(middleware.ProxiedIP(cfg.TrustProxy, cfg.AllowedProxyCIDRs))
+	router.Use(middleware.ProxiedIP(cfg.TrustProxy, cfg.AllowedProxyCIDRs, cfg.ClientIPHeader))
+	router.Use(middleware.AuditContext())
 	router.Use(middleware.CSRF())
 
 	// Logging middleware
@@ -74,43 +82,123 @@ func SetupRouter(cfg *config.Config, pool *pgxpool.Pool, service domain.VoteServ
 	healthHandler := handlers.NewHealthHandler(pool)
 	router.GET("/healthz", healthHandler.HealthCheck)
 
-	// List handler
+	// Public pages hand out voter-token cookies; the vote API only accepts
+	// the ones handed out earlier
+	voterToken := middleware.VoterToken(a.VoterTokens, cfg.SecureCookies)
+	verifyVoterToken := middleware.VerifyVoterToken(a.VoterTokens, cfg.SecureCookies)
+
+	// List handler (default event at /, other events at /e/:event)
 	listHandler := handlers.NewListHandler(service, logger)
-	router.GET("/", listHandler.ShowList)
+	router.GET("/", voterToken, listHandler.ShowList)
+	router.GET("/e/:event", voterToken, listHandler.ShowList)
 
 	// Admin login page (public, no auth required)
-	adminHandler := handlers.NewAdminHandler()
+	adminHandler := handlers.NewAdminHandler(a.Auth, cfg.SecureCookies, logger)
 	router.GET("/admin/login", adminHandler.ShowLogin)
 
-	// Admin dashboard viewer (client-side, no server-side auth required)
-	router.GET("/admin/dashboard", adminHandler.ShowDashboardViewer)
-
-	// Admin protected routes - protected with X-ADMIN-CODE header (must be before /:group/:slug)
-	if cfg.AdminCode != "" {
-		analyticsHandler := handlers.NewAnalyticsHandler(service, logger)
-		authMiddleware := middleware.AdminAuth(cfg.AdminCode)
-		
-		// Register protected routes directly with auth middleware
-		router.GET("/admin/analytics", authMiddleware, analyticsHandler.ShowAnalytics)
-		router.GET("/admin/api/data", authMiddleware, analyticsHandler.GetAnalyticsData)
-
-		logger.Info("Admin routes enabled",
-			"login_path", "/admin/login",
-			"dashboard_path", "/admin/dashboard",
-			"analytics_path", "/admin/analytics",
-			"api_path", "/admin/api/data",
-			"admin_code_length", len(cfg.AdminCode))
-	} else {
-		logger.Warn("Admin routes disabled - AdminCode not configured")
-	}
-
-	// API handlers
-	voteHandler := handlers.NewVoteHandler(service, logger)
-	router.POST("/api/vote/:group/:slug", voteHandler.SubmitVote)
-
-	// Page handler (catch-all, must be last)
-	pageHandler := handlers.NewPageHandler(service, logger)
-	router.GET("/:group/:slug", pageHandler.ShowInnovation)
+	// Admin protected routes - session cookie from /admin/login (must be before /:group/:slug)
+	analyticsHandler := handlers.NewAnalyticsHandler(service, a.Audit, a.Live, logger)
+	adminInnovationHandler := handlers.NewAdminInnovationHandler(service, a.Innovations, logger)
+	adminVotingHandler := handlers.NewAdminVotingHandler(service, logger)
+	adminUserHandler := handlers.NewAdminUserHandler(a.Users, logger)
+	adminAuditHandler := handlers.NewAdminAuditHandler(a.Audit, logger)
+	adminExportHandler := handlers.NewAdminExportHandler(service, a.Audit, logger)
+	adminFraudHandler := handlers.NewAdminFraudHandler(service, a.Fraud, logger)
+	pageAuth := middleware.AdminPage(a.Auth)
+	authMiddleware := middleware.AdminAuth(a.Auth)
+	operator := middleware.RequireRole(domain.AdminRoleOperator)
+	superadmin := middleware.RequireRole(domain.AdminRoleSuperadmin)
+
+	// Vote API throttling; operators can read the rejection counters
+	voteRateLimit := newVoteRateLimit(cfg, logger)
+	rateLimitHandler := handlers.NewRateLimitHandler(voteRateLimit)
+
+	router.POST("/admin/login", adminHandler.Login)
+	router.POST("/admin/logout", adminHandler.Logout)
+
+	// Pages redirect to the login page without a session
+	router.GET("/admin/dashboard", pageAuth, adminHandler.ShowDashboardViewer)
+	router.GET("/admin/analytics", pageAuth, analyticsHandler.ShowAnalytics)
+	router.GET("/admin/innovations", pageAuth, operator, adminInnovationHandler.ShowInnovations)
+	router.GET("/admin/fraud", pageAuth, operator, adminFraudHandler.ShowFraud)
+	router.GET("/admin/users", pageAuth, superadmin, adminUserHandler.ShowUsers)
+	router.GET("/admin/audit", pageAuth, superadmin, adminAuditHandler.ShowAudit)
+
+	// Viewer API
+	router.GET("/admin/api/data", authMiddleware, analyticsHandler.GetAnalyticsData)
+	router.GET("/admin/api/timeseries", authMiddleware, analyticsHandler.GetVoteSeries)
+	router.GET("/admin/api/live", authMiddleware, analyticsHandler.StreamResults)
+	router.GET("/admin/api/export/totals", authMiddleware, adminExportHandler.ExportTotals)
+	router.GET("/admin/api/export/rankings", authMiddleware, adminExportHandler.ExportRankings)
+
+	// Operator API: voting state and innovation management (event chosen with ?event=<slug>)
+	votingAPI := router.Group("/admin/api/voting", authMiddleware, operator)
+	votingAPI.GET("", adminVotingHandler.GetVoting)
+	votingAPI.POST("/open", adminVotingHandler.OpenVoting)
+	votingAPI.POST("/close", adminVotingHandler.CloseVoting)
+
+	router.GET("/admin/api/rate-limit", authMiddleware, operator, rateLimitHandler.GetStats)
+
+	// Raw votes carry user agents and IP hash prefixes
+	router.GET("/admin/api/export/votes", authMiddleware, operator, adminExportHandler.ExportVotes)
+
+	// Suspicious votes review queue
+	fraudAPI := router.Group("/admin/api/fraud", authMiddleware, operator)
+	fraudAPI.GET("/votes", adminFraudHandler.ListVotes)
+	fraudAPI.POST("/analyse", adminFraudHandler.Analyse)
+	fraudAPI.POST("/invalidate", adminFraudHandler.InvalidateVotes)
+	fraudAPI.POST("/restore", adminFraudHandler.RestoreVotes)
+
+	adminAPI := router.Group("/admin/api/innovations", authMiddleware, operator)
+	adminAPI.GET("", adminInnovationHandler.ListInnovations)
+	adminAPI.POST("", adminInnovationHandler.CreateInnovation)
+	adminAPI.POST("/reorder", adminInnovationHandler.ReorderInnovations)
+	adminAPI.GET("/:id", adminInnovationHandler.GetInnovation)
+	adminAPI.PUT("/:id", adminInnovationHandler.UpdateInnovation)
+	adminAPI.POST("/:id/archive", adminInnovationHandler.ArchiveInnovation)
+	adminAPI.POST("/:id/restore", adminInnovationHandler.RestoreInnovation)
+
+	// Superadmin API: accounts, sessions and the audit log
+	userAPI := router.Group("/admin/api/users", authMiddleware, superadmin)
+	userAPI.GET("", adminUserHandler.ListUsers)
+	userAPI.POST("", adminUserHandler.CreateUser)
+	userAPI.PUT("/:id", adminUserHandler.UpdateUser)
+
+	sessionAPI := router.Group("/admin/api/sessions", authMiddleware, superadmin)
+	sessionAPI.GET("", adminHandler.ListSessions)
+	sessionAPI.POST("/:id/revoke", adminHandler.RevokeSession)
+
+	auditAPI := router.Group("/admin/api/audit", authMiddleware, superadmin)
+	auditAPI.GET("", adminAuditHandler.ListEvents)
+	auditAPI.GET("/export", adminAuditHandler.ExportEvents)
+
+	// API handlers (legacy route votes in the default event)
+	voteHandler := handlers.NewVoteHandler(service, a.Verification, a.Challenges, logger)
+	voteLimit := voteRateLimit.Handler()
+	router.POST("/api/vote/:group/:slug", voteLimit, verifyVoterToken, voteHandler.SubmitVote)
+	router.POST("/api/events/:event/vote/:group/:slug", voteLimit, verifyVoterToken, voteHandler.SubmitVote)
+
+	// One-time codes for events that verify voters; throttled like votes
+	verificationHandler := handlers.NewVerificationHandler(service, a.Verification, cfg.SecureCookies, logger)
+	router.POST("/api/events/:event/verify/request", voteLimit, verificationHandler.RequestCode)
+	router.POST("/api/events/:event/verify/confirm", voteLimit, verificationHandler.ConfirmCode)
+
+	// Page handler (catch-all, must be last; legacy route serves the default event)
+	pageHandler := handlers.NewPageHandler(service, a.Verification, logger)
+	router.GET("/e/:event/:group/:slug", voterToken, pageHandler.ShowInnovation)
+	router.GET("/:group/:slug", voterToken, pageHandler.ShowInnovation)
 
 	return router
 }
+
+// newVoteRateLimit builds the vote API limiter from the configured limits
+func newVoteRateLimit(cfg *config.Config, logger *slog.Logger) *middleware.RateLimit {
+	var perIP, perSubnet *util.RateLimiter
+	if cfg.VoteRateIP.Enabled() {
+		perIP = util.NewRateLimiter(cfg.VoteRateIP.PerMinute, cfg.VoteRateIP.Burst)
+	}
+	if cfg.VoteRateSubnet.Enabled() {
+		perSubnet = util.NewRateLimiter(cfg.VoteRateSubnet.PerMinute, cfg.VoteRateSubnet.Burst)
+	}
+	return middleware.NewRateLimit(perIP, perSubnet, logger)
+}
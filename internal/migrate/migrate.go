@@ -0,0 +1,408 @@
+// Package migrate applies the numbered SQL migrations and records them in
+// schema_migrations
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// lockID is the advisory lock key held while migrating, so replicas starting
+// together apply each migration once
+const lockID int64 = 0x766f74656d6967 // "votemig"
+
+// ErrNotBaselined is returned when the database has tables but no recorded
+// migrations, i.e. it was migrated by hand before the runner existed
+var ErrNotBaselined = errors.New("database has tables but no recorded migrations; run 'migrate baseline VERSION' with the last migration applied by hand")
+
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+?)(\.down)?\.sql$`)
+
+// Migration is one numbered schema change
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	// Down reverts the migration; empty when it cannot be reverted
+	Down string
+}
+
+// Status is a migration and whether it has been applied. Applied versions
+// without a file (applied by a newer binary) have an empty Name.
+type Status struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// Load reads NNNN_name.sql migrations and their optional NNNN_name.down.sql
+// reversals from files, sorted by version
+func Load(files fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	downs := make(map[int]string)
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migration %s: name must be NNNN_name.sql or NNNN_name.down.sql", entry.Name())
+		}
+		version, err := strconv.Atoi(match[1])
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s: version must be a positive number", entry.Name())
+		}
+
+		body, err := fs.ReadFile(files, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", entry.Name(), err)
+		}
+
+		if match[3] != "" {
+			if _, ok := downs[version]; ok {
+				return nil, fmt.Errorf("migration %s: version %d has two down files", entry.Name(), version)
+			}
+			downs[version] = string(body)
+			continue
+		}
+		if existing, ok := byVersion[version]; ok {
+			return nil, fmt.Errorf("migration %s: version %d is also used by %s", entry.Name(), version, existing.Name)
+		}
+		byVersion[version] = &Migration{Version: version, Name: match[2], Up: string(body)}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for version, down := range downs {
+		m, ok := byVersion[version]
+		if !ok {
+			return nil, fmt.Errorf("down migration %d has no up migration", version)
+		}
+		m.Down = down
+	}
+	for _, m := range byVersion {
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Migrator applies migrations to a database
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []Migration
+	logger     *slog.Logger
+}
+
+// New loads the migrations from files and creates a Migrator
+func New(pool *pgxpool.Pool, files fs.FS, logger *slog.Logger) (*Migrator, error) {
+	migrations, err := Load(files)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{
+		pool:       pool,
+		migrations: migrations,
+		logger:     logger,
+	}, nil
+}
+
+// Latest returns the highest known version, 0 without migrations
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up applies every pending migration and returns how many ran. Versions
+// applied by a newer binary are left in place.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	ran := 0
+	err := m.locked(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		target := m.Latest()
+		if versions := sortedVersions(applied); len(versions) > 0 && versions[len(versions)-1] > target {
+			target = versions[len(versions)-1]
+			m.logger.WarnContext(ctx, "database has migrations this binary does not know",
+				"latest_known", m.Latest(),
+				"latest_applied", target)
+		}
+		ran, err = m.migrate(ctx, conn, applied, target)
+		return err
+	})
+	return ran, err
+}
+
+// Down reverts the last steps applied migrations and returns how many ran
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	if steps <= 0 {
+		return 0, fmt.Errorf("steps must be positive")
+	}
+
+	ran := 0
+	err := m.locked(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		versions := sortedVersions(applied)
+		target := 0
+		if steps < len(versions) {
+			target = versions[len(versions)-1-steps]
+		}
+		ran, err = m.migrate(ctx, conn, applied, target)
+		return err
+	})
+	return ran, err
+}
+
+// To applies or reverts migrations until version is the last one applied,
+// and returns how many ran. Version 0 reverts everything.
+func (m *Migrator) To(ctx context.Context, version int) (int, error) {
+	if version != 0 && !m.known(version) {
+		return 0, fmt.Errorf("unknown migration version %d", version)
+	}
+
+	ran := 0
+	err := m.locked(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		ran, err = m.migrate(ctx, conn, applied, version)
+		return err
+	})
+	return ran, err
+}
+
+// Status lists every known migration and every applied version, by version
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.locked(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			status := Status{Version: migration.Version, Name: migration.Name}
+			if at, ok := applied[migration.Version]; ok {
+				status.AppliedAt = &at
+				delete(applied, migration.Version)
+			}
+			statuses = append(statuses, status)
+		}
+		for version, at := range applied {
+			statuses = append(statuses, Status{Version: version, AppliedAt: &at})
+		}
+		sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+		return nil
+	})
+	return statuses, err
+}
+
+// Baseline records the migrations up to version as applied without running
+// them, for databases migrated by hand with psql
+func (m *Migrator) Baseline(ctx context.Context, version int) (int, error) {
+	if !m.known(version) {
+		return 0, fmt.Errorf("unknown migration version %d", version)
+	}
+
+	recorded := 0
+	err := m.locked(ctx, func(conn *pgxpool.Conn) error {
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+			tag, err := conn.Exec(ctx, `
+				INSERT INTO schema_migrations (version, name) VALUES ($1, $2)
+				ON CONFLICT (version) DO NOTHING
+			`, migration.Version, migration.Name)
+			if err != nil {
+				return fmt.Errorf("record migration %d: %w", migration.Version, err)
+			}
+			recorded += int(tag.RowsAffected())
+		}
+		return nil
+	})
+	return recorded, err
+}
+
+// migrate runs the steps from applied to target on conn, which holds the lock
+func (m *Migrator) migrate(ctx context.Context, conn *pgxpool.Conn, applied map[int]time.Time, target int) (int, error) {
+	if len(applied) == 0 {
+		var hasTables bool
+		if err := conn.QueryRow(ctx, `SELECT to_regclass('innovations') IS NOT NULL`).Scan(&hasTables); err != nil {
+			return 0, fmt.Errorf("inspect schema: %w", err)
+		}
+		if hasTables {
+			return 0, ErrNotBaselined
+		}
+	}
+
+	up, down, err := plan(m.migrations, applied, target)
+	if err != nil {
+		return 0, err
+	}
+
+	ran := 0
+	for _, migration := range down {
+		if err := m.run(ctx, conn, migration, false); err != nil {
+			return ran, err
+		}
+		ran++
+	}
+	for _, migration := range up {
+		if err := m.run(ctx, conn, migration, true); err != nil {
+			return ran, err
+		}
+		ran++
+	}
+	return ran, nil
+}
+
+// run applies or reverts one migration and its schema_migrations row in a
+// single transaction
+func (m *Migrator) run(ctx context.Context, conn *pgxpool.Conn, migration Migration, up bool) error {
+	direction, script := "up", migration.Up
+	record := `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`
+	if !up {
+		direction, script = "down", migration.Down
+		record = `DELETE FROM schema_migrations WHERE version = $1 AND name = $2`
+	}
+
+	err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, script); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, record, migration.Version, migration.Name)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("migration %04d_%s %s: %w", migration.Version, migration.Name, direction, err)
+	}
+
+	m.logger.InfoContext(ctx, "migration applied",
+		"version", migration.Version,
+		"name", migration.Name,
+		"direction", direction)
+	return nil
+}
+
+// locked runs fn on a connection holding the migration lock, creating
+// schema_migrations first
+func (m *Migrator) locked(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, lockID); err != nil {
+		return fmt.Errorf("take migration lock: %w", err)
+	}
+	defer func() {
+		// The lock belongs to the session; a pooled connection that kept it
+		// would block every later run, so close it if unlocking fails
+		if _, err := conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, lockID); err != nil {
+			m.logger.Error("failed to release migration lock", "error", err)
+			conn.Conn().Close(context.Background())
+		}
+	}()
+
+	if _, err := conn.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+		  version INT PRIMARY KEY,
+		  name TEXT NOT NULL,
+		  applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)
+	`); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+func (m *Migrator) known(version int) bool {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+func appliedVersions(ctx context.Context, conn *pgxpool.Conn) (map[int]time.Time, error) {
+	rows, err := conn.Query(ctx, `SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, fmt.Errorf("scan schema_migrations: %w", err)
+		}
+		applied[version] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+	return applied, nil
+}
+
+// plan returns the migrations to apply (oldest first) and to revert (newest
+// first) so that target is the last applied version. It fails before anything
+// runs if a step cannot be reverted.
+func plan(migrations []Migration, applied map[int]time.Time, target int) (up, down []Migration, err error) {
+	byVersion := make(map[int]Migration, len(migrations))
+	for _, migration := range migrations {
+		byVersion[migration.Version] = migration
+		if _, ok := applied[migration.Version]; !ok && migration.Version <= target {
+			up = append(up, migration)
+		}
+	}
+
+	versions := sortedVersions(applied)
+	for i := len(versions) - 1; i >= 0 && versions[i] > target; i-- {
+		migration, ok := byVersion[versions[i]]
+		if !ok {
+			return nil, nil, fmt.Errorf("migration %d is applied but unknown to this binary", versions[i])
+		}
+		if migration.Down == "" {
+			return nil, nil, fmt.Errorf("migration %04d_%s cannot be reverted: it has no down file", migration.Version, migration.Name)
+		}
+		down = append(down, migration)
+	}
+	return up, down, nil
+}
+
+func sortedVersions(applied map[int]time.Time) []int {
+	versions := make([]int, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+	return versions
+}
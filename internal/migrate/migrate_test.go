@@ -0,0 +1,155 @@
+package migrate
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"voteweb/migrations"
+)
+
+func TestLoad(t *testing.T) {
+	files := fstest.MapFS{
+		"0002_add_votes.sql":      {Data: []byte("CREATE TABLE votes ();")},
+		"0001_init.sql":           {Data: []byte("CREATE TABLE innovations ();")},
+		"0002_add_votes.down.sql": {Data: []byte("DROP TABLE votes;")},
+		"migrations.go":           {Data: []byte("package migrations")},
+	}
+
+	got, err := Load(files)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(got) != 2 || got[0].Version != 1 || got[1].Version != 2 {
+		t.Fatalf("Load() = %+v, want versions 1 and 2 in order", got)
+	}
+	if got[0].Name != "init" || got[0].Down != "" {
+		t.Errorf("migration 1 = %+v, want init without a down script", got[0])
+	}
+	if got[1].Name != "add_votes" || got[1].Down != "DROP TABLE votes;" {
+		t.Errorf("migration 2 = %+v, want add_votes with its down script", got[1])
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+		want  string
+	}{
+		{
+			name: "duplicate version",
+			files: fstest.MapFS{
+				"0001_init.sql":  {},
+				"0001_other.sql": {},
+			},
+			want: "also used by",
+		},
+		{
+			name:  "down without up",
+			files: fstest.MapFS{"0003_gone.down.sql": {}},
+			want:  "has no up migration",
+		},
+		{
+			name:  "unnumbered",
+			files: fstest.MapFS{"init.sql": {}},
+			want:  "name must be",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(tt.files); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load() error = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_Embedded(t *testing.T) {
+	got, err := Load(migrations.Files)
+	if err != nil {
+		t.Fatalf("Load(embedded) error = %v", err)
+	}
+	for i, migration := range got {
+		if migration.Version != i+1 {
+			t.Errorf("migration %d is version %d, want versions numbered without gaps", i, migration.Version)
+		}
+		if strings.TrimSpace(migration.Up) == "" {
+			t.Errorf("migration %04d_%s is empty", migration.Version, migration.Name)
+		}
+	}
+}
+
+func TestPlan(t *testing.T) {
+	all := []Migration{
+		{Version: 1, Name: "init", Up: "up1"},
+		{Version: 2, Name: "votes", Up: "up2", Down: "down2"},
+		{Version: 3, Name: "index", Up: "up3", Down: "down3"},
+	}
+	applied := func(versions ...int) map[int]time.Time {
+		m := make(map[int]time.Time)
+		for _, v := range versions {
+			m[v] = time.Now()
+		}
+		return m
+	}
+	versions := func(migrations []Migration) []int {
+		var v []int
+		for _, m := range migrations {
+			v = append(v, m.Version)
+		}
+		return v
+	}
+
+	tests := []struct {
+		name     string
+		applied  map[int]time.Time
+		target   int
+		wantUp   []int
+		wantDown []int
+		wantErr  string
+	}{
+		{name: "fresh database", applied: applied(), target: 3, wantUp: []int{1, 2, 3}},
+		{name: "up to date", applied: applied(1, 2, 3), target: 3},
+		{name: "gap applied", applied: applied(1, 3), target: 3, wantUp: []int{2}},
+		{name: "to an older version", applied: applied(1, 2, 3), target: 1, wantDown: []int{3, 2}},
+		{name: "no down script", applied: applied(1, 2), target: 0, wantErr: "cannot be reverted"},
+		{name: "newer version applied", applied: applied(1, 3, 4), target: 4, wantUp: []int{2}},
+		{name: "revert unknown version", applied: applied(1, 2, 3, 4), target: 2, wantErr: "unknown to this binary"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			up, down, err := plan(all, tt.applied, tt.target)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("plan() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("plan() error = %v", err)
+			}
+			if got := versions(up); !equalInts(got, tt.wantUp) {
+				t.Errorf("plan() up = %v, want %v", got, tt.wantUp)
+			}
+			if got := versions(down); !equalInts(got, tt.wantDown) {
+				t.Errorf("plan() down = %v, want %v", got, tt.wantDown)
+			}
+		})
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
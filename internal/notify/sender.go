@@ -0,0 +1,66 @@
+// Package notify delivers one-time voter verification codes
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"voteweb/internal/domain"
+)
+
+// LogSender writes codes to the application log instead of delivering them.
+// It is meant for local testing: anyone who can read the log can vote as any
+// contact.
+type LogSender struct {
+	logger *slog.Logger
+}
+
+// NewLogSender creates a LogSender
+func NewLogSender(logger *slog.Logger) *LogSender {
+	return &LogSender{logger: logger}
+}
+
+func (s *LogSender) SendCode(ctx context.Context, msg domain.VerificationMessage) error {
+	s.logger.WarnContext(ctx, "verification code not delivered (log sender)",
+		"channel", msg.Channel,
+		"to", msg.To,
+		"code", msg.Code,
+		"expires_at", msg.ExpiresAt)
+	return nil
+}
+
+// FileSender appends codes to a file, one line per code, for local testing
+type FileSender struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileSender creates a FileSender writing to path
+func NewFileSender(path string) *FileSender {
+	return &FileSender{path: path}
+}
+
+func (s *FileSender) SendCode(ctx context.Context, msg domain.VerificationMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open code file: %w", err)
+	}
+
+	_, err = fmt.Fprintf(f, "%s\t%s\t%s\t%s\texpires %s\n",
+		time.Now().UTC().Format(time.RFC3339), msg.Channel, msg.To, msg.Code,
+		msg.ExpiresAt.UTC().Format(time.RFC3339))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("write code file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"voteweb/internal/domain"
+)
+
+// SMTPConfig holds the mail server settings
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPSender emails codes through a mail server. STARTTLS is used when the
+// server offers it; implicit TLS (port 465) is not supported.
+type SMTPSender struct {
+	cfg  SMTPConfig
+	send func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+	now  func() time.Time
+}
+
+// NewSMTPSender creates an SMTPSender. Without a username no authentication is attempted.
+func NewSMTPSender(cfg SMTPConfig) *SMTPSender {
+	return &SMTPSender{
+		cfg:  cfg,
+		send: smtp.SendMail,
+		now:  time.Now,
+	}
+}
+
+func (s *SMTPSender) SendCode(ctx context.Context, msg domain.VerificationMessage) error {
+	if msg.Channel != domain.ContactEmail {
+		return fmt.Errorf("smtp cannot deliver codes by %s", msg.Channel)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.cfg.Username != "" {
+		auth = smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+	}
+
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	if err := s.send(addr, auth, s.cfg.From, []string{msg.To}, s.message(msg)); err != nil {
+		return fmt.Errorf("send mail: %w", err)
+	}
+	return nil
+}
+
+// message builds a plain-text email. The recipient was validated by
+// domain.NormalizeContact; the event name only appears in the body.
+func (s *SMTPSender) message(msg domain.VerificationMessage) []byte {
+	minutes := int(math.Ceil(msg.ExpiresAt.Sub(s.now()).Minutes()))
+	if minutes < 1 {
+		minutes = 1
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", s.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: Kode verifikasi voting: %s\r\n", msg.Code)
+	fmt.Fprintf(&b, "Date: %s\r\n", s.now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	fmt.Fprintf(&b, "Kode verifikasi Anda untuk %s:\r\n\r\n", singleLine(msg.EventName))
+	fmt.Fprintf(&b, "    %s\r\n\r\n", msg.Code)
+	fmt.Fprintf(&b, "Kode berlaku selama %d menit. Abaikan email ini jika Anda tidak meminta kode.\r\n", minutes)
+	return []byte(b.String())
+}
+
+// singleLine keeps stored text from adding lines to the message
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
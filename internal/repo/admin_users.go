@@ -0,0 +1,141 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"voteweb/internal/domain"
+)
+
+// NewPostgresAdminUserRepository creates a PostgreSQL-backed admin account store
+func NewPostgresAdminUserRepository(pool *pgxpool.Pool) domain.AdminUserRepository {
+	return &postgresRepository{pool: pool}
+}
+
+const adminUserColumns = `id, username, password_hash, role, disabled_at, last_login_at, created_at, updated_at`
+
+// scanAdminUser scans a row selected with adminUserColumns
+func scanAdminUser(row pgx.Row) (*domain.AdminUser, error) {
+	var user domain.AdminUser
+	err := row.Scan(
+		&user.ID,
+		&user.Username,
+		&user.PasswordHash,
+		&user.Role,
+		&user.DisabledAt,
+		&user.LastLoginAt,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *postgresRepository) getAdminUser(ctx context.Context, where string, args ...any) (*domain.AdminUser, error) {
+	query := `SELECT ` + adminUserColumns + ` FROM admin_users WHERE ` + where
+
+	user, err := scanAdminUser(r.pool.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || isInvalidID(err) {
+			return nil, domain.ErrAdminUserNotFound
+		}
+		return nil, fmt.Errorf("query admin user: %w", err)
+	}
+
+	return user, nil
+}
+
+func (r *postgresRepository) GetAdminUserByID(ctx context.Context, id string) (*domain.AdminUser, error) {
+	return r.getAdminUser(ctx, `id = $1`, id)
+}
+
+func (r *postgresRepository) GetAdminUserByUsername(ctx context.Context, username string) (*domain.AdminUser, error) {
+	return r.getAdminUser(ctx, `LOWER(username) = LOWER($1)`, username)
+}
+
+func (r *postgresRepository) CreateAdminUser(ctx context.Context, user *domain.AdminUser) error {
+	query := `
+		INSERT INTO admin_users (username, password_hash, role)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.pool.QueryRow(ctx, query, user.Username, user.PasswordHash, user.Role).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrUsernameTaken
+		}
+		return fmt.Errorf("insert admin user: %w", err)
+	}
+
+	return nil
+}
+
+func (r *postgresRepository) ListAdminUsers(ctx context.Context) ([]*domain.AdminUser, error) {
+	query := `SELECT ` + adminUserColumns + ` FROM admin_users ORDER BY username`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query admin users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*domain.AdminUser
+	for rows.Next() {
+		user, err := scanAdminUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan admin user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return users, nil
+}
+
+func (r *postgresRepository) UpdateAdminUser(ctx context.Context, user *domain.AdminUser) error {
+	query := `
+		UPDATE admin_users
+		SET password_hash = $2, role = $3, disabled_at = $4, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err := r.pool.QueryRow(ctx, query, user.ID, user.PasswordHash, user.Role, user.DisabledAt).Scan(&user.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrAdminUserNotFound
+		}
+		return fmt.Errorf("update admin user: %w", err)
+	}
+
+	return nil
+}
+
+func (r *postgresRepository) RecordAdminLogin(ctx context.Context, id string) error {
+	if _, err := r.pool.Exec(ctx, `UPDATE admin_users SET last_login_at = NOW() WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("record admin login: %w", err)
+	}
+
+	return nil
+}
+
+func (r *postgresRepository) CountActiveSuperadmins(ctx context.Context) (int, error) {
+	query := `SELECT COUNT(*) FROM admin_users WHERE role = 'superadmin' AND disabled_at IS NULL`
+
+	var count int
+	if err := r.pool.QueryRow(ctx, query).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count superadmins: %w", err)
+	}
+
+	return count, nil
+}
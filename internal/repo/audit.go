@@ -0,0 +1,151 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"voteweb/internal/domain"
+)
+
+// NewPostgresAuditRepository creates a PostgreSQL-backed audit log
+func NewPostgresAuditRepository(pool *pgxpool.Pool) domain.AuditRepository {
+	return &postgresRepository{pool: pool}
+}
+
+const auditColumns = `id, occurred_at, actor_id::text, actor, action, target_type, target_id,
+		       before, after, COALESCE(request_id, ''), ip_hash`
+
+// scanAuditEvent scans a row selected with auditColumns
+func scanAuditEvent(row pgx.Row) (*domain.AuditEvent, error) {
+	var event domain.AuditEvent
+	err := row.Scan(
+		&event.ID,
+		&event.OccurredAt,
+		&event.ActorID,
+		&event.Actor,
+		&event.Action,
+		&event.TargetType,
+		&event.TargetID,
+		&event.Before,
+		&event.After,
+		&event.RequestID,
+		&event.IPHash,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (r *postgresRepository) InsertAuditEvent(ctx context.Context, event *domain.AuditEvent) error {
+	query := `
+		INSERT INTO audit_events (occurred_at, actor_id, actor, action, target_type, target_id, before, after, request_id, ip_hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), $10)
+		RETURNING id
+	`
+
+	err := r.pool.QueryRow(ctx, query,
+		event.OccurredAt,
+		event.ActorID,
+		event.Actor,
+		event.Action,
+		event.TargetType,
+		event.TargetID,
+		event.Before,
+		event.After,
+		event.RequestID,
+		event.IPHash,
+	).Scan(&event.ID)
+	if err != nil {
+		return fmt.Errorf("insert audit event: %w", err)
+	}
+
+	return nil
+}
+
+func (r *postgresRepository) ListAuditEvents(ctx context.Context, filter domain.AuditFilter) ([]*domain.AuditEvent, error) {
+	var events []*domain.AuditEvent
+	err := r.StreamAuditEvents(ctx, filter, func(event *domain.AuditEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// StreamAuditEvents calls fn for each matching row without buffering the result
+func (r *postgresRepository) StreamAuditEvents(ctx context.Context, filter domain.AuditFilter, fn func(*domain.AuditEvent) error) error {
+	where, args := auditFilterClause(filter)
+	query := `SELECT ` + auditColumns + ` FROM audit_events` + where + ` ORDER BY id DESC`
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += ` LIMIT $` + strconv.Itoa(len(args))
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("query audit events: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
+			return fmt.Errorf("scan audit event: %w", err)
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return nil
+}
+
+// auditFilterClause builds the WHERE clause and arguments for filter
+func auditFilterClause(filter domain.AuditFilter) (string, []any) {
+	var conditions []string
+	var args []any
+
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, strings.ReplaceAll(condition, "?", "$"+strconv.Itoa(len(args))))
+	}
+
+	if filter.Action != "" {
+		add(`action = ?`, filter.Action)
+	}
+	if filter.Actor != "" {
+		add(`actor = ?`, filter.Actor)
+	}
+	if filter.TargetType != "" {
+		add(`target_type = ?`, filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		add(`target_id = ?`, filter.TargetID)
+	}
+	if filter.Since != nil {
+		add(`occurred_at >= ?`, *filter.Since)
+	}
+	if filter.Until != nil {
+		add(`occurred_at < ?`, *filter.Until)
+	}
+	if filter.BeforeID > 0 {
+		add(`id < ?`, filter.BeforeID)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
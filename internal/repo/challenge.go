@@ -0,0 +1,57 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"voteweb/internal/domain"
+)
+
+// NewPostgresChallengeRepository creates a PostgreSQL-backed source of the
+// recent-vote counts used to score vote requests and a store of solved
+// challenge tokens
+func NewPostgresChallengeRepository(pool *pgxpool.Pool) domain.ChallengeRepository {
+	return &postgresRepository{pool: pool}
+}
+
+func (r *postgresRepository) CountRecentVotesByIP(ctx context.Context, eventID string, ipHash []byte, since time.Time) (int64, error) {
+	query := `SELECT COUNT(*) FROM votes WHERE event_id = $1 AND voter_ip_hash = $2 AND created_at >= $3`
+
+	var count int64
+	err := r.pool.QueryRow(ctx, query, eventID, ipHash, since).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("count recent votes by ip: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *postgresRepository) UseChallengeToken(ctx context.Context, tokenHash []byte, expiresAt time.Time) (bool, error) {
+	// Expired tokens fail verification before they get here, so they can go
+	if _, err := r.pool.Exec(ctx, `DELETE FROM used_challenges WHERE expires_at < NOW()`); err != nil {
+		return false, fmt.Errorf("delete expired challenges: %w", err)
+	}
+
+	query := `
+		INSERT INTO used_challenges (token_hash, expires_at)
+		VALUES ($1, $2)
+		ON CONFLICT (token_hash) DO NOTHING
+	`
+
+	tag, err := r.pool.Exec(ctx, query, tokenHash, expiresAt)
+	if err != nil {
+		return false, fmt.Errorf("insert used challenge: %w", err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *postgresRepository) ReleaseChallengeToken(ctx context.Context, tokenHash []byte) error {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM used_challenges WHERE token_hash = $1`, tokenHash); err != nil {
+		return fmt.Errorf("delete used challenge: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,431 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"voteweb/internal/domain"
+)
+
+// conformanceGroups are the groups of the event every conformance test starts with
+var conformanceGroups = []string{"digital", "operasional"}
+
+// conformanceBackend returns a repository and a fresh event of its own that
+// holds conformanceGroups and nothing else
+type conformanceBackend func(t *testing.T) (domain.Repository, *domain.Event)
+
+// runRepositoryConformance checks that a domain.Repository behaves like the
+// PostgreSQL schema: unique votes per scope, not-found errors, innovation
+// ordering and vote counts. Every backend must pass it.
+func runRepositoryConformance(t *testing.T, newBackend conformanceBackend) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, repo domain.Repository, event *domain.Event)
+	}{
+		{"DuplicateVote", testConformanceDuplicateVote},
+		{"ConcurrentVotes", testConformanceConcurrentVotes},
+		{"ConcurrentCappedVotes", testConformanceConcurrentCappedVotes},
+		{"NotFound", testConformanceNotFound},
+		{"SlugUniqueness", testConformanceSlugUniqueness},
+		{"ListInnovationsOrder", testConformanceListInnovationsOrder},
+		{"Counting", testConformanceCounting},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, event := newBackend(t)
+			tt.run(t, repo, event)
+		})
+	}
+}
+
+func createConformanceInnovation(t *testing.T, repo domain.Repository, event *domain.Event, groupSlug, slug, name string) *domain.Innovation {
+	t.Helper()
+	innovation := &domain.Innovation{EventID: event.ID, GroupSlug: groupSlug, Slug: slug, Name: name}
+	if err := repo.CreateInnovation(context.Background(), innovation); err != nil {
+		t.Fatalf("CreateInnovation(%s/%s) error = %v", groupSlug, slug, err)
+	}
+	return innovation
+}
+
+// conformanceScope returns the scope key of innovation under the named vote policy
+func conformanceScope(t *testing.T, policy string, innovation *domain.Innovation) string {
+	t.Helper()
+	votePolicy, err := domain.NewVotePolicy(policy)
+	if err != nil {
+		t.Fatalf("NewVotePolicy(%q) error = %v", policy, err)
+	}
+	return votePolicy.ScopeKey(innovation)
+}
+
+// conformanceVote builds a vote for innovation; voter and ip stand in for the
+// hashed voter key and IP
+func conformanceVote(event *domain.Event, innovation *domain.Innovation, scopeKey, voter, ip string) *domain.Vote {
+	return &domain.Vote{
+		EventID:      event.ID,
+		InnovationID: innovation.ID,
+		ScopeKey:     scopeKey,
+		VoterKey:     []byte("voter:" + voter),
+		VoterIPHash:  []byte("ip:" + ip),
+		SubnetHash:   []byte("subnet:" + ip),
+		UserAgent:    "conformance",
+	}
+}
+
+func insertConformanceVote(t *testing.T, repo domain.Repository, vote *domain.Vote) bool {
+	t.Helper()
+	inserted, err := repo.InsertVote(context.Background(), vote)
+	if err != nil {
+		t.Fatalf("InsertVote() error = %v", err)
+	}
+	return inserted
+}
+
+func wantVoteCount(t *testing.T, repo domain.Repository, innovation *domain.Innovation, want int64) {
+	t.Helper()
+	got, err := repo.GetVoteCount(context.Background(), innovation.ID)
+	if err != nil {
+		t.Fatalf("GetVoteCount() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("GetVoteCount(%s) = %d, want %d", innovation.Slug, got, want)
+	}
+}
+
+func testConformanceDuplicateVote(t *testing.T, repo domain.Repository, event *domain.Event) {
+	ctx := context.Background()
+	meter := createConformanceInnovation(t, repo, event, "digital", "smart-meter", "Smart Meter")
+	form := createConformanceInnovation(t, repo, event, "digital", "jabar-form", "Jabar Form")
+	global := conformanceScope(t, domain.VotePolicyGlobal, meter)
+
+	if !insertConformanceVote(t, repo, conformanceVote(event, meter, global, "alice", "10.0.0.1")) {
+		t.Fatal("first vote was not inserted")
+	}
+	if insertConformanceVote(t, repo, conformanceVote(event, meter, global, "alice", "10.0.0.1")) {
+		t.Error("repeated vote was inserted")
+	}
+	if insertConformanceVote(t, repo, conformanceVote(event, form, global, "alice", "10.0.0.2")) {
+		t.Error("second vote in the same scope was inserted")
+	}
+	if !insertConformanceVote(t, repo, conformanceVote(event, form, global, "bob", "10.0.0.1")) {
+		t.Error("another voter's vote was not inserted")
+	}
+	perInnovation := conformanceScope(t, domain.VotePolicyPerInnovation, form)
+	if !insertConformanceVote(t, repo, conformanceVote(event, form, perInnovation, "alice", "10.0.0.1")) {
+		t.Error("vote in another scope was not inserted")
+	}
+
+	wantVoteCount(t, repo, meter, 1)
+	wantVoteCount(t, repo, form, 2)
+
+	voted, err := repo.HasVotedInScope(ctx, event.ID, global, []byte("voter:alice"))
+	if err != nil || !voted {
+		t.Errorf("HasVotedInScope(alice) = %v, %v, want true", voted, err)
+	}
+	voted, err = repo.HasVotedInScope(ctx, event.ID, global, []byte("voter:carol"))
+	if err != nil || voted {
+		t.Errorf("HasVotedInScope(carol) = %v, %v, want false", voted, err)
+	}
+
+	innovation, err := repo.GetVotedInnovation(ctx, event.ID, global, []byte("voter:alice"))
+	if err != nil || innovation.ID != meter.ID {
+		t.Errorf("GetVotedInnovation(alice) = %+v, %v, want the smart meter", innovation, err)
+	}
+}
+
+func testConformanceConcurrentVotes(t *testing.T, repo domain.Repository, event *domain.Event) {
+	meter := createConformanceInnovation(t, repo, event, "digital", "smart-meter", "Smart Meter")
+	form := createConformanceInnovation(t, repo, event, "digital", "jabar-form", "Jabar Form")
+	global := conformanceScope(t, domain.VotePolicyGlobal, meter)
+	targets := []*domain.Innovation{meter, form}
+
+	const voters = 32
+	var inserted atomic.Int32
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*voters)
+
+	// Half the goroutines share one voter, the other half vote once each
+	for i := range 2 * voters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			voter := "shared"
+			if i%2 == 1 {
+				voter = uuid.NewString()
+			}
+			ok, err := repo.InsertVote(context.Background(), conformanceVote(event, targets[i%4/2], global, voter, "10.0.0.1"))
+			if err != nil {
+				errs <- err
+				return
+			}
+			if ok {
+				inserted.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("InsertVote() error = %v", err)
+	}
+	if got := inserted.Load(); got != voters+1 {
+		t.Errorf("inserted %d votes, want %d: one for the shared voter and one per other voter", got, voters+1)
+	}
+
+	total, err := repo.GetTotalVoters(context.Background(), event.ID)
+	if err != nil {
+		t.Fatalf("GetTotalVoters() error = %v", err)
+	}
+	if total != voters+1 {
+		t.Errorf("GetTotalVoters() = %d, want %d", total, voters+1)
+	}
+}
+
+func testConformanceConcurrentCappedVotes(t *testing.T, repo domain.Repository, event *domain.Event) {
+	ctx := context.Background()
+	meter := createConformanceInnovation(t, repo, event, "digital", "smart-meter", "Smart Meter")
+	global := conformanceScope(t, domain.VotePolicyGlobal, meter)
+
+	const maxPerIP = 3
+	var inserted, capped atomic.Int32
+	var wg sync.WaitGroup
+	errs := make(chan error, 32)
+
+	// Every goroutine brings a fresh voter token from one network
+	for range 32 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, err := repo.InsertVoteCapped(ctx, conformanceVote(event, meter, global, uuid.NewString(), "10.0.0.1"), maxPerIP)
+			switch {
+			case errors.Is(err, domain.ErrIPVoteCapReached):
+				capped.Add(1)
+			case err != nil:
+				errs <- err
+			case ok:
+				inserted.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("InsertVoteCapped() error = %v", err)
+	}
+	if got := inserted.Load(); got != maxPerIP {
+		t.Errorf("inserted %d votes, want the cap of %d", got, maxPerIP)
+	}
+	if got := capped.Load(); got != 32-maxPerIP {
+		t.Errorf("capped %d votes, want %d", got, 32-maxPerIP)
+	}
+	wantVoteCount(t, repo, meter, maxPerIP)
+
+	// Another network is not affected, and a voter who already voted is a
+	// duplicate rather than capped
+	ok, err := repo.InsertVoteCapped(ctx, conformanceVote(event, meter, global, "bob", "10.0.0.2"), maxPerIP)
+	if err != nil || !ok {
+		t.Errorf("InsertVoteCapped() from another IP = %v, %v, want true", ok, err)
+	}
+	ok, err = repo.InsertVoteCapped(ctx, conformanceVote(event, meter, global, "bob", "10.0.0.1"), maxPerIP)
+	if err != nil || ok {
+		t.Errorf("InsertVoteCapped() repeated = %v, %v, want false without an error", ok, err)
+	}
+}
+
+func testConformanceNotFound(t *testing.T, repo domain.Repository, event *domain.Event) {
+	ctx := context.Background()
+	missingID := uuid.NewString()
+	meter := createConformanceInnovation(t, repo, event, "digital", "smart-meter", "Smart Meter")
+
+	if _, err := repo.GetEventBySlug(ctx, "no-such-event-"+missingID); !errors.Is(err, domain.ErrEventNotFound) {
+		t.Errorf("GetEventBySlug() error = %v, want ErrEventNotFound", err)
+	}
+	if _, err := repo.GetEventByID(ctx, missingID); !errors.Is(err, domain.ErrEventNotFound) {
+		t.Errorf("GetEventByID() error = %v, want ErrEventNotFound", err)
+	}
+	if err := repo.UpdateEventWindow(ctx, missingID, &domain.VotingWindow{}); !errors.Is(err, domain.ErrEventNotFound) {
+		t.Errorf("UpdateEventWindow() error = %v, want ErrEventNotFound", err)
+	}
+
+	if _, err := repo.GetInnovationBySlug(ctx, event.ID, "digital", "missing"); !errors.Is(err, domain.ErrInnovationNotFound) {
+		t.Errorf("GetInnovationBySlug() error = %v, want ErrInnovationNotFound", err)
+	}
+	if _, err := repo.GetInnovationBySlug(ctx, event.ID, "operasional", meter.Slug); !errors.Is(err, domain.ErrInnovationNotFound) {
+		t.Errorf("GetInnovationBySlug() in another group error = %v, want ErrInnovationNotFound", err)
+	}
+	for _, id := range []string{missingID, "not-a-uuid"} {
+		if _, err := repo.GetInnovationByID(ctx, id); !errors.Is(err, domain.ErrInnovationNotFound) {
+			t.Errorf("GetInnovationByID(%q) error = %v, want ErrInnovationNotFound", id, err)
+		}
+	}
+
+	missing := &domain.Innovation{ID: missingID, EventID: event.ID, GroupSlug: "digital", Slug: "missing", Name: "Missing"}
+	if err := repo.UpdateInnovation(ctx, missing); !errors.Is(err, domain.ErrInnovationNotFound) {
+		t.Errorf("UpdateInnovation() error = %v, want ErrInnovationNotFound", err)
+	}
+	if err := repo.SetInnovationArchived(ctx, missingID, true); !errors.Is(err, domain.ErrInnovationNotFound) {
+		t.Errorf("SetInnovationArchived() error = %v, want ErrInnovationNotFound", err)
+	}
+
+	form := createConformanceInnovation(t, repo, event, "digital", "jabar-form", "Jabar Form")
+	if err := repo.SetInnovationPositions(ctx, []string{form.ID, missingID}); !errors.Is(err, domain.ErrInnovationNotFound) {
+		t.Errorf("SetInnovationPositions() error = %v, want ErrInnovationNotFound", err)
+	}
+	if got, err := repo.GetInnovationByID(ctx, form.ID); err != nil || got.Position != form.Position {
+		t.Errorf("position after a failed reorder = %+v, %v, want %d unchanged", got, err, form.Position)
+	}
+
+	global := conformanceScope(t, domain.VotePolicyGlobal, meter)
+	if _, err := repo.GetVotedInnovation(ctx, event.ID, global, []byte("voter:nobody")); !errors.Is(err, domain.ErrInnovationNotFound) {
+		t.Errorf("GetVotedInnovation() error = %v, want ErrInnovationNotFound", err)
+	}
+	if _, err := repo.InsertVote(ctx, conformanceVote(event, missing, global, "alice", "10.0.0.1")); err == nil {
+		t.Error("InsertVote() for a missing innovation succeeded")
+	}
+}
+
+func testConformanceSlugUniqueness(t *testing.T, repo domain.Repository, event *domain.Event) {
+	ctx := context.Background()
+	meter := createConformanceInnovation(t, repo, event, "digital", "smart-meter", "Smart Meter")
+
+	duplicate := &domain.Innovation{EventID: event.ID, GroupSlug: "digital", Slug: "smart-meter", Name: "Smart Meter 2"}
+	if err := repo.CreateInnovation(ctx, duplicate); !errors.Is(err, domain.ErrSlugTaken) {
+		t.Errorf("CreateInnovation() of a taken slug error = %v, want ErrSlugTaken", err)
+	}
+	other := createConformanceInnovation(t, repo, event, "operasional", "smart-meter", "Smart Meter Operasional")
+	if other.Position != 1 {
+		t.Errorf("Position = %d, want 1 as the first innovation of its group", other.Position)
+	}
+
+	exists, err := repo.SlugExists(ctx, event.ID, "digital", "smart-meter", "")
+	if err != nil || !exists {
+		t.Errorf("SlugExists() = %v, %v, want true", exists, err)
+	}
+	exists, err = repo.SlugExists(ctx, event.ID, "digital", "smart-meter", meter.ID)
+	if err != nil || exists {
+		t.Errorf("SlugExists() excluding the owner = %v, %v, want false", exists, err)
+	}
+
+	moved := *other
+	moved.GroupSlug = "digital"
+	if err := repo.UpdateInnovation(ctx, &moved); !errors.Is(err, domain.ErrSlugTaken) {
+		t.Errorf("UpdateInnovation() onto a taken slug error = %v, want ErrSlugTaken", err)
+	}
+
+	// Archived innovations keep their slug
+	if err := repo.SetInnovationArchived(ctx, meter.ID, true); err != nil {
+		t.Fatalf("SetInnovationArchived() error = %v", err)
+	}
+	if err := repo.CreateInnovation(ctx, duplicate); !errors.Is(err, domain.ErrSlugTaken) {
+		t.Errorf("CreateInnovation() of an archived slug error = %v, want ErrSlugTaken", err)
+	}
+}
+
+func testConformanceListInnovationsOrder(t *testing.T, repo domain.Repository, event *domain.Event) {
+	ctx := context.Background()
+	zeta := createConformanceInnovation(t, repo, event, "operasional", "zeta", "Zeta")
+	beta := createConformanceInnovation(t, repo, event, "digital", "beta", "Beta")
+	alpha := createConformanceInnovation(t, repo, event, "digital", "alpha", "Alpha")
+	gamma := createConformanceInnovation(t, repo, event, "digital", "gamma", "Gamma")
+	if beta.Position != 1 || alpha.Position != 2 || gamma.Position != 3 {
+		t.Errorf("positions = %d, %d, %d, want innovations appended to their group",
+			beta.Position, alpha.Position, gamma.Position)
+	}
+
+	// Gamma moves first; Beta and Alpha then share position 2 and sort by name
+	if err := repo.SetInnovationPositions(ctx, []string{gamma.ID, beta.ID}); err != nil {
+		t.Fatalf("SetInnovationPositions() error = %v", err)
+	}
+	wantInnovationOrder(t, "ListInnovations()", repo.ListInnovations, event, gamma, alpha, beta, zeta)
+
+	if err := repo.SetInnovationArchived(ctx, gamma.ID, true); err != nil {
+		t.Fatalf("SetInnovationArchived() error = %v", err)
+	}
+	wantInnovationOrder(t, "ListInnovations() after archiving", repo.ListInnovations, event, alpha, beta, zeta)
+	wantInnovationOrder(t, "ListAllInnovations()", repo.ListAllInnovations, event, gamma, alpha, beta, zeta)
+
+	if err := repo.SetInnovationArchived(ctx, gamma.ID, false); err != nil {
+		t.Fatalf("SetInnovationArchived() restore error = %v", err)
+	}
+	wantInnovationOrder(t, "ListInnovations() after restoring", repo.ListInnovations, event, gamma, alpha, beta, zeta)
+}
+
+func wantInnovationOrder(t *testing.T, name string, list func(context.Context, string) ([]*domain.Innovation, error),
+	event *domain.Event, want ...*domain.Innovation) {
+	t.Helper()
+	got, err := list(context.Background(), event.ID)
+	if err != nil {
+		t.Fatalf("%s error = %v", name, err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("%s = %d innovations, want %d", name, len(got), len(want))
+	}
+	for i := range want {
+		if got[i].ID != want[i].ID {
+			t.Errorf("%s[%d] = %s, want %s", name, i, got[i].Slug, want[i].Slug)
+		}
+	}
+}
+
+func testConformanceCounting(t *testing.T, repo domain.Repository, event *domain.Event) {
+	ctx := context.Background()
+	meter := createConformanceInnovation(t, repo, event, "digital", "smart-meter", "Smart Meter")
+	form := createConformanceInnovation(t, repo, event, "digital", "jabar-form", "Jabar Form")
+	sapa := createConformanceInnovation(t, repo, event, "operasional", "sapa-warga", "Sapa Warga")
+	digital := conformanceScope(t, domain.VotePolicyPerGroup, meter)
+	operasional := conformanceScope(t, domain.VotePolicyPerGroup, sapa)
+
+	votes := []*domain.Vote{
+		conformanceVote(event, meter, digital, "alice", "10.0.0.1"),
+		conformanceVote(event, sapa, operasional, "alice", "10.0.0.1"),
+		conformanceVote(event, meter, digital, "bob", "10.0.0.2"),
+		// Two voters behind one IP, as with voter tokens
+		conformanceVote(event, form, digital, "carol", "10.0.0.3"),
+		conformanceVote(event, meter, digital, "dave", "10.0.0.3"),
+	}
+	for _, vote := range votes {
+		if !insertConformanceVote(t, repo, vote) {
+			t.Fatalf("vote %s was not inserted", vote.VoterKey)
+		}
+	}
+
+	wantVoteCount(t, repo, meter, 3)
+	wantVoteCount(t, repo, form, 1)
+	wantVoteCount(t, repo, sapa, 1)
+
+	total, err := repo.GetTotalVoters(ctx, event.ID)
+	if err != nil || total != 4 {
+		t.Errorf("GetTotalVoters() = %d, %v, want 4", total, err)
+	}
+
+	// Archived innovations leave the results but their voters still count
+	if err := repo.SetInnovationArchived(ctx, sapa.ID, true); err != nil {
+		t.Fatalf("SetInnovationArchived() error = %v", err)
+	}
+	results, err := repo.GetEventResults(ctx, event.ID)
+	if err != nil {
+		t.Fatalf("GetEventResults() error = %v", err)
+	}
+	if results.TotalVoters != 4 {
+		t.Errorf("TotalVoters = %d, want 4", results.TotalVoters)
+	}
+	want := []struct {
+		id    string
+		count int64
+	}{{meter.ID, 3}, {form.ID, 1}}
+	if len(results.Innovations) != len(want) {
+		t.Fatalf("results = %d innovations, want %d", len(results.Innovations), len(want))
+	}
+	for i, w := range want {
+		if got := results.Innovations[i]; got.ID != w.id || got.VoteCount != w.count {
+			t.Errorf("results[%d] = %s with %d votes, want %s with %d", i, got.Slug, got.VoteCount, w.id, w.count)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"voteweb/internal/domain"
+)
+
+// NewPostgresFraudRepository creates a PostgreSQL-backed store for vote
+// flags and review decisions
+func NewPostgresFraudRepository(pool *pgxpool.Pool) domain.FraudRepository {
+	return &postgresRepository{pool: pool}
+}
+
+func (r *postgresRepository) ListVoteSamples(ctx context.Context, eventID string, since time.Time) ([]*domain.VoteSample, error) {
+	query := `
+		SELECT id, innovation_id, created_at, voter_subnet_hash, COALESCE(user_agent, '')
+		FROM votes
+		WHERE event_id = $1 AND created_at >= $2
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.pool.Query(ctx, query, eventID, since)
+	if err != nil {
+		return nil, fmt.Errorf("query vote samples: %w", err)
+	}
+	defer rows.Close()
+
+	var samples []*domain.VoteSample
+	for rows.Next() {
+		var sample domain.VoteSample
+		if err := rows.Scan(&sample.ID, &sample.InnovationID, &sample.CreatedAt, &sample.SubnetHash, &sample.UserAgent); err != nil {
+			return nil, fmt.Errorf("scan vote sample: %w", err)
+		}
+		samples = append(samples, &sample)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return samples, nil
+}
+
+func (r *postgresRepository) InsertVoteFlags(ctx context.Context, flags []*domain.VoteFlag) (int, error) {
+	query := `
+		INSERT INTO vote_flags (vote_id, event_id, rule, detail, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (vote_id, rule) DO NOTHING
+	`
+
+	batch := &pgx.Batch{}
+	for _, flag := range flags {
+		batch.Queue(query, flag.VoteID, flag.EventID, flag.Rule, flag.Detail, flag.CreatedAt)
+	}
+
+	results := r.pool.SendBatch(ctx, batch)
+	defer results.Close()
+
+	added := 0
+	for range flags {
+		tag, err := results.Exec()
+		if err != nil {
+			return added, fmt.Errorf("insert vote flag: %w", err)
+		}
+		added += int(tag.RowsAffected())
+	}
+
+	return added, nil
+}
+
+func (r *postgresRepository) ListFlaggedVotes(ctx context.Context, filter domain.FlaggedVoteFilter) ([]*domain.FlaggedVote, error) {
+	conditions := []string{"v.event_id = $1"}
+	args := []any{filter.EventID}
+
+	switch filter.Status {
+	case domain.FlagStatusPending:
+		conditions = append(conditions, "v.reviewed_at IS NULL")
+	case domain.FlagStatusInvalidated:
+		conditions = append(conditions, "v.invalidated_at IS NOT NULL")
+	case domain.FlagStatusKept:
+		conditions = append(conditions, "v.reviewed_at IS NOT NULL", "v.invalidated_at IS NULL")
+	}
+	if filter.Rule != "" {
+		args = append(args, filter.Rule)
+		conditions = append(conditions, fmt.Sprintf("EXISTS (SELECT 1 FROM vote_flags r WHERE r.vote_id = v.id AND r.rule = $%d)", len(args)))
+	}
+	if filter.BeforeID > 0 {
+		args = append(args, filter.BeforeID)
+		conditions = append(conditions, fmt.Sprintf("v.id < $%d", len(args)))
+	}
+	args = append(args, filter.Limit)
+
+	query := `
+		SELECT v.id, v.innovation_id, i.group_slug, i.name, v.created_at,
+		       COALESCE(v.user_agent, ''), v.voter_ip_hash,
+		       v.reviewed_at, v.invalidated_at, COALESCE(v.invalidation_reason, ''),
+		       array_agg(f.rule ORDER BY f.rule), array_agg(f.detail ORDER BY f.rule)
+		FROM votes v
+		JOIN vote_flags f ON f.vote_id = v.id
+		JOIN innovations i ON i.id = v.innovation_id
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		GROUP BY v.id, i.group_slug, i.name
+		ORDER BY v.id DESC
+		LIMIT $` + fmt.Sprint(len(args))
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query flagged votes: %w", err)
+	}
+	defer rows.Close()
+
+	var votes []*domain.FlaggedVote
+	for rows.Next() {
+		var vote domain.FlaggedVote
+		var ipHash []byte
+		var rules, details []string
+		if err := rows.Scan(&vote.ID, &vote.InnovationID, &vote.GroupSlug, &vote.InnovationName, &vote.CreatedAt,
+			&vote.UserAgent, &ipHash, &vote.ReviewedAt, &vote.InvalidatedAt, &vote.InvalidationReason,
+			&rules, &details); err != nil {
+			return nil, fmt.Errorf("scan flagged vote: %w", err)
+		}
+		vote.ShortIPHash = domain.ShortHash(ipHash)
+		for i, rule := range rules {
+			vote.Flags = append(vote.Flags, domain.FlagSummary{Rule: rule, Detail: details[i]})
+		}
+		votes = append(votes, &vote)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return votes, nil
+}
+
+func (r *postgresRepository) ReviewVotes(ctx context.Context, eventID string, ids []int64, invalidate bool, reason string, at time.Time) (int, error) {
+	// Votes already reviewed into the requested state are left untouched
+	query := `
+		UPDATE votes
+		SET reviewed_at = $4,
+		    invalidated_at = CASE WHEN $3 THEN $4::timestamptz END,
+		    invalidation_reason = CASE WHEN $3 THEN $5::text END
+		WHERE event_id = $1 AND id = ANY($2)
+		  AND (reviewed_at IS NULL OR (invalidated_at IS NOT NULL) <> $3)
+	`
+
+	tag, err := r.pool.Exec(ctx, query, eventID, ids, invalidate, at, reason)
+	if err != nil {
+		return 0, fmt.Errorf("review votes: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
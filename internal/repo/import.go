@@ -0,0 +1,53 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"voteweb/internal/domain"
+)
+
+// NewPostgresImportRepository creates a PostgreSQL-backed store for bulk
+// innovation imports
+func NewPostgresImportRepository(pool *pgxpool.Pool) domain.ImportRepository {
+	return &postgresRepository{pool: pool}
+}
+
+func (r *postgresRepository) ImportInnovations(ctx context.Context, creates, updates []*domain.Innovation) error {
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		for _, innovation := range creates {
+			err := tx.QueryRow(ctx, insertInnovationQuery,
+				innovation.EventID, innovation.GroupSlug, innovation.Slug, innovation.Name,
+				innovation.Division, innovation.EntityName, innovation.PIC, innovation.Description,
+				innovation.LogoInnovationURL, innovation.LogoEntityURL, innovation.VideoURL, innovation.SlideURL,
+				innovation.IgURL, innovation.YtURL, innovation.HeroURL, innovation.HeroMobileURL,
+			).Scan(&innovation.ID, &innovation.Position, &innovation.CreatedAt, &innovation.UpdatedAt)
+			if err != nil {
+				if isUniqueViolation(err) {
+					return fmt.Errorf("%w: %s/%s", domain.ErrSlugTaken, innovation.GroupSlug, innovation.Slug)
+				}
+				return fmt.Errorf("insert innovation %s/%s: %w", innovation.GroupSlug, innovation.Slug, err)
+			}
+		}
+
+		for _, innovation := range updates {
+			err := tx.QueryRow(ctx, updateInnovationQuery,
+				innovation.ID, innovation.GroupSlug, innovation.Slug, innovation.Name,
+				innovation.Division, innovation.EntityName, innovation.PIC, innovation.Description,
+				innovation.LogoInnovationURL, innovation.LogoEntityURL, innovation.VideoURL, innovation.SlideURL,
+				innovation.IgURL, innovation.YtURL, innovation.HeroURL, innovation.HeroMobileURL,
+			).Scan(&innovation.UpdatedAt)
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return fmt.Errorf("%w: %s", domain.ErrInnovationNotFound, innovation.ID)
+				}
+				return fmt.Errorf("update innovation %s: %w", innovation.ID, err)
+			}
+		}
+		return nil
+	})
+}
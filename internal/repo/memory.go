@@ -0,0 +1,1244 @@
+package repo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"voteweb/internal/domain"
+)
+
+// MemoryRepository keeps everything in process memory. It implements every
+// domain repository interface with the semantics of the PostgreSQL schema
+// (unique keys, foreign keys, not-found errors), for tests and DEMO_MODE.
+// Data is lost when the process exits. It is safe for concurrent use.
+type MemoryRepository struct {
+	mu sync.RWMutex
+
+	events        map[string]*domain.Event
+	groups        []*domain.Group
+	innovations   map[string]*domain.Innovation
+	votes         []*memoryVote
+	flags         []*domain.VoteFlag
+	adminUsers    map[string]*domain.AdminUser
+	adminSessions map[string]*domain.AdminSession
+	auditEvents   []*domain.AuditEvent
+	voterCodes    map[string]*domain.VerificationCode
+	voterSessions map[string]*domain.VoterSession
+	// usedChallenges maps solved challenge token hashes to their expiry. In
+	// DEMO_MODE they live only as long as the process.
+	usedChallenges map[string]time.Time
+
+	nextVoteID  int64
+	nextAuditID int64
+}
+
+// memoryVote is a row of the votes table
+type memoryVote struct {
+	domain.Vote
+	reviewedAt         *time.Time
+	invalidatedAt      *time.Time
+	invalidationReason string
+}
+
+// NewMemoryRepository creates an empty in-memory store holding only the
+// default event, as a freshly migrated database does
+func NewMemoryRepository() *MemoryRepository {
+	now := memoryNow()
+	defaultEvent := &domain.Event{
+		ID:                uuid.NewString(),
+		Slug:              "default",
+		Name:              "Default Event",
+		IsDefault:         true,
+		VotePolicy:        domain.VotePolicyGlobal,
+		VoterVerification: domain.VoterVerificationIP,
+		Window:            domain.VotingWindow{Paused: true, UpdatedAt: now},
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+
+	return &MemoryRepository{
+		events:         map[string]*domain.Event{defaultEvent.ID: defaultEvent},
+		innovations:    make(map[string]*domain.Innovation),
+		adminUsers:     make(map[string]*domain.AdminUser),
+		adminSessions:  make(map[string]*domain.AdminSession),
+		voterCodes:     make(map[string]*domain.VerificationCode),
+		voterSessions:  make(map[string]*domain.VoterSession),
+		usedChallenges: make(map[string]time.Time),
+	}
+}
+
+// memoryNow returns the current time at the microsecond precision of
+// PostgreSQL timestamps
+func memoryNow() time.Time {
+	return time.Now().Truncate(time.Microsecond)
+}
+
+// AddEvent stores a new event. Events have no repository method because the
+// PostgreSQL schema gets them from migrations.
+func (m *MemoryRepository) AddEvent(ctx context.Context, event *domain.Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, existing := range m.events {
+		if existing.Slug == event.Slug || (event.IsDefault && existing.IsDefault) {
+			return fmt.Errorf("insert event: %s conflicts with event %s", event.Slug, existing.Slug)
+		}
+	}
+
+	now := memoryNow()
+	stored := *event
+	stored.ID = uuid.NewString()
+	if stored.VotePolicy == "" {
+		stored.VotePolicy = domain.VotePolicyGlobal
+	}
+	if stored.VoterVerification == "" {
+		stored.VoterVerification = domain.VoterVerificationIP
+	}
+	stored.CreatedAt, stored.UpdatedAt, stored.Window.UpdatedAt = now, now, now
+	m.events[stored.ID] = &stored
+	*event = stored
+	return nil
+}
+
+// AddGroup inserts a group or updates its name and position, like the seed
+func (m *MemoryRepository) AddGroup(ctx context.Context, group *domain.Group) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.events[group.EventID]; !ok {
+		return fmt.Errorf("insert group %s: %w", group.Slug, domain.ErrEventNotFound)
+	}
+	for _, existing := range m.groups {
+		if existing.EventID == group.EventID && existing.Slug == group.Slug {
+			existing.Name, existing.Position = group.Name, group.Position
+			return nil
+		}
+	}
+	stored := *group
+	m.groups = append(m.groups, &stored)
+	return nil
+}
+
+// Events
+
+func (m *MemoryRepository) GetEventBySlug(ctx context.Context, slug string) (*domain.Event, error) {
+	return m.findEvent(func(event *domain.Event) bool { return event.Slug == slug })
+}
+
+func (m *MemoryRepository) GetEventByID(ctx context.Context, id string) (*domain.Event, error) {
+	return m.findEvent(func(event *domain.Event) bool { return event.ID == id })
+}
+
+func (m *MemoryRepository) GetDefaultEvent(ctx context.Context) (*domain.Event, error) {
+	return m.findEvent(func(event *domain.Event) bool { return event.IsDefault })
+}
+
+func (m *MemoryRepository) findEvent(match func(*domain.Event) bool) (*domain.Event, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, event := range m.events {
+		if match(event) {
+			copied := *event
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrEventNotFound
+}
+
+func (m *MemoryRepository) ListEvents(ctx context.Context) ([]*domain.Event, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var events []*domain.Event
+	for _, event := range m.events {
+		copied := *event
+		events = append(events, &copied)
+	}
+	slices.SortStableFunc(events, func(a, b *domain.Event) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return events, nil
+}
+
+func (m *MemoryRepository) UpdateEventWindow(ctx context.Context, eventID string, window *domain.VotingWindow) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	event, ok := m.events[eventID]
+	if !ok {
+		return domain.ErrEventNotFound
+	}
+	if window.OpensAt != nil && window.ClosesAt != nil && !window.OpensAt.Before(*window.ClosesAt) {
+		return errors.New("update event window: opens_at must be before closes_at")
+	}
+
+	now := memoryNow()
+	event.Window = domain.VotingWindow{
+		OpensAt:   window.OpensAt,
+		ClosesAt:  window.ClosesAt,
+		Paused:    window.Paused,
+		UpdatedAt: now,
+	}
+	event.UpdatedAt = now
+	window.UpdatedAt = now
+	return nil
+}
+
+func (m *MemoryRepository) ListGroups(ctx context.Context, eventID string) ([]*domain.Group, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var groups []*domain.Group
+	for _, group := range m.groups {
+		if group.EventID == eventID {
+			copied := *group
+			groups = append(groups, &copied)
+		}
+	}
+	slices.SortStableFunc(groups, func(a, b *domain.Group) int {
+		if a.Position != b.Position {
+			return a.Position - b.Position
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return groups, nil
+}
+
+// Innovations
+
+func (m *MemoryRepository) GetInnovationBySlug(ctx context.Context, eventID, groupSlug, slug string) (*domain.Innovation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, innovation := range m.innovations {
+		if innovation.EventID == eventID && innovation.GroupSlug == groupSlug && innovation.Slug == slug &&
+			innovation.ArchivedAt == nil {
+			copied := *innovation
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrInnovationNotFound
+}
+
+func (m *MemoryRepository) GetInnovationByID(ctx context.Context, id string) (*domain.Innovation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	innovation, ok := m.innovations[id]
+	if !ok {
+		return nil, domain.ErrInnovationNotFound
+	}
+	copied := *innovation
+	return &copied, nil
+}
+
+func (m *MemoryRepository) ListInnovations(ctx context.Context, eventID string) ([]*domain.Innovation, error) {
+	return m.listInnovations(eventID, false), nil
+}
+
+func (m *MemoryRepository) ListAllInnovations(ctx context.Context, eventID string) ([]*domain.Innovation, error) {
+	return m.listInnovations(eventID, true), nil
+}
+
+// listInnovations returns copies of the event's innovations ordered by
+// group, position and name
+func (m *MemoryRepository) listInnovations(eventID string, archived bool) []*domain.Innovation {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var innovations []*domain.Innovation
+	for _, innovation := range m.innovations {
+		if innovation.EventID == eventID && (archived || innovation.ArchivedAt == nil) {
+			copied := *innovation
+			innovations = append(innovations, &copied)
+		}
+	}
+	sortInnovations(innovations)
+	return innovations
+}
+
+func sortInnovations(innovations []*domain.Innovation) {
+	slices.SortFunc(innovations, func(a, b *domain.Innovation) int {
+		if c := strings.Compare(a.GroupSlug, b.GroupSlug); c != 0 {
+			return c
+		}
+		if a.Position != b.Position {
+			return a.Position - b.Position
+		}
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+}
+
+func (m *MemoryRepository) SlugExists(ctx context.Context, eventID, groupSlug, slug, excludeID string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.slugTaken(m.innovations, eventID, groupSlug, slug, excludeID), nil
+}
+
+func (m *MemoryRepository) slugTaken(innovations map[string]*domain.Innovation, eventID, groupSlug, slug, excludeID string) bool {
+	for _, innovation := range innovations {
+		if innovation.EventID == eventID && innovation.GroupSlug == groupSlug && innovation.Slug == slug &&
+			innovation.ID != excludeID {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MemoryRepository) CreateInnovation(ctx context.Context, innovation *domain.Innovation) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.insertInnovation(m.innovations, innovation)
+}
+
+func (m *MemoryRepository) UpdateInnovation(ctx context.Context, innovation *domain.Innovation) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.updateInnovation(m.innovations, innovation)
+}
+
+// insertInnovation adds innovation to the end of its group in innovations.
+// The caller holds the write lock.
+func (m *MemoryRepository) insertInnovation(innovations map[string]*domain.Innovation, innovation *domain.Innovation) error {
+	if !m.groupExists(innovation.EventID, innovation.GroupSlug) {
+		return fmt.Errorf("insert innovation: group %s does not exist in event %s", innovation.GroupSlug, innovation.EventID)
+	}
+	if m.slugTaken(innovations, innovation.EventID, innovation.GroupSlug, innovation.Slug, "") {
+		return domain.ErrSlugTaken
+	}
+
+	position := 0
+	for _, existing := range innovations {
+		if existing.EventID == innovation.EventID && existing.GroupSlug == innovation.GroupSlug {
+			position = max(position, existing.Position)
+		}
+	}
+
+	now := memoryNow()
+	innovation.ID = uuid.NewString()
+	innovation.Position = position + 1
+	innovation.ArchivedAt = nil
+	innovation.CreatedAt, innovation.UpdatedAt = now, now
+
+	stored := *innovation
+	innovations[stored.ID] = &stored
+	return nil
+}
+
+// updateInnovation saves the editable fields of innovation in innovations.
+// The caller holds the write lock.
+func (m *MemoryRepository) updateInnovation(innovations map[string]*domain.Innovation, innovation *domain.Innovation) error {
+	current, ok := innovations[innovation.ID]
+	if !ok {
+		return domain.ErrInnovationNotFound
+	}
+	if !m.groupExists(current.EventID, innovation.GroupSlug) {
+		return fmt.Errorf("update innovation: group %s does not exist in event %s", innovation.GroupSlug, current.EventID)
+	}
+	if m.slugTaken(innovations, current.EventID, innovation.GroupSlug, innovation.Slug, innovation.ID) {
+		return domain.ErrSlugTaken
+	}
+
+	stored := *current
+	stored.GroupSlug = innovation.GroupSlug
+	stored.Slug = innovation.Slug
+	stored.Name = innovation.Name
+	stored.Division = innovation.Division
+	stored.EntityName = innovation.EntityName
+	stored.PIC = innovation.PIC
+	stored.Description = innovation.Description
+	stored.LogoInnovationURL = innovation.LogoInnovationURL
+	stored.LogoEntityURL = innovation.LogoEntityURL
+	stored.VideoURL = innovation.VideoURL
+	stored.SlideURL = innovation.SlideURL
+	stored.IgURL = innovation.IgURL
+	stored.YtURL = innovation.YtURL
+	stored.HeroURL = innovation.HeroURL
+	stored.HeroMobileURL = innovation.HeroMobileURL
+	stored.UpdatedAt = memoryNow()
+	innovations[stored.ID] = &stored
+
+	innovation.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+func (m *MemoryRepository) groupExists(eventID, slug string) bool {
+	for _, group := range m.groups {
+		if group.EventID == eventID && group.Slug == slug {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *MemoryRepository) SetInnovationPositions(ctx context.Context, ids []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Check every ID first so a missing one changes nothing
+	for _, id := range ids {
+		if _, ok := m.innovations[id]; !ok {
+			return domain.ErrInnovationNotFound
+		}
+	}
+
+	now := memoryNow()
+	for i, id := range ids {
+		m.innovations[id].Position = i + 1
+		m.innovations[id].UpdatedAt = now
+	}
+	return nil
+}
+
+func (m *MemoryRepository) SetInnovationArchived(ctx context.Context, id string, archived bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	innovation, ok := m.innovations[id]
+	if !ok {
+		return domain.ErrInnovationNotFound
+	}
+
+	now := memoryNow()
+	switch {
+	case !archived:
+		innovation.ArchivedAt = nil
+	case innovation.ArchivedAt == nil:
+		innovation.ArchivedAt = &now
+	}
+	innovation.UpdatedAt = now
+	return nil
+}
+
+func (m *MemoryRepository) ImportInnovations(ctx context.Context, creates, updates []*domain.Innovation) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Work on a copy of the table and keep it only if every write succeeds
+	innovations := make(map[string]*domain.Innovation, len(m.innovations)+len(creates))
+	for id, innovation := range m.innovations {
+		innovations[id] = innovation
+	}
+
+	for _, innovation := range creates {
+		if err := m.insertInnovation(innovations, innovation); err != nil {
+			if errors.Is(err, domain.ErrSlugTaken) {
+				return fmt.Errorf("%w: %s/%s", domain.ErrSlugTaken, innovation.GroupSlug, innovation.Slug)
+			}
+			return err
+		}
+	}
+	for _, innovation := range updates {
+		if err := m.updateInnovation(innovations, innovation); err != nil {
+			if errors.Is(err, domain.ErrInnovationNotFound) {
+				return fmt.Errorf("%w: %s", domain.ErrInnovationNotFound, innovation.ID)
+			}
+			return err
+		}
+	}
+
+	m.innovations = innovations
+	return nil
+}
+
+// Votes
+
+func (m *MemoryRepository) InsertVote(ctx context.Context, vote *domain.Vote) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	innovation, ok := m.innovations[vote.InnovationID]
+	if !ok || innovation.EventID != vote.EventID {
+		return false, fmt.Errorf("insert vote: innovation %s does not exist in event %s", vote.InnovationID, vote.EventID)
+	}
+	if m.findVote(vote.EventID, vote.ScopeKey, vote.VoterKey) != nil {
+		// Already voted in the scope
+		return false, nil
+	}
+
+	m.insertVote(vote)
+	return true, nil
+}
+
+func (m *MemoryRepository) InsertVoteCapped(ctx context.Context, vote *domain.Vote, maxPerIP int) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	innovation, ok := m.innovations[vote.InnovationID]
+	if !ok || innovation.EventID != vote.EventID {
+		return false, fmt.Errorf("insert vote: innovation %s does not exist in event %s", vote.InnovationID, vote.EventID)
+	}
+	if m.findVote(vote.EventID, vote.ScopeKey, vote.VoterKey) != nil {
+		return false, nil
+	}
+
+	var ipVotes int
+	for _, existing := range m.votes {
+		if existing.EventID == vote.EventID && existing.ScopeKey == vote.ScopeKey &&
+			bytes.Equal(existing.VoterIPHash, vote.VoterIPHash) {
+			ipVotes++
+		}
+	}
+	if ipVotes >= maxPerIP {
+		return false, domain.ErrIPVoteCapReached
+	}
+
+	m.insertVote(vote)
+	return true, nil
+}
+
+// insertVote appends vote with the next ID. The caller holds the write lock.
+func (m *MemoryRepository) insertVote(vote *domain.Vote) {
+	m.nextVoteID++
+	stored := &memoryVote{Vote: *vote}
+	stored.ID = m.nextVoteID
+	stored.CreatedAt = memoryNow()
+	m.votes = append(m.votes, stored)
+}
+
+func (m *MemoryRepository) findVote(eventID, scopeKey string, voterKey []byte) *memoryVote {
+	for _, vote := range m.votes {
+		if vote.EventID == eventID && vote.ScopeKey == scopeKey && bytes.Equal(vote.VoterKey, voterKey) {
+			return vote
+		}
+	}
+	return nil
+}
+
+// countVotes counts the votes matching fn
+func (m *MemoryRepository) countVotes(fn func(*memoryVote) bool) int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var count int64
+	for _, vote := range m.votes {
+		if fn(vote) {
+			count++
+		}
+	}
+	return count
+}
+
+func (m *MemoryRepository) GetVoteCount(ctx context.Context, innovationID string) (int64, error) {
+	return m.countVotes(func(vote *memoryVote) bool {
+		return vote.InnovationID == innovationID && vote.invalidatedAt == nil
+	}), nil
+}
+
+func (m *MemoryRepository) HasVotedInScope(ctx context.Context, eventID, scopeKey string, voterKey []byte) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.findVote(eventID, scopeKey, voterKey) != nil, nil
+}
+
+func (m *MemoryRepository) CountRecentVotesByIP(ctx context.Context, eventID string, ipHash []byte, since time.Time) (int64, error) {
+	return m.countVotes(func(vote *memoryVote) bool {
+		return vote.EventID == eventID && bytes.Equal(vote.VoterIPHash, ipHash) && !vote.CreatedAt.Before(since)
+	}), nil
+}
+
+func (m *MemoryRepository) UseChallengeToken(ctx context.Context, tokenHash []byte, expiresAt time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := memoryNow()
+	for token, expiry := range m.usedChallenges {
+		if expiry.Before(now) {
+			delete(m.usedChallenges, token)
+		}
+	}
+	if _, ok := m.usedChallenges[string(tokenHash)]; ok {
+		return false, nil
+	}
+	m.usedChallenges[string(tokenHash)] = expiresAt
+	return true, nil
+}
+
+func (m *MemoryRepository) ReleaseChallengeToken(ctx context.Context, tokenHash []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.usedChallenges, string(tokenHash))
+	return nil
+}
+
+func (m *MemoryRepository) GetVotedInnovation(ctx context.Context, eventID, scopeKey string, voterKey []byte) (*domain.Innovation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	vote := m.findVote(eventID, scopeKey, voterKey)
+	if vote == nil {
+		return nil, domain.ErrInnovationNotFound
+	}
+	innovation, ok := m.innovations[vote.InnovationID]
+	if !ok {
+		return nil, domain.ErrInnovationNotFound
+	}
+	copied := *innovation
+	return &copied, nil
+}
+
+func (m *MemoryRepository) GetTotalVoters(ctx context.Context, eventID string) (int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.totalVoters(eventID), nil
+}
+
+// totalVoters counts the distinct voter keys of the event's valid votes. The
+// caller holds the lock.
+func (m *MemoryRepository) totalVoters(eventID string) int64 {
+	voters := make(map[string]bool)
+	for _, vote := range m.votes {
+		if vote.EventID == eventID && vote.invalidatedAt == nil {
+			voters[string(vote.VoterKey)] = true
+		}
+	}
+	return int64(len(voters))
+}
+
+func (m *MemoryRepository) GetEventResults(ctx context.Context, eventID string) (*domain.EventResults, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	counts := make(map[string]int64)
+	for _, vote := range m.votes {
+		if vote.EventID == eventID && vote.invalidatedAt == nil {
+			counts[vote.InnovationID]++
+		}
+	}
+
+	var innovations []*domain.Innovation
+	for _, innovation := range m.innovations {
+		if innovation.EventID == eventID && innovation.ArchivedAt == nil {
+			copied := *innovation
+			innovations = append(innovations, &copied)
+		}
+	}
+	sortInnovations(innovations)
+
+	results := &domain.EventResults{}
+	for _, innovation := range innovations {
+		results.Innovations = append(results.Innovations, &domain.InnovationResult{
+			Innovation: innovation,
+			VoteCount:  counts[innovation.ID],
+		})
+	}
+	// Like the SQL version, the voter count comes with the innovation rows
+	if len(innovations) > 0 {
+		results.TotalVoters = m.totalVoters(eventID)
+	}
+	return results, nil
+}
+
+func (m *MemoryRepository) GetVoteBuckets(ctx context.Context, eventID string, since, until time.Time, bucket time.Duration) ([]*domain.VoteBucket, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	type key struct {
+		innovationID string
+		start        time.Time
+	}
+	counts := make(map[key]int64)
+	for _, vote := range m.votes {
+		if vote.EventID != eventID || !vote.CreatedAt.Before(until) || vote.invalidatedAt != nil {
+			continue
+		}
+		// Votes before the range keep a zero start, like date_bin's NULL bucket
+		k := key{innovationID: vote.InnovationID}
+		if !vote.CreatedAt.Before(since) {
+			offset := vote.CreatedAt.Sub(since)
+			k.start = since.Add(offset - offset%bucket)
+		}
+		counts[k]++
+	}
+
+	buckets := make([]*domain.VoteBucket, 0, len(counts))
+	for k, count := range counts {
+		buckets = append(buckets, &domain.VoteBucket{InnovationID: k.innovationID, Start: k.start, Count: count})
+	}
+	slices.SortFunc(buckets, func(a, b *domain.VoteBucket) int {
+		if c := a.Start.Compare(b.Start); c != 0 {
+			return c
+		}
+		return strings.Compare(a.InnovationID, b.InnovationID)
+	})
+	return buckets, nil
+}
+
+func (m *MemoryRepository) StreamVotes(ctx context.Context, eventID string, fn func(*domain.VoteRecord) error) error {
+	// Copy the rows first so fn may call back into the repository
+	m.mu.RLock()
+	var records []*domain.VoteRecord
+	for _, vote := range m.votes {
+		innovation, ok := m.innovations[vote.InnovationID]
+		if vote.EventID != eventID || !ok {
+			continue
+		}
+		records = append(records, &domain.VoteRecord{
+			ID:             vote.ID,
+			InnovationID:   vote.InnovationID,
+			GroupSlug:      innovation.GroupSlug,
+			InnovationSlug: innovation.Slug,
+			InnovationName: innovation.Name,
+			CreatedAt:      vote.CreatedAt,
+			VoterIPHash:    vote.VoterIPHash,
+			UserAgent:      vote.UserAgent,
+			Invalidated:    vote.invalidatedAt != nil,
+		})
+	}
+	m.mu.RUnlock()
+
+	for _, record := range records {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Fraud review
+
+func (m *MemoryRepository) ListVoteSamples(ctx context.Context, eventID string, since time.Time) ([]*domain.VoteSample, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var samples []*domain.VoteSample
+	for _, vote := range m.votes {
+		if vote.EventID == eventID && !vote.CreatedAt.Before(since) {
+			samples = append(samples, &domain.VoteSample{
+				ID:           vote.ID,
+				InnovationID: vote.InnovationID,
+				CreatedAt:    vote.CreatedAt,
+				SubnetHash:   vote.SubnetHash,
+				UserAgent:    vote.UserAgent,
+			})
+		}
+	}
+	slices.SortStableFunc(samples, func(a, b *domain.VoteSample) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return int(a.ID - b.ID)
+	})
+	return samples, nil
+}
+
+func (m *MemoryRepository) InsertVoteFlags(ctx context.Context, flags []*domain.VoteFlag) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	added := 0
+	for _, flag := range flags {
+		if m.voteByID(flag.EventID, flag.VoteID) == nil {
+			return added, fmt.Errorf("insert vote flag: vote %d does not exist in event %s", flag.VoteID, flag.EventID)
+		}
+		exists := slices.ContainsFunc(m.flags, func(f *domain.VoteFlag) bool {
+			return f.VoteID == flag.VoteID && f.Rule == flag.Rule
+		})
+		if exists {
+			continue
+		}
+		stored := *flag
+		m.flags = append(m.flags, &stored)
+		added++
+	}
+	return added, nil
+}
+
+func (m *MemoryRepository) voteByID(eventID string, id int64) *memoryVote {
+	for _, vote := range m.votes {
+		if vote.ID == id && vote.EventID == eventID {
+			return vote
+		}
+	}
+	return nil
+}
+
+func (m *MemoryRepository) ListFlaggedVotes(ctx context.Context, filter domain.FlaggedVoteFilter) ([]*domain.FlaggedVote, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	flagsByVote := make(map[int64][]domain.FlagSummary)
+	for _, flag := range m.flags {
+		flagsByVote[flag.VoteID] = append(flagsByVote[flag.VoteID], domain.FlagSummary{Rule: flag.Rule, Detail: flag.Detail})
+	}
+
+	var votes []*domain.FlaggedVote
+	for i := len(m.votes) - 1; i >= 0 && len(votes) < filter.Limit; i-- {
+		vote := m.votes[i]
+		flags := flagsByVote[vote.ID]
+		innovation, ok := m.innovations[vote.InnovationID]
+		if vote.EventID != filter.EventID || len(flags) == 0 || !ok {
+			continue
+		}
+
+		switch filter.Status {
+		case domain.FlagStatusPending:
+			if vote.reviewedAt != nil {
+				continue
+			}
+		case domain.FlagStatusInvalidated:
+			if vote.invalidatedAt == nil {
+				continue
+			}
+		case domain.FlagStatusKept:
+			if vote.reviewedAt == nil || vote.invalidatedAt != nil {
+				continue
+			}
+		}
+		if filter.Rule != "" && !slices.ContainsFunc(flags, func(f domain.FlagSummary) bool { return f.Rule == filter.Rule }) {
+			continue
+		}
+		if filter.BeforeID > 0 && vote.ID >= filter.BeforeID {
+			continue
+		}
+
+		flags = slices.Clone(flags)
+		slices.SortFunc(flags, func(a, b domain.FlagSummary) int { return strings.Compare(a.Rule, b.Rule) })
+		votes = append(votes, &domain.FlaggedVote{
+			ID:                 vote.ID,
+			InnovationID:       vote.InnovationID,
+			GroupSlug:          innovation.GroupSlug,
+			InnovationName:     innovation.Name,
+			CreatedAt:          vote.CreatedAt,
+			UserAgent:          vote.UserAgent,
+			ShortIPHash:        domain.ShortHash(vote.VoterIPHash),
+			Flags:              flags,
+			ReviewedAt:         vote.reviewedAt,
+			InvalidatedAt:      vote.invalidatedAt,
+			InvalidationReason: vote.invalidationReason,
+		})
+	}
+	return votes, nil
+}
+
+func (m *MemoryRepository) ReviewVotes(ctx context.Context, eventID string, ids []int64, invalidate bool, reason string, at time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	changed := 0
+	for _, vote := range m.votes {
+		if vote.EventID != eventID || !slices.Contains(ids, vote.ID) {
+			continue
+		}
+		// Votes already reviewed into the requested state are left untouched
+		if vote.reviewedAt != nil && (vote.invalidatedAt != nil) == invalidate {
+			continue
+		}
+
+		reviewedAt := at
+		vote.reviewedAt = &reviewedAt
+		vote.invalidatedAt, vote.invalidationReason = nil, ""
+		if invalidate {
+			vote.invalidatedAt, vote.invalidationReason = &reviewedAt, reason
+		}
+		changed++
+	}
+	return changed, nil
+}
+
+// Admin accounts
+
+func (m *MemoryRepository) GetAdminUserByID(ctx context.Context, id string) (*domain.AdminUser, error) {
+	return m.findAdminUser(func(user *domain.AdminUser) bool { return user.ID == id })
+}
+
+func (m *MemoryRepository) GetAdminUserByUsername(ctx context.Context, username string) (*domain.AdminUser, error) {
+	return m.findAdminUser(func(user *domain.AdminUser) bool { return strings.EqualFold(user.Username, username) })
+}
+
+func (m *MemoryRepository) findAdminUser(match func(*domain.AdminUser) bool) (*domain.AdminUser, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, user := range m.adminUsers {
+		if match(user) {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrAdminUserNotFound
+}
+
+func (m *MemoryRepository) CreateAdminUser(ctx context.Context, user *domain.AdminUser) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !user.Role.IsValid() {
+		return fmt.Errorf("insert admin user: invalid role %q", user.Role)
+	}
+	for _, existing := range m.adminUsers {
+		if strings.EqualFold(existing.Username, user.Username) {
+			return domain.ErrUsernameTaken
+		}
+	}
+
+	now := memoryNow()
+	user.ID = uuid.NewString()
+	user.CreatedAt, user.UpdatedAt = now, now
+	m.adminUsers[user.ID] = &domain.AdminUser{
+		ID:           user.ID,
+		Username:     user.Username,
+		PasswordHash: user.PasswordHash,
+		Role:         user.Role,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	return nil
+}
+
+func (m *MemoryRepository) ListAdminUsers(ctx context.Context) ([]*domain.AdminUser, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var users []*domain.AdminUser
+	for _, user := range m.adminUsers {
+		copied := *user
+		users = append(users, &copied)
+	}
+	slices.SortFunc(users, func(a, b *domain.AdminUser) int { return strings.Compare(a.Username, b.Username) })
+	return users, nil
+}
+
+func (m *MemoryRepository) UpdateAdminUser(ctx context.Context, user *domain.AdminUser) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.adminUsers[user.ID]
+	if !ok {
+		return domain.ErrAdminUserNotFound
+	}
+	if !user.Role.IsValid() {
+		return fmt.Errorf("update admin user: invalid role %q", user.Role)
+	}
+
+	stored.PasswordHash = user.PasswordHash
+	stored.Role = user.Role
+	stored.DisabledAt = user.DisabledAt
+	stored.UpdatedAt = memoryNow()
+	user.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+func (m *MemoryRepository) RecordAdminLogin(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if user, ok := m.adminUsers[id]; ok {
+		now := memoryNow()
+		user.LastLoginAt = &now
+	}
+	return nil
+}
+
+func (m *MemoryRepository) CountActiveSuperadmins(ctx context.Context) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	count := 0
+	for _, user := range m.adminUsers {
+		if user.Role == domain.AdminRoleSuperadmin && user.DisabledAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Admin sessions
+
+func (m *MemoryRepository) CreateAdminSession(ctx context.Context, session *domain.AdminSession) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.adminUsers[session.UserID]; !ok {
+		return fmt.Errorf("insert admin session: admin user %s does not exist", session.UserID)
+	}
+	for _, existing := range m.adminSessions {
+		if bytes.Equal(existing.TokenHash, session.TokenHash) {
+			return errors.New("insert admin session: token hash already in use")
+		}
+	}
+
+	session.ID = uuid.NewString()
+	m.adminSessions[session.ID] = &domain.AdminSession{
+		ID:         session.ID,
+		TokenHash:  session.TokenHash,
+		UserID:     session.UserID,
+		UserAgent:  session.UserAgent,
+		CreatedAt:  session.CreatedAt,
+		LastSeenAt: session.CreatedAt,
+		ExpiresAt:  session.ExpiresAt,
+	}
+	return nil
+}
+
+// joinSession copies session with the username and role of its user. The
+// caller holds the lock.
+func (m *MemoryRepository) joinSession(session *domain.AdminSession) *domain.AdminSession {
+	copied := *session
+	if user, ok := m.adminUsers[session.UserID]; ok {
+		copied.Username, copied.Role = user.Username, user.Role
+	}
+	return &copied
+}
+
+func (m *MemoryRepository) GetAdminSession(ctx context.Context, tokenHash []byte) (*domain.AdminSession, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, session := range m.adminSessions {
+		if bytes.Equal(session.TokenHash, tokenHash) {
+			return m.joinSession(session), nil
+		}
+	}
+	return nil, domain.ErrSessionNotFound
+}
+
+func (m *MemoryRepository) TouchAdminSession(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if session, ok := m.adminSessions[id]; ok {
+		session.LastSeenAt = memoryNow()
+	}
+	return nil
+}
+
+func (m *MemoryRepository) ListActiveAdminSessions(ctx context.Context) ([]*domain.AdminSession, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now()
+	var sessions []*domain.AdminSession
+	for _, session := range m.adminSessions {
+		if session.RevokedAt == nil && session.ExpiresAt.After(now) {
+			sessions = append(sessions, m.joinSession(session))
+		}
+	}
+	slices.SortFunc(sessions, func(a, b *domain.AdminSession) int { return b.LastSeenAt.Compare(a.LastSeenAt) })
+	return sessions, nil
+}
+
+func (m *MemoryRepository) RevokeAdminSession(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, ok := m.adminSessions[id]
+	if !ok || session.RevokedAt != nil {
+		return domain.ErrSessionNotFound
+	}
+	now := memoryNow()
+	session.RevokedAt = &now
+	return nil
+}
+
+func (m *MemoryRepository) RevokeUserSessions(ctx context.Context, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := memoryNow()
+	for _, session := range m.adminSessions {
+		if session.UserID == userID && session.RevokedAt == nil {
+			session.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+// Audit log
+
+func (m *MemoryRepository) InsertAuditEvent(ctx context.Context, event *domain.AuditEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nextAuditID++
+	event.ID = m.nextAuditID
+	stored := *event
+	m.auditEvents = append(m.auditEvents, &stored)
+	return nil
+}
+
+func (m *MemoryRepository) ListAuditEvents(ctx context.Context, filter domain.AuditFilter) ([]*domain.AuditEvent, error) {
+	var events []*domain.AuditEvent
+	err := m.StreamAuditEvents(ctx, filter, func(event *domain.AuditEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// StreamAuditEvents calls fn for each matching event, newest first
+func (m *MemoryRepository) StreamAuditEvents(ctx context.Context, filter domain.AuditFilter, fn func(*domain.AuditEvent) error) error {
+	m.mu.RLock()
+	var events []*domain.AuditEvent
+	for i := len(m.auditEvents) - 1; i >= 0; i-- {
+		if filter.Limit > 0 && len(events) == filter.Limit {
+			break
+		}
+		event := m.auditEvents[i]
+		if auditEventMatches(event, filter) {
+			copied := *event
+			events = append(events, &copied)
+		}
+	}
+	m.mu.RUnlock()
+
+	for _, event := range events {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// auditEventMatches applies the conditions of auditFilterClause to event
+func auditEventMatches(event *domain.AuditEvent, filter domain.AuditFilter) bool {
+	switch {
+	case filter.Action != "" && event.Action != filter.Action,
+		filter.Actor != "" && event.Actor != filter.Actor,
+		filter.TargetType != "" && event.TargetType != filter.TargetType,
+		filter.TargetID != "" && event.TargetID != filter.TargetID,
+		filter.Since != nil && event.OccurredAt.Before(*filter.Since),
+		filter.Until != nil && !event.OccurredAt.Before(*filter.Until),
+		filter.BeforeID > 0 && event.ID >= filter.BeforeID:
+		return false
+	}
+	return true
+}
+
+// Voter verification
+
+func (m *MemoryRepository) CreateVerificationCode(ctx context.Context, code *domain.VerificationCode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.events[code.EventID]; !ok {
+		return fmt.Errorf("insert voter code: %w", domain.ErrEventNotFound)
+	}
+	code.ID = uuid.NewString()
+	stored := *code
+	stored.Attempts, stored.UsedAt = 0, nil
+	m.voterCodes[stored.ID] = &stored
+	return nil
+}
+
+func (m *MemoryRepository) GetLatestVerificationCode(ctx context.Context, eventID string, contactHash []byte) (*domain.VerificationCode, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var latest *domain.VerificationCode
+	for _, code := range m.voterCodes {
+		if code.EventID == eventID && bytes.Equal(code.ContactHash, contactHash) &&
+			(latest == nil || code.CreatedAt.After(latest.CreatedAt)) {
+			latest = code
+		}
+	}
+	if latest == nil {
+		return nil, domain.ErrInvalidCode
+	}
+	copied := *latest
+	return &copied, nil
+}
+
+func (m *MemoryRepository) CountVerificationCodes(ctx context.Context, eventID string, contactHash []byte, since time.Time) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	count := 0
+	for _, code := range m.voterCodes {
+		if code.EventID == eventID && bytes.Equal(code.ContactHash, contactHash) && !code.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MemoryRepository) RecordVerificationAttempt(ctx context.Context, id string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	code, ok := m.voterCodes[id]
+	if !ok {
+		return 0, domain.ErrInvalidCode
+	}
+	code.Attempts++
+	return code.Attempts, nil
+}
+
+func (m *MemoryRepository) UseVerificationCode(ctx context.Context, id string, at time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	code, ok := m.voterCodes[id]
+	if !ok || code.UsedAt != nil {
+		return false, nil
+	}
+	usedAt := at
+	code.UsedAt = &usedAt
+	return true, nil
+}
+
+func (m *MemoryRepository) CreateVoterSession(ctx context.Context, session *domain.VoterSession) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.events[session.EventID]; !ok {
+		return fmt.Errorf("insert voter session: %w", domain.ErrEventNotFound)
+	}
+	if _, ok := m.voterSessions[string(session.TokenHash)]; ok {
+		return errors.New("insert voter session: token hash already in use")
+	}
+	stored := *session
+	m.voterSessions[string(session.TokenHash)] = &stored
+	return nil
+}
+
+func (m *MemoryRepository) GetVoterSession(ctx context.Context, tokenHash []byte) (*domain.VoterSession, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	session, ok := m.voterSessions[string(tokenHash)]
+	if !ok {
+		return nil, domain.ErrVerificationRequired
+	}
+	copied := *session
+	return &copied, nil
+}
+
+// The memory store satisfies every repository interface
+var (
+	_ domain.Repository             = (*MemoryRepository)(nil)
+	_ domain.AuditRepository        = (*MemoryRepository)(nil)
+	_ domain.AdminUserRepository    = (*MemoryRepository)(nil)
+	_ domain.SessionRepository      = (*MemoryRepository)(nil)
+	_ domain.VerificationRepository = (*MemoryRepository)(nil)
+	_ domain.ChallengeRepository    = (*MemoryRepository)(nil)
+	_ domain.FraudRepository        = (*MemoryRepository)(nil)
+	_ domain.ImportRepository       = (*MemoryRepository)(nil)
+)
@@ -0,0 +1,82 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"voteweb/internal/domain"
+)
+
+// newTestMemoryRepository creates a store with an open event holding
+// conformanceGroups
+func newTestMemoryRepository(t *testing.T) (*MemoryRepository, *domain.Event) {
+	t.Helper()
+	store := NewMemoryRepository()
+	ctx := context.Background()
+
+	event := &domain.Event{Slug: "conformance", Name: "Conformance"}
+	if err := store.AddEvent(ctx, event); err != nil {
+		t.Fatalf("AddEvent() error = %v", err)
+	}
+	for i, slug := range conformanceGroups {
+		if err := store.AddGroup(ctx, &domain.Group{EventID: event.ID, Slug: slug, Name: slug, Position: i + 1}); err != nil {
+			t.Fatalf("AddGroup(%s) error = %v", slug, err)
+		}
+	}
+	return store, event
+}
+
+func TestMemoryRepository_Conformance(t *testing.T) {
+	runRepositoryConformance(t, func(t *testing.T) (domain.Repository, *domain.Event) {
+		return newTestMemoryRepository(t)
+	})
+}
+
+func TestMemoryRepository_ImportInnovationsIsAtomic(t *testing.T) {
+	store, event := newTestMemoryRepository(t)
+	ctx := context.Background()
+
+	existing := &domain.Innovation{EventID: event.ID, GroupSlug: "digital", Slug: "smart-meter", Name: "Smart Meter"}
+	if err := store.CreateInnovation(ctx, existing); err != nil {
+		t.Fatalf("CreateInnovation() error = %v", err)
+	}
+
+	creates := []*domain.Innovation{
+		{EventID: event.ID, GroupSlug: "digital", Slug: "sapa-warga", Name: "Sapa Warga"},
+		{EventID: event.ID, GroupSlug: "digital", Slug: "smart-meter", Name: "Smart Meter 2"},
+	}
+	updated := *existing
+	updated.Name = "Smart Meter Renamed"
+	err := store.ImportInnovations(ctx, creates, []*domain.Innovation{&updated})
+	if !errors.Is(err, domain.ErrSlugTaken) {
+		t.Fatalf("ImportInnovations() error = %v, want ErrSlugTaken", err)
+	}
+
+	innovations, _ := store.ListAllInnovations(ctx, event.ID)
+	if len(innovations) != 1 || innovations[0].Name != "Smart Meter" {
+		t.Errorf("innovations = %+v, want only the untouched smart meter", innovations)
+	}
+}
+
+func TestMemoryRepository_ReturnsCopies(t *testing.T) {
+	store, event := newTestMemoryRepository(t)
+	ctx := context.Background()
+
+	innovation := &domain.Innovation{EventID: event.ID, GroupSlug: "digital", Slug: "smart-meter", Name: "Smart Meter"}
+	if err := store.CreateInnovation(ctx, innovation); err != nil {
+		t.Fatalf("CreateInnovation() error = %v", err)
+	}
+	innovation.Name = "Changed by the caller"
+
+	got, err := store.GetInnovationByID(ctx, innovation.ID)
+	if err != nil {
+		t.Fatalf("GetInnovationByID() error = %v", err)
+	}
+	got.Name = "Changed again"
+
+	stored, _ := store.GetInnovationBySlug(ctx, event.ID, "digital", "smart-meter")
+	if stored.Name != "Smart Meter" {
+		t.Errorf("stored name = %q, want the store unaffected by callers", stored.Name)
+	}
+}
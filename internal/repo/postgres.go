@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"voteweb/internal/domain"
@@ -20,18 +22,42 @@ func NewPostgresRepository(pool *pgxpool.Pool) domain.Repository {
 	return &postgresRepository{pool: pool}
 }
 
-func (r *postgresRepository) GetInnovationBySlug(ctx context.Context, groupSlug, slug string) (*domain.Innovation, error) {
-	query := `
-		SELECT id, group_slug, slug, name, division, entity_name, pic, description,
+const eventColumns = `id, slug, name, is_default, vote_policy, voter_verification, opens_at, closes_at, paused, created_at, updated_at`
+
+// scanEvent scans a row selected with eventColumns
+func scanEvent(row pgx.Row) (*domain.Event, error) {
+	var event domain.Event
+	err := row.Scan(
+		&event.ID,
+		&event.Slug,
+		&event.Name,
+		&event.IsDefault,
+		&event.VotePolicy,
+		&event.VoterVerification,
+		&event.Window.OpensAt,
+		&event.Window.ClosesAt,
+		&event.Window.Paused,
+		&event.CreatedAt,
+		&event.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	event.Window.UpdatedAt = event.UpdatedAt
+	return &event, nil
+}
+
+const innovationColumns = `id, event_id, group_slug, slug, name, division, entity_name, pic, description,
 		       logo_innovation_url, logo_entity_url, video_url, slide_url, ig_url, yt_url,
-		       created_at, updated_at
-		FROM innovations
-		WHERE group_slug = $1 AND slug = $2
-	`
+		       hero_url, hero_mobile_url, position, archived_at, created_at, updated_at`
 
+// scanInnovation scans a row selected with innovationColumns. Columns selected
+// after them are scanned into extra.
+func scanInnovation(row pgx.Row, extra ...any) (*domain.Innovation, error) {
 	var innovation domain.Innovation
-	err := r.pool.QueryRow(ctx, query, groupSlug, slug).Scan(
+	dest := []any{
 		&innovation.ID,
+		&innovation.EventID,
 		&innovation.GroupSlug,
 		&innovation.Slug,
 		&innovation.Name,
@@ -45,10 +71,127 @@ func (r *postgresRepository) GetInnovationBySlug(ctx context.Context, groupSlug,
 		&innovation.SlideURL,
 		&innovation.IgURL,
 		&innovation.YtURL,
+		&innovation.HeroURL,
+		&innovation.HeroMobileURL,
+		&innovation.Position,
+		&innovation.ArchivedAt,
 		&innovation.CreatedAt,
 		&innovation.UpdatedAt,
-	)
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return &innovation, nil
+}
+
+func (r *postgresRepository) getEvent(ctx context.Context, where string, args ...any) (*domain.Event, error) {
+	query := `SELECT ` + eventColumns + ` FROM events WHERE ` + where
+
+	event, err := scanEvent(r.pool.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrEventNotFound
+		}
+		return nil, fmt.Errorf("query event: %w", err)
+	}
+
+	return event, nil
+}
+
+func (r *postgresRepository) GetEventBySlug(ctx context.Context, slug string) (*domain.Event, error) {
+	return r.getEvent(ctx, `slug = $1`, slug)
+}
+
+func (r *postgresRepository) GetEventByID(ctx context.Context, id string) (*domain.Event, error) {
+	return r.getEvent(ctx, `id = $1`, id)
+}
+
+func (r *postgresRepository) GetDefaultEvent(ctx context.Context) (*domain.Event, error) {
+	return r.getEvent(ctx, `is_default`)
+}
+
+func (r *postgresRepository) ListEvents(ctx context.Context) ([]*domain.Event, error) {
+	query := `SELECT ` + eventColumns + ` FROM events ORDER BY created_at DESC`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*domain.Event
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return events, nil
+}
+
+func (r *postgresRepository) UpdateEventWindow(ctx context.Context, eventID string, window *domain.VotingWindow) error {
+	query := `
+		UPDATE events
+		SET opens_at = $2, closes_at = $3, paused = $4, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err := r.pool.QueryRow(ctx, query, eventID, window.OpensAt, window.ClosesAt, window.Paused).Scan(&window.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrEventNotFound
+		}
+		return fmt.Errorf("update event window: %w", err)
+	}
+
+	return nil
+}
+
+func (r *postgresRepository) ListGroups(ctx context.Context, eventID string) ([]*domain.Group, error) {
+	query := `
+		SELECT event_id, slug, name, position
+		FROM event_groups
+		WHERE event_id = $1
+		ORDER BY position, name
+	`
+
+	rows, err := r.pool.Query(ctx, query, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("query groups: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []*domain.Group
+	for rows.Next() {
+		var group domain.Group
+		if err := rows.Scan(&group.EventID, &group.Slug, &group.Name, &group.Position); err != nil {
+			return nil, fmt.Errorf("scan group: %w", err)
+		}
+		groups = append(groups, &group)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return groups, nil
+}
+
+func (r *postgresRepository) GetInnovationBySlug(ctx context.Context, eventID, groupSlug, slug string) (*domain.Innovation, error) {
+	query := `
+		SELECT ` + innovationColumns + `
+		FROM innovations
+		WHERE event_id = $1 AND group_slug = $2 AND slug = $3 AND archived_at IS NULL
+	`
 
+	innovation, err := scanInnovation(r.pool.QueryRow(ctx, query, eventID, groupSlug, slug))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrInnovationNotFound
@@ -56,23 +199,78 @@ func (r *postgresRepository) GetInnovationBySlug(ctx context.Context, groupSlug,
 		return nil, fmt.Errorf("query innovation: %w", err)
 	}
 
-	return &innovation, nil
+	return innovation, nil
 }
 
+// insertVoteQuery inserts a vote unless the voter already voted in the scope,
+// in which case it returns no row
+const insertVoteQuery = `
+	INSERT INTO votes (event_id, innovation_id, scope_key, voter_key, voter_ip_hash, voter_token_hash, voter_subnet_hash, user_agent, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+	ON CONFLICT (event_id, scope_key, voter_key) DO NOTHING
+	RETURNING id
+`
+
+// voteCapLockClass is the first key of the advisory locks that serialise
+// capped votes per (event, scope, IP hash); "vote" in ASCII
+const voteCapLockClass int32 = 0x766f7465
+
 func (r *postgresRepository) InsertVote(ctx context.Context, vote *domain.Vote) (bool, error) {
-	query := `
-		INSERT INTO votes (innovation_id, voter_ip_hash, user_agent, created_at)
-		VALUES ($1, $2, $3, NOW())
-		ON CONFLICT (voter_ip_hash) DO NOTHING
-		RETURNING id
-	`
+	return insertVote(ctx, r.pool, vote)
+}
+
+func (r *postgresRepository) InsertVoteCapped(ctx context.Context, vote *domain.Vote, maxPerIP int) (bool, error) {
+	var inserted bool
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		// Concurrent votes from the same IP hash wait here until this
+		// transaction commits, so the count below includes their votes
+		_, err := tx.Exec(ctx,
+			`SELECT pg_advisory_xact_lock($1, hashtext($2::text || ':' || $3 || ':' || encode($4, 'hex')))`,
+			voteCapLockClass, vote.EventID, vote.ScopeKey, vote.VoterIPHash)
+		if err != nil {
+			return fmt.Errorf("lock ip votes: %w", err)
+		}
+
+		var voted bool
+		var ipVotes int64
+		err = tx.QueryRow(ctx, `
+			SELECT
+				EXISTS(SELECT 1 FROM votes WHERE event_id = $1 AND scope_key = $2 AND voter_key = $3),
+				(SELECT COUNT(*) FROM votes WHERE event_id = $1 AND scope_key = $2 AND voter_ip_hash = $4)
+		`, vote.EventID, vote.ScopeKey, vote.VoterKey, vote.VoterIPHash).Scan(&voted, &ipVotes)
+		if err != nil {
+			return fmt.Errorf("count scope votes by ip: %w", err)
+		}
+		if voted {
+			return nil
+		}
+		if ipVotes >= int64(maxPerIP) {
+			return domain.ErrIPVoteCapReached
+		}
 
+		inserted, err = insertVote(ctx, tx, vote)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return inserted, nil
+}
+
+// rowQuerier is a pool or a transaction
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// insertVote runs insertVoteQuery on db
+func insertVote(ctx context.Context, db rowQuerier, vote *domain.Vote) (bool, error) {
 	var id int64
-	err := r.pool.QueryRow(ctx, query, vote.InnovationID, vote.VoterIPHash, vote.UserAgent).Scan(&id)
+	err := db.QueryRow(ctx, insertVoteQuery, vote.EventID, vote.InnovationID, vote.ScopeKey, vote.VoterKey, vote.VoterIPHash, vote.VoterTokenHash, vote.SubnetHash, vote.UserAgent).Scan(&id)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			// No rows returned means conflict occurred - this IP has already voted
+			// No rows returned means conflict occurred - this voter has already voted in the scope
 			return false, nil
 		}
 		return false, fmt.Errorf("insert vote: %w", err)
@@ -83,7 +281,7 @@ func (r *postgresRepository) InsertVote(ctx context.Context, vote *domain.Vote)
 }
 
 func (r *postgresRepository) GetVoteCount(ctx context.Context, innovationID string) (int64, error) {
-	query := `SELECT COUNT(*) FROM votes WHERE innovation_id = $1`
+	query := `SELECT COUNT(*) FROM votes WHERE innovation_id = $1 AND invalidated_at IS NULL`
 
 	var count int64
 	err := r.pool.QueryRow(ctx, query, innovationID).Scan(&count)
@@ -94,16 +292,30 @@ func (r *postgresRepository) GetVoteCount(ctx context.Context, innovationID stri
 	return count, nil
 }
 
-func (r *postgresRepository) ListInnovations(ctx context.Context) ([]*domain.Innovation, error) {
+func (r *postgresRepository) ListInnovations(ctx context.Context, eventID string) ([]*domain.Innovation, error) {
 	query := `
-		SELECT id, group_slug, slug, name, division, entity_name, pic, description,
-		       logo_innovation_url, logo_entity_url, video_url, slide_url, ig_url, yt_url,
-		       created_at, updated_at
+		SELECT ` + innovationColumns + `
 		FROM innovations
-		ORDER BY group_slug, name
+		WHERE event_id = $1 AND archived_at IS NULL
+		ORDER BY group_slug, position, name
 	`
 
-	rows, err := r.pool.Query(ctx, query)
+	return r.queryInnovations(ctx, query, eventID)
+}
+
+func (r *postgresRepository) ListAllInnovations(ctx context.Context, eventID string) ([]*domain.Innovation, error) {
+	query := `
+		SELECT ` + innovationColumns + `
+		FROM innovations
+		WHERE event_id = $1
+		ORDER BY group_slug, position, name
+	`
+
+	return r.queryInnovations(ctx, query, eventID)
+}
+
+func (r *postgresRepository) queryInnovations(ctx context.Context, query string, args ...any) ([]*domain.Innovation, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query innovations: %w", err)
 	}
@@ -111,29 +323,11 @@ func (r *postgresRepository) ListInnovations(ctx context.Context) ([]*domain.Inn
 
 	var innovations []*domain.Innovation
 	for rows.Next() {
-		var innovation domain.Innovation
-		err := rows.Scan(
-			&innovation.ID,
-			&innovation.GroupSlug,
-			&innovation.Slug,
-			&innovation.Name,
-			&innovation.Division,
-			&innovation.EntityName,
-			&innovation.PIC,
-			&innovation.Description,
-			&innovation.LogoInnovationURL,
-			&innovation.LogoEntityURL,
-			&innovation.VideoURL,
-			&innovation.SlideURL,
-			&innovation.IgURL,
-			&innovation.YtURL,
-			&innovation.CreatedAt,
-			&innovation.UpdatedAt,
-		)
+		innovation, err := scanInnovation(rows)
 		if err != nil {
 			return nil, fmt.Errorf("scan innovation: %w", err)
 		}
-		innovations = append(innovations, &innovation)
+		innovations = append(innovations, innovation)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -143,28 +337,164 @@ func (r *postgresRepository) ListInnovations(ctx context.Context) ([]*domain.Inn
 	return innovations, nil
 }
 
-func (r *postgresRepository) HasVoted(ctx context.Context, innovationID string, voterIPHash []byte) (bool, error) {
+func (r *postgresRepository) GetInnovationByID(ctx context.Context, id string) (*domain.Innovation, error) {
+	query := `SELECT ` + innovationColumns + ` FROM innovations WHERE id = $1`
+
+	innovation, err := scanInnovation(r.pool.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || isInvalidID(err) {
+			return nil, domain.ErrInnovationNotFound
+		}
+		return nil, fmt.Errorf("query innovation: %w", err)
+	}
+
+	return innovation, nil
+}
+
+func (r *postgresRepository) SlugExists(ctx context.Context, eventID, groupSlug, slug, excludeID string) (bool, error) {
 	query := `
 		SELECT EXISTS(
-			SELECT 1 FROM votes 
-			WHERE innovation_id = $1 AND voter_ip_hash = $2
+			SELECT 1 FROM innovations
+			WHERE event_id = $1 AND group_slug = $2 AND slug = $3
+			  AND ($4 = '' OR id::text <> $4)
 		)
 	`
 
 	var exists bool
-	err := r.pool.QueryRow(ctx, query, innovationID, voterIPHash).Scan(&exists)
+	err := r.pool.QueryRow(ctx, query, eventID, groupSlug, slug, excludeID).Scan(&exists)
 	if err != nil {
-		return false, fmt.Errorf("check vote: %w", err)
+		return false, fmt.Errorf("check slug: %w", err)
 	}
 
 	return exists, nil
 }
 
-func (r *postgresRepository) GetTotalVoters(ctx context.Context) (int64, error) {
-	query := `SELECT COUNT(DISTINCT voter_ip_hash) FROM votes`
+// insertInnovationQuery appends a new innovation to the end of its group
+const insertInnovationQuery = `
+	INSERT INTO innovations (
+		event_id, group_slug, slug, name, division, entity_name, pic, description,
+		logo_innovation_url, logo_entity_url, video_url, slide_url, ig_url, yt_url,
+		hero_url, hero_mobile_url, position
+	)
+	VALUES (
+		$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16,
+		(SELECT COALESCE(MAX(position), 0) + 1 FROM innovations WHERE event_id = $1 AND group_slug = $2)
+	)
+	RETURNING id, position, created_at, updated_at
+`
+
+const updateInnovationQuery = `
+	UPDATE innovations
+	SET group_slug = $2, slug = $3, name = $4, division = $5, entity_name = $6, pic = $7,
+	    description = $8, logo_innovation_url = $9, logo_entity_url = $10, video_url = $11,
+	    slide_url = $12, ig_url = $13, yt_url = $14, hero_url = $15, hero_mobile_url = $16,
+	    updated_at = NOW()
+	WHERE id = $1
+	RETURNING updated_at
+`
+
+func (r *postgresRepository) CreateInnovation(ctx context.Context, innovation *domain.Innovation) error {
+	err := r.pool.QueryRow(ctx, insertInnovationQuery,
+		innovation.EventID, innovation.GroupSlug, innovation.Slug, innovation.Name,
+		innovation.Division, innovation.EntityName, innovation.PIC, innovation.Description,
+		innovation.LogoInnovationURL, innovation.LogoEntityURL, innovation.VideoURL, innovation.SlideURL,
+		innovation.IgURL, innovation.YtURL, innovation.HeroURL, innovation.HeroMobileURL,
+	).Scan(&innovation.ID, &innovation.Position, &innovation.CreatedAt, &innovation.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrSlugTaken
+		}
+		return fmt.Errorf("insert innovation: %w", err)
+	}
+
+	return nil
+}
+
+func (r *postgresRepository) UpdateInnovation(ctx context.Context, innovation *domain.Innovation) error {
+	err := r.pool.QueryRow(ctx, updateInnovationQuery,
+		innovation.ID, innovation.GroupSlug, innovation.Slug, innovation.Name,
+		innovation.Division, innovation.EntityName, innovation.PIC, innovation.Description,
+		innovation.LogoInnovationURL, innovation.LogoEntityURL, innovation.VideoURL, innovation.SlideURL,
+		innovation.IgURL, innovation.YtURL, innovation.HeroURL, innovation.HeroMobileURL,
+	).Scan(&innovation.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) || isInvalidID(err) {
+			return domain.ErrInnovationNotFound
+		}
+		if isUniqueViolation(err) {
+			return domain.ErrSlugTaken
+		}
+		return fmt.Errorf("update innovation: %w", err)
+	}
+
+	return nil
+}
+
+func (r *postgresRepository) SetInnovationPositions(ctx context.Context, ids []string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for i, id := range ids {
+		tag, err := tx.Exec(ctx, `UPDATE innovations SET position = $2, updated_at = NOW() WHERE id = $1`, id, i+1)
+		if err != nil {
+			if isInvalidID(err) {
+				return domain.ErrInnovationNotFound
+			}
+			return fmt.Errorf("update innovation position: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return domain.ErrInnovationNotFound
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *postgresRepository) SetInnovationArchived(ctx context.Context, id string, archived bool) error {
+	query := `
+		UPDATE innovations
+		SET archived_at = CASE WHEN $2 THEN COALESCE(archived_at, NOW()) END, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	tag, err := r.pool.Exec(ctx, query, id, archived)
+	if err != nil {
+		if isInvalidID(err) {
+			return domain.ErrInnovationNotFound
+		}
+		return fmt.Errorf("update innovation archive state: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrInnovationNotFound
+	}
+
+	return nil
+}
+
+// isUniqueViolation reports whether err is a PostgreSQL unique_violation
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+// isInvalidID reports whether err comes from an id that is not a valid UUID
+func isInvalidID(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "22P02"
+}
+
+func (r *postgresRepository) GetTotalVoters(ctx context.Context, eventID string) (int64, error) {
+	query := `SELECT COUNT(DISTINCT voter_key) FROM votes WHERE event_id = $1 AND invalidated_at IS NULL`
 
 	var count int64
-	err := r.pool.QueryRow(ctx, query).Scan(&count)
+	err := r.pool.QueryRow(ctx, query, eventID).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("count total voters: %w", err)
 	}
@@ -172,49 +502,147 @@ func (r *postgresRepository) GetTotalVoters(ctx context.Context) (int64, error)
 	return count, nil
 }
 
-func (r *postgresRepository) HasVotedGlobally(ctx context.Context, voterIPHash []byte) (bool, error) {
-	query := `SELECT EXISTS(SELECT 1 FROM votes WHERE voter_ip_hash = $1)`
+func (r *postgresRepository) GetEventResults(ctx context.Context, eventID string) (*domain.EventResults, error) {
+	// One pass over votes: counts per innovation joined onto the active
+	// innovations, with the event's distinct voters computed once
+	query := `
+		SELECT ` + innovationColumns + `,
+		       COALESCE(v.vote_count, 0),
+		       (SELECT COUNT(DISTINCT voter_key) FROM votes WHERE event_id = $1 AND invalidated_at IS NULL)
+		FROM innovations
+		LEFT JOIN (
+			SELECT innovation_id, COUNT(*) AS vote_count
+			FROM votes
+			WHERE event_id = $1 AND invalidated_at IS NULL
+			GROUP BY innovation_id
+		) v ON v.innovation_id = innovations.id
+		WHERE innovations.event_id = $1 AND innovations.archived_at IS NULL
+		ORDER BY group_slug, position, name
+	`
 
-	var exists bool
-	err := r.pool.QueryRow(ctx, query, voterIPHash).Scan(&exists)
+	rows, err := r.pool.Query(ctx, query, eventID)
 	if err != nil {
-		return false, fmt.Errorf("check global vote: %w", err)
+		return nil, fmt.Errorf("query results: %w", err)
 	}
+	defer rows.Close()
 
-	return exists, nil
+	results := &domain.EventResults{}
+	for rows.Next() {
+		var result domain.InnovationResult
+		innovation, err := scanInnovation(rows, &result.VoteCount, &results.TotalVoters)
+		if err != nil {
+			return nil, fmt.Errorf("scan result: %w", err)
+		}
+		result.Innovation = innovation
+		results.Innovations = append(results.Innovations, &result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return results, nil
 }
 
-func (r *postgresRepository) GetVotedInnovation(ctx context.Context, voterIPHash []byte) (*domain.Innovation, error) {
+func (r *postgresRepository) GetVoteBuckets(ctx context.Context, eventID string, since, until time.Time, bucket time.Duration) ([]*domain.VoteBucket, error) {
+	// Votes before the range fall into a NULL bucket so the caller can seed
+	// running totals from the same pass
 	query := `
-		SELECT i.id, i.group_slug, i.slug, i.name, i.division, i.entity_name, i.pic, i.description,
-		       i.logo_innovation_url, i.logo_entity_url, i.video_url, i.slide_url, i.ig_url, i.yt_url,
-		       i.created_at, i.updated_at
+		SELECT innovation_id,
+		       CASE WHEN created_at < $2 THEN NULL
+		            ELSE date_bin($4 * INTERVAL '1 second', created_at, $2)
+		       END AS bucket,
+		       COUNT(*)
+		FROM votes
+		WHERE event_id = $1 AND created_at < $3 AND invalidated_at IS NULL
+		GROUP BY 1, 2
+		ORDER BY 2 NULLS FIRST
+	`
+
+	rows, err := r.pool.Query(ctx, query, eventID, since, until, int64(bucket/time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("query vote buckets: %w", err)
+	}
+	defer rows.Close()
+
+	var buckets []*domain.VoteBucket
+	for rows.Next() {
+		var b domain.VoteBucket
+		var start *time.Time
+		if err := rows.Scan(&b.InnovationID, &start, &b.Count); err != nil {
+			return nil, fmt.Errorf("scan vote bucket: %w", err)
+		}
+		if start != nil {
+			b.Start = *start
+		}
+		buckets = append(buckets, &b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return buckets, nil
+}
+
+func (r *postgresRepository) StreamVotes(ctx context.Context, eventID string, fn func(*domain.VoteRecord) error) error {
+	query := `
+		SELECT v.id, v.innovation_id, i.group_slug, i.slug, i.name,
+		       v.created_at, v.voter_ip_hash, COALESCE(v.user_agent, ''), v.invalidated_at IS NOT NULL
 		FROM votes v
-		JOIN innovations i ON v.innovation_id = i.id
-		WHERE v.voter_ip_hash = $1
-		LIMIT 1
+		JOIN innovations i ON i.id = v.innovation_id
+		WHERE v.event_id = $1
+		ORDER BY v.id
 	`
 
-	var innovation domain.Innovation
-	err := r.pool.QueryRow(ctx, query, voterIPHash).Scan(
-		&innovation.ID,
-		&innovation.GroupSlug,
-		&innovation.Slug,
-		&innovation.Name,
-		&innovation.Division,
-		&innovation.EntityName,
-		&innovation.PIC,
-		&innovation.Description,
-		&innovation.LogoInnovationURL,
-		&innovation.LogoEntityURL,
-		&innovation.VideoURL,
-		&innovation.SlideURL,
-		&innovation.IgURL,
-		&innovation.YtURL,
-		&innovation.CreatedAt,
-		&innovation.UpdatedAt,
-	)
+	rows, err := r.pool.Query(ctx, query, eventID)
+	if err != nil {
+		return fmt.Errorf("query votes: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var record domain.VoteRecord
+		if err := rows.Scan(&record.ID, &record.InnovationID, &record.GroupSlug, &record.InnovationSlug,
+			&record.InnovationName, &record.CreatedAt, &record.VoterIPHash, &record.UserAgent, &record.Invalidated); err != nil {
+			return fmt.Errorf("scan vote: %w", err)
+		}
+		if err := fn(&record); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return nil
+}
 
+func (r *postgresRepository) HasVotedInScope(ctx context.Context, eventID, scopeKey string, voterKey []byte) (bool, error) {
+	query := `SELECT EXISTS(SELECT 1 FROM votes WHERE event_id = $1 AND scope_key = $2 AND voter_key = $3)`
+
+	var exists bool
+	err := r.pool.QueryRow(ctx, query, eventID, scopeKey, voterKey).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("check scope vote: %w", err)
+	}
+
+	return exists, nil
+}
+
+func (r *postgresRepository) GetVotedInnovation(ctx context.Context, eventID, scopeKey string, voterKey []byte) (*domain.Innovation, error) {
+	query := `
+		SELECT ` + innovationColumns + `
+		FROM innovations
+		WHERE id = (
+			SELECT innovation_id FROM votes
+			WHERE event_id = $1 AND scope_key = $2 AND voter_key = $3
+			LIMIT 1
+		)
+	`
+
+	innovation, err := scanInnovation(r.pool.QueryRow(ctx, query, eventID, scopeKey, voterKey))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrInnovationNotFound
@@ -222,5 +650,5 @@ func (r *postgresRepository) GetVotedInnovation(ctx context.Context, voterIPHash
 		return nil, fmt.Errorf("get voted innovation: %w", err)
 	}
 
-	return &innovation, nil
+	return innovation, nil
 }
@@ -0,0 +1,91 @@
+package repo
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"voteweb/internal/domain"
+	"voteweb/internal/migrate"
+	"voteweb/migrations"
+)
+
+func TestPostgresRepository_Conformance(t *testing.T) {
+	pool := openTestDatabase(t)
+	runRepositoryConformance(t, func(t *testing.T) (domain.Repository, *domain.Event) {
+		return NewPostgresRepository(pool), createTestEvent(t, pool)
+	})
+}
+
+// openTestDatabase connects to TEST_DATABASE_URL and applies the migrations.
+// The test is skipped when the variable is not set.
+func openTestDatabase(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+
+	pool, err := pgxpool.New(ctx, databaseURL)
+	if err != nil {
+		t.Fatalf("connect to TEST_DATABASE_URL: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	migrator, err := migrate.New(pool, migrations.Files, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+	return pool
+}
+
+// createTestEvent inserts an open event holding conformanceGroups under a
+// unique slug, and deletes it with its innovations and votes after the test
+func createTestEvent(t *testing.T, pool *pgxpool.Pool) *domain.Event {
+	t.Helper()
+	ctx := context.Background()
+
+	var eventID string
+	slug := "conformance-" + uuid.NewString()
+	err := pool.QueryRow(ctx,
+		`INSERT INTO events (slug, name, paused) VALUES ($1, $2, false) RETURNING id`,
+		slug, "Conformance",
+	).Scan(&eventID)
+	if err != nil {
+		t.Fatalf("insert event: %v", err)
+	}
+	t.Cleanup(func() {
+		// Innovations reference the event's groups without ON DELETE CASCADE
+		if _, err := pool.Exec(ctx, `DELETE FROM innovations WHERE event_id = $1`, eventID); err != nil {
+			t.Errorf("delete innovations: %v", err)
+		}
+		if _, err := pool.Exec(ctx, `DELETE FROM events WHERE id = $1`, eventID); err != nil {
+			t.Errorf("delete event: %v", err)
+		}
+	})
+
+	for i, group := range conformanceGroups {
+		_, err := pool.Exec(ctx,
+			`INSERT INTO event_groups (event_id, slug, name, position) VALUES ($1, $2, $3, $4)`,
+			eventID, group, group, i+1,
+		)
+		if err != nil {
+			t.Fatalf("insert group %s: %v", group, err)
+		}
+	}
+
+	event, err := NewPostgresRepository(pool).GetEventByID(ctx, eventID)
+	if err != nil {
+		t.Fatalf("GetEventByID() error = %v", err)
+	}
+	return event
+}
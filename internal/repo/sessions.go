@@ -0,0 +1,142 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"voteweb/internal/domain"
+)
+
+// NewPostgresSessionRepository creates a PostgreSQL-backed admin session store
+func NewPostgresSessionRepository(pool *pgxpool.Pool) domain.SessionRepository {
+	return &postgresRepository{pool: pool}
+}
+
+const sessionColumns = `s.id, s.token_hash, s.user_id, u.username, u.role, COALESCE(s.user_agent, ''),
+		       s.created_at, s.last_seen_at, s.expires_at, s.revoked_at`
+
+// scanSession scans a row selected with sessionColumns
+func scanSession(row pgx.Row) (*domain.AdminSession, error) {
+	var session domain.AdminSession
+	err := row.Scan(
+		&session.ID,
+		&session.TokenHash,
+		&session.UserID,
+		&session.Username,
+		&session.Role,
+		&session.UserAgent,
+		&session.CreatedAt,
+		&session.LastSeenAt,
+		&session.ExpiresAt,
+		&session.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *postgresRepository) CreateAdminSession(ctx context.Context, session *domain.AdminSession) error {
+	query := `
+		INSERT INTO admin_sessions (token_hash, user_id, user_agent, created_at, last_seen_at, expires_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $4, $5)
+		RETURNING id
+	`
+
+	err := r.pool.QueryRow(ctx, query, session.TokenHash, session.UserID, session.UserAgent, session.CreatedAt, session.ExpiresAt).Scan(&session.ID)
+	if err != nil {
+		return fmt.Errorf("insert admin session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *postgresRepository) GetAdminSession(ctx context.Context, tokenHash []byte) (*domain.AdminSession, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM admin_sessions s
+		JOIN admin_users u ON u.id = s.user_id
+		WHERE s.token_hash = $1
+	`
+
+	session, err := scanSession(r.pool.QueryRow(ctx, query, tokenHash))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("query admin session: %w", err)
+	}
+
+	return session, nil
+}
+
+func (r *postgresRepository) TouchAdminSession(ctx context.Context, id string) error {
+	_, err := r.pool.Exec(ctx, `UPDATE admin_sessions SET last_seen_at = NOW() WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("touch admin session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *postgresRepository) ListActiveAdminSessions(ctx context.Context) ([]*domain.AdminSession, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM admin_sessions s
+		JOIN admin_users u ON u.id = s.user_id
+		WHERE s.revoked_at IS NULL AND s.expires_at > NOW()
+		ORDER BY s.last_seen_at DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query admin sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*domain.AdminSession
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan admin session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return sessions, nil
+}
+
+func (r *postgresRepository) RevokeAdminSession(ctx context.Context, id string) error {
+	query := `UPDATE admin_sessions SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`
+
+	tag, err := r.pool.Exec(ctx, query, id)
+	if err != nil {
+		if isInvalidID(err) {
+			return domain.ErrSessionNotFound
+		}
+		return fmt.Errorf("revoke admin session: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+func (r *postgresRepository) RevokeUserSessions(ctx context.Context, userID string) error {
+	query := `UPDATE admin_sessions SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`
+
+	if _, err := r.pool.Exec(ctx, query, userID); err != nil {
+		return fmt.Errorf("revoke admin user sessions: %w", err)
+	}
+
+	return nil
+}
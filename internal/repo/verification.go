@@ -0,0 +1,142 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"voteweb/internal/domain"
+)
+
+// NewPostgresVerificationRepository creates a PostgreSQL-backed store for
+// voter one-time codes and sessions
+func NewPostgresVerificationRepository(pool *pgxpool.Pool) domain.VerificationRepository {
+	return &postgresRepository{pool: pool}
+}
+
+func (r *postgresRepository) CreateVerificationCode(ctx context.Context, code *domain.VerificationCode) error {
+	query := `
+		INSERT INTO voter_codes (event_id, contact_hash, channel, code_hash, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+
+	err := r.pool.QueryRow(ctx, query, code.EventID, code.ContactHash, code.Channel, code.CodeHash,
+		code.CreatedAt, code.ExpiresAt).Scan(&code.ID)
+	if err != nil {
+		return fmt.Errorf("insert voter code: %w", err)
+	}
+
+	return nil
+}
+
+func (r *postgresRepository) GetLatestVerificationCode(ctx context.Context, eventID string, contactHash []byte) (*domain.VerificationCode, error) {
+	query := `
+		SELECT id, event_id, contact_hash, channel, code_hash, attempts, created_at, expires_at, used_at
+		FROM voter_codes
+		WHERE event_id = $1 AND contact_hash = $2
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	var code domain.VerificationCode
+	err := r.pool.QueryRow(ctx, query, eventID, contactHash).Scan(
+		&code.ID,
+		&code.EventID,
+		&code.ContactHash,
+		&code.Channel,
+		&code.CodeHash,
+		&code.Attempts,
+		&code.CreatedAt,
+		&code.ExpiresAt,
+		&code.UsedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrInvalidCode
+		}
+		return nil, fmt.Errorf("query voter code: %w", err)
+	}
+
+	return &code, nil
+}
+
+func (r *postgresRepository) CountVerificationCodes(ctx context.Context, eventID string, contactHash []byte, since time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM voter_codes WHERE event_id = $1 AND contact_hash = $2 AND created_at >= $3`
+
+	var count int
+	if err := r.pool.QueryRow(ctx, query, eventID, contactHash, since).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count voter codes: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *postgresRepository) RecordVerificationAttempt(ctx context.Context, id string) (int, error) {
+	query := `UPDATE voter_codes SET attempts = attempts + 1 WHERE id = $1 RETURNING attempts`
+
+	var attempts int
+	if err := r.pool.QueryRow(ctx, query, id).Scan(&attempts); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, domain.ErrInvalidCode
+		}
+		return 0, fmt.Errorf("record voter code attempt: %w", err)
+	}
+
+	return attempts, nil
+}
+
+func (r *postgresRepository) UseVerificationCode(ctx context.Context, id string, at time.Time) (bool, error) {
+	query := `UPDATE voter_codes SET used_at = $2 WHERE id = $1 AND used_at IS NULL`
+
+	tag, err := r.pool.Exec(ctx, query, id, at)
+	if err != nil {
+		return false, fmt.Errorf("use voter code: %w", err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *postgresRepository) CreateVoterSession(ctx context.Context, session *domain.VoterSession) error {
+	query := `
+		INSERT INTO voter_sessions (token_hash, event_id, contact_hash, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.pool.Exec(ctx, query, session.TokenHash, session.EventID, session.ContactHash,
+		session.CreatedAt, session.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("insert voter session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *postgresRepository) GetVoterSession(ctx context.Context, tokenHash []byte) (*domain.VoterSession, error) {
+	query := `
+		SELECT token_hash, event_id, contact_hash, created_at, expires_at
+		FROM voter_sessions
+		WHERE token_hash = $1
+	`
+
+	var session domain.VoterSession
+	err := r.pool.QueryRow(ctx, query, tokenHash).Scan(
+		&session.TokenHash,
+		&session.EventID,
+		&session.ContactHash,
+		&session.CreatedAt,
+		&session.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrVerificationRequired
+		}
+		return nil, fmt.Errorf("query voter session: %w", err)
+	}
+
+	return &session, nil
+}
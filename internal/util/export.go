@@ -0,0 +1,155 @@
+package util
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// TableWriter writes the rows of a tabular export one at a time
+type TableWriter interface {
+	WriteRow(values ...any) error
+	// Close flushes buffered output; the export is incomplete until it returns
+	Close() error
+}
+
+// csvFlushRows is how many rows the CSV writer buffers before flushing
+const csvFlushRows = 500
+
+type csvTableWriter struct {
+	w    *csv.Writer
+	rows int
+}
+
+// NewCSVTableWriter writes rows as CSV. Text that a spreadsheet would run as a
+// formula is prefixed with a quote.
+func NewCSVTableWriter(w io.Writer) TableWriter {
+	return &csvTableWriter{w: csv.NewWriter(w)}
+}
+
+func (t *csvTableWriter) WriteRow(values ...any) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		record[i] = formatCSVValue(value)
+	}
+	if err := t.w.Write(record); err != nil {
+		return err
+	}
+
+	t.rows++
+	if t.rows%csvFlushRows == 0 {
+		t.w.Flush()
+		return t.w.Error()
+	}
+	return nil
+}
+
+func (t *csvTableWriter) Close() error {
+	t.w.Flush()
+	return t.w.Error()
+}
+
+func formatCSVValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return escapeFormula(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	default:
+		return escapeFormula(fmt.Sprint(v))
+	}
+}
+
+// escapeFormula keeps spreadsheets from evaluating user-supplied text
+func escapeFormula(s string) string {
+	if s == "" {
+		return s
+	}
+	switch s[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + s
+	}
+	return s
+}
+
+type xlsxTableWriter struct {
+	w         io.Writer
+	file      *excelize.File
+	stream    *excelize.StreamWriter
+	timeStyle int
+	row       int
+}
+
+// NewXLSXTableWriter writes rows to a single-sheet XLSX workbook. Rows are
+// spooled to a temporary file once they outgrow memory, and the workbook is
+// written to w on Close.
+func NewXLSXTableWriter(w io.Writer, sheet string) (TableWriter, error) {
+	file := excelize.NewFile()
+	if err := file.SetSheetName("Sheet1", sheet); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("name sheet: %w", err)
+	}
+
+	stream, err := file.NewStreamWriter(sheet)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("create sheet writer: %w", err)
+	}
+
+	format := "yyyy-mm-dd hh:mm:ss"
+	timeStyle, err := file.NewStyle(&excelize.Style{CustomNumFmt: &format})
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("create time style: %w", err)
+	}
+
+	return &xlsxTableWriter{
+		w:         w,
+		file:      file,
+		stream:    stream,
+		timeStyle: timeStyle,
+	}, nil
+}
+
+func (t *xlsxTableWriter) WriteRow(values ...any) error {
+	t.row++
+	cell, err := excelize.CoordinatesToCellName(1, t.row)
+	if err != nil {
+		return err
+	}
+
+	row := make([]any, len(values))
+	for i, value := range values {
+		if ts, ok := value.(time.Time); ok {
+			row[i] = excelize.Cell{StyleID: t.timeStyle, Value: ts.UTC()}
+			continue
+		}
+		row[i] = value
+	}
+	return t.stream.SetRow(cell, row)
+}
+
+func (t *xlsxTableWriter) Close() error {
+	defer t.file.Close()
+	if err := t.stream.Flush(); err != nil {
+		return fmt.Errorf("flush sheet: %w", err)
+	}
+	if err := t.file.Write(t.w); err != nil {
+		return fmt.Errorf("write workbook: %w", err)
+	}
+	return nil
+}
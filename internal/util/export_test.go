@@ -0,0 +1,72 @@
+package util
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestCSVTableWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCSVTableWriter(&buf)
+
+	created := time.Date(2026, 5, 1, 9, 30, 0, 0, time.FixedZone("WIB", 7*3600))
+	rows := [][]any{
+		{"name", "votes", "share", "created_at"},
+		{"Inovasi, \"Satu\"", int64(12), 37.5, created},
+		{"=HYPERLINK(\"x\")", 0, nil, "-1"},
+	}
+	for _, row := range rows {
+		if err := w.WriteRow(row...); err != nil {
+			t.Fatalf("WriteRow() error = %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	want := "name,votes,share,created_at\n" +
+		"\"Inovasi, \"\"Satu\"\"\",12,37.50,2026-05-01T02:30:00Z\n" +
+		"\"'=HYPERLINK(\"\"x\"\")\",0,,'-1\n"
+	if buf.String() != want {
+		t.Errorf("CSV =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestXLSXTableWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewXLSXTableWriter(&buf, "Votes")
+	if err != nil {
+		t.Fatalf("NewXLSXTableWriter() error = %v", err)
+	}
+
+	created := time.Date(2026, 5, 1, 9, 30, 0, 0, time.UTC)
+	if err := w.WriteRow("id", "created_at"); err != nil {
+		t.Fatalf("WriteRow() error = %v", err)
+	}
+	if err := w.WriteRow(int64(7), created); err != nil {
+		t.Fatalf("WriteRow() error = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	file, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatalf("OpenReader() error = %v", err)
+	}
+	defer file.Close()
+
+	rows, err := file.GetRows("Votes")
+	if err != nil {
+		t.Fatalf("GetRows() error = %v", err)
+	}
+	if len(rows) != 2 || rows[0][0] != "id" || rows[1][0] != "7" {
+		t.Fatalf("rows = %v, want header and one data row", rows)
+	}
+	if rows[1][1] != "2026-05-01 09:30:00" {
+		t.Errorf("created_at cell = %q, want 2026-05-01 09:30:00", rows[1][1])
+	}
+}
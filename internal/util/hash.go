@@ -7,21 +7,43 @@ import (
 
 // IPHasher provides IP hashing functionality using HMAC-SHA256
 type IPHasher struct {
-	salt []byte
+	salt     []byte
+	identity IPIdentity
 }
 
-// NewIPHasher creates a new IPHasher with the given salt
+// NewIPHasher creates a new IPHasher with the given salt and DefaultIPIdentity
 func NewIPHasher(salt string) *IPHasher {
+	return NewIPHasherWithIdentity(salt, DefaultIPIdentity)
+}
+
+// NewIPHasherWithIdentity creates a new IPHasher that masks addresses to the
+// identity's prefixes before hashing
+func NewIPHasherWithIdentity(salt string, identity IPIdentity) *IPHasher {
 	return &IPHasher{
-		salt: []byte(salt),
+		salt:     []byte(salt),
+		identity: identity,
 	}
 }
 
-// HashIP hashes an IP address using HMAC-SHA256
+// HashIP hashes the normalized form of an IP address using HMAC-SHA256
 func (h *IPHasher) HashIP(ip string) []byte {
 	mac := hmac.New(sha256.New, h.salt)
-	mac.Write([]byte(ip))
+	mac.Write([]byte(h.identity.Normalize(ip)))
 	return mac.Sum(nil)
 }
 
+// HashSubnet hashes the network of an IP address: its /24 (IPv4) or /64
+// (IPv6), whatever the voter identity prefixes are
+func (h *IPHasher) HashSubnet(ip string) []byte {
+	mac := hmac.New(sha256.New, h.salt)
+	mac.Write([]byte("subnet:" + SubnetKey(ip)))
+	return mac.Sum(nil)
+}
 
+// HashValue hashes an identifier other than an IP address, such as a voter's
+// contact, using HMAC-SHA256 with the same salt
+func (h *IPHasher) HashValue(value string) []byte {
+	mac := hmac.New(sha256.New, h.salt)
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
@@ -32,6 +32,24 @@ func TestIPHasher_HashIP(t *testing.T) {
 			ip2:  "2001:0db8:85a3:0000:0000:8a2e:0370:7334",
 			want: true,
 		},
+		{
+			name: "IPv6 addresses in the same /64",
+			ip1:  "2001:db8:85a3:1::1",
+			ip2:  "2001:db8:85a3:1:ffff:ffff:ffff:fffe",
+			want: true,
+		},
+		{
+			name: "IPv6 addresses in different /64s",
+			ip1:  "2001:db8:85a3:1::1",
+			ip2:  "2001:db8:85a3:2::1",
+			want: false,
+		},
+		{
+			name: "IPv4-mapped IPv6 matches IPv4",
+			ip1:  "::ffff:1.2.3.4",
+			ip2:  "1.2.3.4",
+			want: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -68,3 +86,42 @@ func TestIPHasher_DifferentSalts(t *testing.T) {
 
 
 
+
+func TestIPHasher_Identity(t *testing.T) {
+	hasher := NewIPHasherWithIdentity("test-salt", IPIdentity{IPv4Prefix: 24, IPv6Prefix: 128})
+
+	if string(hasher.HashIP("10.0.0.1")) != string(hasher.HashIP("10.0.0.200")) {
+		t.Error("Addresses in the same IPv4 /24 should hash the same")
+	}
+	if string(hasher.HashIP("2001:db8::1")) == string(hasher.HashIP("2001:db8::2")) {
+		t.Error("Distinct IPv6 addresses should hash differently with a /128 identity")
+	}
+}
+
+func TestIPHasher_HashValue(t *testing.T) {
+	hasher := NewIPHasher("test-salt")
+
+	if string(hasher.HashValue("budi@example.com")) != string(hasher.HashValue("budi@example.com")) {
+		t.Error("Same value should produce the same hash")
+	}
+	if string(hasher.HashValue("budi@example.com")) == string(hasher.HashValue("siti@example.com")) {
+		t.Error("Different values should produce different hashes")
+	}
+	if string(NewIPHasher("other-salt").HashValue("budi@example.com")) == string(hasher.HashValue("budi@example.com")) {
+		t.Error("Different salts should produce different hashes for the same value")
+	}
+}
+
+func TestIPHasher_HashSubnet(t *testing.T) {
+	hasher := NewIPHasher("test-salt")
+
+	if string(hasher.HashSubnet("10.0.0.1")) != string(hasher.HashSubnet("10.0.0.200")) {
+		t.Error("Addresses in the same IPv4 /24 should share a subnet hash")
+	}
+	if string(hasher.HashSubnet("10.0.0.1")) == string(hasher.HashSubnet("10.0.1.1")) {
+		t.Error("Addresses in different /24s should have different subnet hashes")
+	}
+	if string(hasher.HashSubnet("10.0.0.1")) == string(hasher.HashIP("10.0.0.1")) {
+		t.Error("Subnet hashes should differ from voter IP hashes")
+	}
+}
@@ -32,4 +32,113 @@ func IsIPInCIDRs(ip string, cidrs []*net.IPNet) bool {
 	return false
 }
 
+// IPIdentity says how much of an address identifies one client. Addresses
+// are masked to these prefix lengths, so every address of an IPv6 /64 counts
+// as the same client. IPv4-mapped IPv6 addresses are treated as IPv4.
+type IPIdentity struct {
+	IPv4Prefix int
+	IPv6Prefix int
+}
+
+// DefaultIPIdentity keeps whole IPv4 addresses and the /64 of IPv6 addresses,
+// the smallest block an IPv6 user is usually given
+var DefaultIPIdentity = IPIdentity{IPv4Prefix: 32, IPv6Prefix: 64}
 
+// Normalize returns the canonical form of ip under the identity: the bare
+// address for a full-length prefix, CIDR notation otherwise. Unparseable
+// input is returned as is.
+func (id IPIdentity) Normalize(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ip
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return prefixString(v4, id.IPv4Prefix, 32)
+	}
+	return prefixString(parsed, id.IPv6Prefix, 128)
+}
+
+// prefixString masks ip to the first bits of its total length
+func prefixString(ip net.IP, bits, total int) string {
+	if bits <= 0 || bits >= total {
+		return ip.String()
+	}
+	mask := net.CIDRMask(bits, total)
+	return (&net.IPNet{IP: ip.Mask(mask), Mask: mask}).String()
+}
+
+// SubnetKey returns the network of ip as a CIDR string: the /24 of an IPv4
+// address or the /64 of an IPv6 address. Unparseable input is returned as is.
+func SubnetKey(ip string) string {
+	return IPIdentity{IPv4Prefix: 24, IPv6Prefix: 64}.Normalize(ip)
+}
+
+// ParseXForwardedFor splits X-Forwarded-For values into hops, client first.
+// Ports are dropped and empty entries skipped.
+func ParseXForwardedFor(values ...string) []string {
+	var hops []string
+	for _, value := range values {
+		for _, hop := range strings.Split(value, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, strings.Trim(NormalizeIP(hop), "[]"))
+			}
+		}
+	}
+	return hops
+}
+
+// ParseForwarded returns the for= addresses of RFC 7239 Forwarded values,
+// client first. Obfuscated identifiers such as "unknown" or "_hidden" are
+// kept so a walk over the hops stops at them.
+func ParseForwarded(values ...string) []string {
+	var hops []string
+	for _, value := range values {
+		for _, element := range strings.Split(value, ",") {
+			hop := ""
+			for _, pair := range strings.Split(element, ";") {
+				key, val, ok := strings.Cut(strings.TrimSpace(pair), "=")
+				if ok && strings.EqualFold(key, "for") {
+					hop = strings.Trim(strings.TrimSpace(val), `"`)
+					break
+				}
+			}
+			if hop == "" {
+				continue
+			}
+			if strings.HasPrefix(hop, "[") {
+				// [2001:db8::1] or [2001:db8::1]:4711
+				if end := strings.Index(hop, "]"); end > 0 {
+					hop = hop[1:end]
+				}
+			} else {
+				hop = NormalizeIP(hop)
+			}
+			hops = append(hops, hop)
+		}
+	}
+	return hops
+}
+
+// ClientIPFromHops finds the client behind a chain of proxies. hops lists the
+// forwarded addresses client first and remoteIP is the connection's peer. The
+// chain is walked right to left, skipping addresses in trusted, and the first
+// untrusted address is the client: everything to its left was written by a
+// party we cannot verify. An entry that is not an IP ends the walk at the
+// last address seen.
+func ClientIPFromHops(hops []string, remoteIP string, trusted []*net.IPNet) string {
+	clientIP := remoteIP
+	if !IsIPInCIDRs(clientIP, trusted) {
+		return clientIP
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		if net.ParseIP(hops[i]) == nil {
+			return clientIP
+		}
+		clientIP = hops[i]
+		if !IsIPInCIDRs(clientIP, trusted) {
+			return clientIP
+		}
+	}
+	return clientIP
+}
@@ -2,6 +2,7 @@ package util
 
 import (
 	"net"
+	"strings"
 	"testing"
 )
 
@@ -94,3 +95,255 @@ func TestIsIPInCIDRs(t *testing.T) {
 
 
 
+
+func TestIPIdentity_Normalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		identity IPIdentity
+		input    string
+		want     string
+	}{
+		{
+			name:     "IPv4 full address",
+			identity: DefaultIPIdentity,
+			input:    "192.168.1.1",
+			want:     "192.168.1.1",
+		},
+		{
+			name:     "IPv4-mapped IPv6",
+			identity: DefaultIPIdentity,
+			input:    "::ffff:192.168.1.1",
+			want:     "192.168.1.1",
+		},
+		{
+			name:     "IPv6 /64",
+			identity: DefaultIPIdentity,
+			input:    "2001:0DB8:0000:0001:0000:0000:0000:0001",
+			want:     "2001:db8:0:1::/64",
+		},
+		{
+			name:     "IPv6 /128 canonical form",
+			identity: IPIdentity{IPv4Prefix: 32, IPv6Prefix: 128},
+			input:    "2001:0DB8:0000:0000:0000:0000:0000:0001",
+			want:     "2001:db8::1",
+		},
+		{
+			name:     "IPv4 /24",
+			identity: IPIdentity{IPv4Prefix: 24, IPv6Prefix: 64},
+			input:    "10.1.2.3",
+			want:     "10.1.2.0/24",
+		},
+		{
+			name:     "invalid input unchanged",
+			identity: DefaultIPIdentity,
+			input:    "unknown",
+			want:     "unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.identity.Normalize(tt.input); got != tt.want {
+				t.Errorf("Normalize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubnetKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "IPv4 /24",
+			input: "203.0.113.77",
+			want:  "203.0.113.0/24",
+		},
+		{
+			name:  "IPv4-mapped IPv6 uses the IPv4 /24",
+			input: "::ffff:203.0.113.77",
+			want:  "203.0.113.0/24",
+		},
+		{
+			name:  "IPv6 /64",
+			input: "2001:db8:1:2:aaaa:bbbb:cccc:dddd",
+			want:  "2001:db8:1:2::/64",
+		},
+		{
+			name:  "invalid input unchanged",
+			input: "not-an-ip",
+			want:  "not-an-ip",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SubnetKey(tt.input); got != tt.want {
+				t.Errorf("SubnetKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseXForwardedFor(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   []string
+	}{
+		{
+			name:   "single address",
+			values: []string{"203.0.113.7"},
+			want:   []string{"203.0.113.7"},
+		},
+		{
+			name:   "chain with spaces and ports",
+			values: []string{"203.0.113.7:5000 , 198.51.100.2,10.0.0.1"},
+			want:   []string{"203.0.113.7", "198.51.100.2", "10.0.0.1"},
+		},
+		{
+			name:   "bracketed IPv6",
+			values: []string{"[2001:db8::1]:8080, [2001:db8::2]"},
+			want:   []string{"2001:db8::1", "2001:db8::2"},
+		},
+		{
+			name:   "repeated headers are joined in order",
+			values: []string{"203.0.113.7", "10.0.0.1"},
+			want:   []string{"203.0.113.7", "10.0.0.1"},
+		},
+		{
+			name:   "empty entries skipped",
+			values: []string{" , 203.0.113.7,,"},
+			want:   []string{"203.0.113.7"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseXForwardedFor(tt.values...)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("ParseXForwardedFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseForwarded(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   []string
+	}{
+		{
+			name:   "single element",
+			values: []string{"for=192.0.2.60;proto=http;by=203.0.113.43"},
+			want:   []string{"192.0.2.60"},
+		},
+		{
+			name:   "multiple elements, case-insensitive key",
+			values: []string{"for=192.0.2.43, FOR=198.51.100.17"},
+			want:   []string{"192.0.2.43", "198.51.100.17"},
+		},
+		{
+			name:   "quoted IPv6 with port",
+			values: []string{`for="[2001:db8:cafe::17]:4711"`},
+			want:   []string{"2001:db8:cafe::17"},
+		},
+		{
+			name:   "quoted IPv4 with port",
+			values: []string{`for="192.0.2.60:8080"`},
+			want:   []string{"192.0.2.60"},
+		},
+		{
+			name:   "obfuscated identifiers kept",
+			values: []string{"for=unknown, for=_hidden"},
+			want:   []string{"unknown", "_hidden"},
+		},
+		{
+			name:   "element without for skipped",
+			values: []string{"proto=https, for=192.0.2.60"},
+			want:   []string{"192.0.2.60"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseForwarded(tt.values...)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("ParseForwarded() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientIPFromHops(t *testing.T) {
+	_, private, _ := net.ParseCIDR("10.0.0.0/8")
+	_, ula, _ := net.ParseCIDR("fd00::/8")
+	trusted := []*net.IPNet{private, ula}
+
+	tests := []struct {
+		name     string
+		hops     []string
+		remoteIP string
+		want     string
+	}{
+		{
+			name:     "untrusted peer ignores the header",
+			hops:     []string{"1.1.1.1"},
+			remoteIP: "203.0.113.7",
+			want:     "203.0.113.7",
+		},
+		{
+			name:     "trusted peer, single hop",
+			hops:     []string{"203.0.113.7"},
+			remoteIP: "10.0.0.1",
+			want:     "203.0.113.7",
+		},
+		{
+			name:     "spoofed leftmost entry is skipped",
+			hops:     []string{"1.1.1.1", "203.0.113.7"},
+			remoteIP: "10.0.0.1",
+			want:     "203.0.113.7",
+		},
+		{
+			name:     "trusted proxies in the chain are skipped",
+			hops:     []string{"1.1.1.1", "203.0.113.7", "10.0.0.3", "10.0.0.2"},
+			remoteIP: "10.0.0.1",
+			want:     "203.0.113.7",
+		},
+		{
+			name:     "all trusted returns the leftmost",
+			hops:     []string{"10.0.0.3", "10.0.0.2"},
+			remoteIP: "10.0.0.1",
+			want:     "10.0.0.3",
+		},
+		{
+			name:     "empty chain returns the peer",
+			hops:     nil,
+			remoteIP: "10.0.0.1",
+			want:     "10.0.0.1",
+		},
+		{
+			name:     "invalid entry stops at the last address seen",
+			hops:     []string{"1.1.1.1", "unknown", "10.0.0.2"},
+			remoteIP: "10.0.0.1",
+			want:     "10.0.0.2",
+		},
+		{
+			name:     "IPv6 chain",
+			hops:     []string{"2001:db8::1", "fd00::2"},
+			remoteIP: "fd00::1",
+			want:     "2001:db8::1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClientIPFromHops(tt.hops, tt.remoteIP, trusted); got != tt.want {
+				t.Errorf("ClientIPFromHops() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
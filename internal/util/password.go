@@ -0,0 +1,35 @@
+package util
+
+import (
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordHasher hashes admin passwords with bcrypt
+type PasswordHasher struct {
+	cost int
+}
+
+// NewPasswordHasher creates a new PasswordHasher with the given bcrypt cost.
+// A cost of 0 uses bcrypt.DefaultCost.
+func NewPasswordHasher(cost int) *PasswordHasher {
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+	return &PasswordHasher{
+		cost: cost,
+	}
+}
+
+// Hash returns the bcrypt hash of password
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Compare reports whether password matches hash
+func (h *PasswordHasher) Compare(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
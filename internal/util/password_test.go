@@ -0,0 +1,61 @@
+package util
+
+import (
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestPasswordHasher_Compare(t *testing.T) {
+	hasher := NewPasswordHasher(bcrypt.MinCost)
+
+	hash, err := hasher.Hash("correct horse battery")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		hash     string
+		password string
+		want     bool
+	}{
+		{
+			name:     "matching password",
+			hash:     hash,
+			password: "correct horse battery",
+			want:     true,
+		},
+		{
+			name:     "wrong password",
+			hash:     hash,
+			password: "correct horse battery!",
+			want:     false,
+		},
+		{
+			name:     "empty hash",
+			hash:     "",
+			password: "correct horse battery",
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasher.Compare(tt.hash, tt.password); got != tt.want {
+				t.Errorf("Compare() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPasswordHasher_Salted(t *testing.T) {
+	hasher := NewPasswordHasher(bcrypt.MinCost)
+
+	hash1, _ := hasher.Hash("same-password")
+	hash2, _ := hasher.Hash("same-password")
+
+	if hash1 == hash2 {
+		t.Error("Hashing the same password twice should produce different hashes")
+	}
+}
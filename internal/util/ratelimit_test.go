@@ -0,0 +1,88 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		perMinute int
+		burst     int
+		requests  []time.Duration // offsets from start
+		want      []bool
+	}{
+		{
+			name:      "burst then rejected",
+			perMinute: 60,
+			burst:     3,
+			requests:  []time.Duration{0, 0, 0, 0},
+			want:      []bool{true, true, true, false},
+		},
+		{
+			name:      "refills over time",
+			perMinute: 60,
+			burst:     1,
+			requests:  []time.Duration{0, 500 * time.Millisecond, time.Second},
+			want:      []bool{true, false, true},
+		},
+		{
+			name:      "refill is capped at burst",
+			perMinute: 60,
+			burst:     2,
+			requests:  []time.Duration{0, 0, time.Hour, time.Hour, time.Hour},
+			want:      []bool{true, true, true, true, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewRateLimiter(tt.perMinute, tt.burst)
+			for i, offset := range tt.requests {
+				got, _ := limiter.Allow("10.0.0.1", start.Add(offset))
+				if got != tt.want[i] {
+					t.Errorf("request %d at +%v: Allow() = %v, want %v", i, offset, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiter_RetryAfter(t *testing.T) {
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(30, 1)
+
+	limiter.Allow("a", start)
+	ok, wait := limiter.Allow("a", start)
+	if ok {
+		t.Fatal("expected second request to be rejected")
+	}
+	if wait != 2*time.Second {
+		t.Errorf("retry after = %v, want 2s", wait)
+	}
+
+	// Keys are independent
+	if ok, _ := limiter.Allow("b", start); !ok {
+		t.Error("expected another key to be allowed")
+	}
+}
+
+func TestRateLimiter_Sweep(t *testing.T) {
+	start := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(60, 5)
+
+	limiter.Allow("a", start)
+	limiter.Allow("b", start)
+	if limiter.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", limiter.Len())
+	}
+
+	// Both buckets have refilled after 5s; the next sweep forgets them
+	limiter.Allow("c", start.Add(2*time.Minute))
+	if limiter.Len() != 1 {
+		t.Errorf("Len() = %d, want 1 after sweep", limiter.Len())
+	}
+}
@@ -0,0 +1,111 @@
+package util
+
+import (
+	"testing"
+)
+
+func TestTokenSigner_Verify(t *testing.T) {
+	signer := NewTokenSigner("test-secret")
+	signed := signer.Sign("abc123")
+
+	tests := []struct {
+		name   string
+		value  string
+		want   string
+		wantOK bool
+	}{
+		{
+			name:   "valid signature",
+			value:  signed,
+			want:   "abc123",
+			wantOK: true,
+		},
+		{
+			name:   "tampered token",
+			value:  "abc124" + signed[len("abc123"):],
+			wantOK: false,
+		},
+		{
+			name:   "tampered signature",
+			value:  signed[:len(signed)-2] + "AA",
+			wantOK: false,
+		},
+		{
+			name:   "missing signature",
+			value:  "abc123",
+			wantOK: false,
+		},
+		{
+			name:   "empty token",
+			value:  "." + signed[len("abc123")+1:],
+			wantOK: false,
+		},
+		{
+			name:   "signed with another secret",
+			value:  NewTokenSigner("other-secret").Sign("abc123"),
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := signer.Verify(tt.value)
+			if ok != tt.wantOK {
+				t.Fatalf("Verify() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && got != tt.want {
+				t.Errorf("Verify() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenSigner_Rotation(t *testing.T) {
+	old := NewTokenSigner("old-secret")
+	rotated := NewTokenSigner("new-secret", "old-secret")
+
+	oldSigned := old.Sign("abc123")
+	got, ok := rotated.Verify(oldSigned)
+	if !ok || got != "abc123" {
+		t.Fatalf("Verify() of a token signed with a previous secret = %q, %v", got, ok)
+	}
+	if rotated.IsCurrent(oldSigned) {
+		t.Error("IsCurrent() = true for a token signed with a previous secret")
+	}
+
+	newSigned := rotated.Sign("abc123")
+	if !rotated.IsCurrent(newSigned) {
+		t.Error("IsCurrent() = false for a token signed with the current secret")
+	}
+	if _, ok := old.Verify(newSigned); ok {
+		t.Error("a signer without the new secret verified a token signed with it")
+	}
+	if rotated.IsCurrent("abc123.AAAA") {
+		t.Error("IsCurrent() = true for an invalid signature")
+	}
+}
+
+func TestTokenSigner_Derive(t *testing.T) {
+	signer := NewTokenSigner("test-secret")
+	derived := signer.Derive("purpose")
+
+	signed := derived.Sign("abc123")
+	if got, ok := derived.Verify(signed); !ok || got != "abc123" {
+		t.Fatalf("Verify() = %q, %v, want the derived signer to verify its own token", got, ok)
+	}
+	if _, ok := signer.Verify(signed); ok {
+		t.Error("the parent signer verified a token of the derived signer")
+	}
+	if _, ok := derived.Verify(signer.Sign("abc123")); ok {
+		t.Error("the derived signer verified a token of the parent signer")
+	}
+	if _, ok := signer.Derive("other").Verify(signed); ok {
+		t.Error("a signer derived for another purpose verified the token")
+	}
+
+	// Deriving from a rotated signer still accepts tokens of the old secret
+	rotated := NewTokenSigner("new-secret", "test-secret").Derive("purpose")
+	if _, ok := rotated.Verify(signed); !ok || rotated.IsCurrent(signed) {
+		t.Error("a derived signer lost the previous secret on rotation")
+	}
+}
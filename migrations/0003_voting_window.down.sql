@@ -0,0 +1,3 @@
+-- Revert: Store the voting window in the database
+
+DROP TABLE IF EXISTS voting_window;
@@ -0,0 +1,18 @@
+-- Migration: Store the voting window in the database
+-- Voting is open only between opens_at and closes_at (NULL means unbounded)
+-- and while paused is false. The table holds a single row.
+
+CREATE TABLE IF NOT EXISTS voting_window (
+  id SMALLINT PRIMARY KEY DEFAULT 1,
+  opens_at TIMESTAMPTZ,
+  closes_at TIMESTAMPTZ,
+  paused BOOLEAN NOT NULL DEFAULT false,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CONSTRAINT voting_window_single_row CHECK (id = 1),
+  CONSTRAINT voting_window_range CHECK (opens_at IS NULL OR closes_at IS NULL OR opens_at < closes_at)
+);
+
+-- Start paused so an upgraded deployment keeps voting closed until an
+-- operator opens it explicitly
+INSERT INTO voting_window (id, paused) VALUES (1, true)
+ON CONFLICT (id) DO NOTHING;
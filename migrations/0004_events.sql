@@ -0,0 +1,85 @@
+-- Migration: Multi-event support
+-- An event owns its groups, innovations and votes, and carries its own voting
+-- window. Existing data moves into a default event so past results are kept.
+
+CREATE TABLE IF NOT EXISTS events (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  slug TEXT NOT NULL,
+  name TEXT NOT NULL,
+  is_default BOOLEAN NOT NULL DEFAULT false,
+  opens_at TIMESTAMPTZ,
+  closes_at TIMESTAMPTZ,
+  paused BOOLEAN NOT NULL DEFAULT true,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CONSTRAINT events_slug_uk UNIQUE (slug),
+  CONSTRAINT events_window_range CHECK (opens_at IS NULL OR closes_at IS NULL OR opens_at < closes_at)
+);
+
+-- At most one event serves the legacy routes (/, /:group/:slug, /api/vote/...)
+CREATE UNIQUE INDEX IF NOT EXISTS events_single_default ON events (is_default) WHERE is_default;
+
+-- Move the global voting window into the default event
+INSERT INTO events (slug, name, is_default, opens_at, closes_at, paused)
+SELECT 'default', 'Default Event', true, opens_at, closes_at, paused
+FROM voting_window
+WHERE id = 1
+ON CONFLICT (slug) DO NOTHING;
+
+INSERT INTO events (slug, name, is_default, paused)
+VALUES ('default', 'Default Event', true, true)
+ON CONFLICT (slug) DO NOTHING;
+
+DROP TABLE IF EXISTS voting_window;
+
+-- Groups (categories) per event
+CREATE TABLE IF NOT EXISTS event_groups (
+  event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  slug TEXT NOT NULL,
+  name TEXT NOT NULL,
+  position INT NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (event_id, slug)
+);
+
+-- Innovations belong to an event and one of its groups
+ALTER TABLE innovations ADD COLUMN IF NOT EXISTS event_id UUID;
+UPDATE innovations SET event_id = (SELECT id FROM events WHERE slug = 'default') WHERE event_id IS NULL;
+ALTER TABLE innovations ALTER COLUMN event_id SET NOT NULL;
+
+INSERT INTO event_groups (event_id, slug, name)
+SELECT DISTINCT event_id, group_slug, group_slug FROM innovations
+ON CONFLICT (event_id, slug) DO NOTHING;
+
+UPDATE event_groups g SET name = v.name, position = v.position
+FROM (VALUES
+  ('pemprov-jabar', 'Pemerintah Provinsi Jawa Barat', 1),
+  ('pemda-kabupaten', 'Pemerintah Daerah Kabupaten', 2),
+  ('pemda-kota', 'Pemerintah Daerah Kota', 3),
+  ('bumn-bumd', 'BUMN/BUMD', 4),
+  ('kementrian-lembaga-pt', 'Kementerian/Lembaga/PT', 5),
+  ('smp-sma-sederajat', 'SMP/SMA/Sederajat', 6)
+) AS v(slug, name, position)
+WHERE g.slug = v.slug;
+
+ALTER TABLE innovations DROP CONSTRAINT IF EXISTS innovations_group_slug_slug_uk;
+ALTER TABLE innovations ADD CONSTRAINT innovations_event_group_slug_uk UNIQUE (event_id, group_slug, slug);
+ALTER TABLE innovations ADD CONSTRAINT innovations_id_event_uk UNIQUE (id, event_id);
+ALTER TABLE innovations ADD CONSTRAINT innovations_group_fk
+  FOREIGN KEY (event_id, group_slug) REFERENCES event_groups(event_id, slug) ON UPDATE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_innovations_event ON innovations(event_id);
+
+-- Votes are unique per IP within an event instead of forever
+ALTER TABLE votes ADD COLUMN IF NOT EXISTS event_id UUID;
+UPDATE votes v SET event_id = i.event_id FROM innovations i WHERE v.innovation_id = i.id AND v.event_id IS NULL;
+ALTER TABLE votes ALTER COLUMN event_id SET NOT NULL;
+
+ALTER TABLE votes DROP CONSTRAINT IF EXISTS votes_innovation_id_fkey;
+ALTER TABLE votes ADD CONSTRAINT votes_innovation_event_fk
+  FOREIGN KEY (innovation_id, event_id) REFERENCES innovations(id, event_id) ON DELETE CASCADE;
+
+ALTER TABLE votes DROP CONSTRAINT IF EXISTS votes_unique_per_ip;
+ALTER TABLE votes ADD CONSTRAINT votes_unique_per_ip_per_event UNIQUE (event_id, voter_ip_hash);
+
+CREATE INDEX IF NOT EXISTS idx_votes_event ON votes(event_id);
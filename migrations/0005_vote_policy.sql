@@ -0,0 +1,17 @@
+-- Migration: Configurable vote policy per event
+-- A vote is recorded with the scope it counts against. The policy decides the
+-- scope key and the unique constraint enforces one vote per IP per scope:
+--   global          scope_key = 'global'                one vote per event
+--   per-group       scope_key = 'group:<group_slug>'     one vote per group
+--   per-innovation  scope_key = 'innovation:<id>'        one vote per innovation
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS vote_policy TEXT NOT NULL DEFAULT 'global';
+ALTER TABLE events ADD CONSTRAINT events_vote_policy_check
+  CHECK (vote_policy IN ('global', 'per-group', 'per-innovation'));
+
+-- Existing votes were cast under the global policy
+ALTER TABLE votes ADD COLUMN IF NOT EXISTS scope_key TEXT NOT NULL DEFAULT 'global';
+ALTER TABLE votes ALTER COLUMN scope_key DROP DEFAULT;
+
+ALTER TABLE votes DROP CONSTRAINT IF EXISTS votes_unique_per_ip_per_event;
+ALTER TABLE votes ADD CONSTRAINT votes_unique_per_ip_per_scope UNIQUE (event_id, scope_key, voter_ip_hash);
@@ -0,0 +1,6 @@
+-- Revert: Hero images as innovation data
+-- The hero paths are dropped with their columns; 0006 sets them again for the
+-- default event's innovations.
+
+ALTER TABLE innovations DROP COLUMN IF EXISTS hero_mobile_url;
+ALTER TABLE innovations DROP COLUMN IF EXISTS hero_url;
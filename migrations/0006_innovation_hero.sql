@@ -0,0 +1,46 @@
+-- Migration: Hero images as innovation data
+-- Hero and mobile hero paths used to be hard-coded in the page handler.
+
+ALTER TABLE innovations ADD COLUMN IF NOT EXISTS hero_url TEXT;
+ALTER TABLE innovations ADD COLUMN IF NOT EXISTS hero_mobile_url TEXT;
+
+-- Carry over the paths of the default event's innovations
+UPDATE innovations i
+SET hero_url = v.hero_url,
+    hero_mobile_url = v.hero_mobile_url,
+    updated_at = now()
+FROM (VALUES
+  ('bumn-bumd', 'alat-pemecah-ombak-apo-desa-mayangan-subang', '/static/bumn-bumd/6.webp', '/static/bumn-bumd/6-mobile.webp'),
+  ('bumn-bumd', 'simotip', '/static/bumn-bumd/simotip.webp', '/static/bumn-bumd/simotip-mobile.webp'),
+  ('bumn-bumd', 'aplikasi-pemilu-elektronik-e-voting', '/static/bumn-bumd/evoting.webp', '/static/bumn-bumd/evoting-mobile.webp'),
+  ('bumn-bumd', 'thr-asyik', '/static/bumn-bumd/thr-asyik.webp', '/static/bumn-bumd/thr-asyik-mobile.webp'),
+  ('kementrian-lembaga-pt', 'isopa-intelligent-solar-panel', '/static/kementrian-lembaga-pt/isopa.webp', '/static/kementrian-lembaga-pt/isopa-mobile.webp'),
+  ('kementrian-lembaga-pt', 'instrumen-deteksi-risiko-stunting-pada-remaja-insting', '/static/kementrian-lembaga-pt/insting.webp', '/static/kementrian-lembaga-pt/insting-mobile.webp'),
+  ('kementrian-lembaga-pt', 'teknologi-hybrid-taman-sanitasi-hts-untuk-pencegahan-pencemaran-lingkungan-dan-daur-ulang-air', '/static/kementrian-lembaga-pt/hts.webp', '/static/kementrian-lembaga-pt/hts-mobile-1.webp'),
+  ('kementrian-lembaga-pt', 'inovasi-saschieversity', '/static/kementrian-lembaga-pt/saschieversity.webp', '/static/kementrian-lembaga-pt/saschieversity-mobile.webp'),
+  ('kementrian-lembaga-pt', 'mentari-mental-health-remaja-indonesia-assessment', '/static/kementrian-lembaga-pt/mentari-assesment.webp', '/static/kementrian-lembaga-pt/mentari-assesment.webp'),
+  ('pemprov-jabar', 'jabar-digital-academy', '/static/pemprov-jabar/jabar-istimewa-digital-academy.webp', '/static/pemprov-jabar/jabar-istimewa-digital-academy-mobile.webp'),
+  ('pemprov-jabar', 'delman-sarah-model-pemeliharaan-sapi-perah-di-jawa-barat', '/static/pemprov-jabar/new-normal-persusuan-jawa-barat.webp', '/static/pemprov-jabar/new-normal-persusuan-jawa-barat-mobile.webp'),
+  ('pemprov-jabar', 'jabar-form', '/static/pemprov-jabar/jabar-form.webp', '/static/pemprov-jabar/jabar-form-mobile.webp'),
+  ('pemprov-jabar', 'gisa-prima-adminduk-jabar', '/static/pemprov-jabar/gisa-prima.webp', '/static/pemprov-jabar/gisa-prima-mobile.webp'),
+  ('pemprov-jabar', 'data-potensi-digital-desa-tapal-desa', '/static/pemprov-jabar/tapal-desa.webp', '/static/pemprov-jabar/tapal-desa-mobile.webp'),
+  ('smp-sma-sederajat', 'penguatan-kompetensi-litnum-melalui-lesson-study', '/static/smp-sma/litnum.webp', '/static/smp-sma/litnum-mobile.webp'),
+  ('smp-sma-sederajat', 'motor-lstrik-dengan-teknologi-finger-print', '/static/smp-sma/motor-listrik-dengan-teknologi-finger-print.webp', '/static/smp-sma/motor-listrik-dengan-teknologi-finger-print-mobile.webp'),
+  ('smp-sma-sederajat', 'samving-block-sampah-plastik-menjadi-paving-block', '/static/smp-sma/samving-block.webp', '/static/smp-sma/samving-block-mobile.webp'),
+  ('smp-sma-sederajat', 'inovasi-sabun-nanas-tsanawiyah-satu', '/static/smp-sma/sanatsu.webp', '/static/smp-sma/sanatsu-mobile.webp'),
+  ('smp-sma-sederajat', 'tonnetar-tongkat-tunanetra-pintar', '/static/smp-sma/tonnetar.webp', '/static/smp-sma/tonnetar-mobile.webp'),
+  ('pemda-kabupaten', 'si-pintar-online', '/static/pemda-jabar/pintar-on-line.webp', '/static/pemda-jabar/pintar-on-line-mobile.webp'),
+  ('pemda-kabupaten', 'ekonomi-bangit-harapan-terbit-si-dara-puber-buka-jalan-sejahtera-untuk-5-260-orang-miskin-di-kabupaten-sumedang-sistem-pemberdayaan-masyarakat-miskin-dengan-pengembangan-ekonomi-produktif-melalui-kelompok-usaha-bersama', '/static/pemda-jabar/sidara-puber.webp', '/static/pemda-jabar/sidara-puber-mobile.webp'),
+  ('pemda-kabupaten', 'sistem-informasi-manajemen-perlindungan-pertanian-simarlin', '/static/pemda-jabar/simarlin.webp', '/static/pemda-jabar/simarlin-mobile.webp'),
+  ('pemda-kabupaten', 'nyai-indramayu-artificial-intelligence', '/static/pemda-jabar/nyai.webp', '/static/pemda-jabar/nyai.webp'),
+  ('pemda-kabupaten', 'ngupahan-ngabagi-ngubah-ngurai-sampah-pangan-dinas-ketahanan-pangan-kab-bogor', '/static/pemda-jabar/ngupahan.webp', '/static/pemda-jabar/ngupahan-mobile.webp'),
+  ('pemda-kabupaten', 'ketupat-lebaran-kegunaan-kartu-kepatuhan-minum-tablet-tambah-darah', '/static/pemda-jabar/ketupat-lebaran.webp', '/static/pemda-jabar/ketupat-lebaran-mobile.webp'),
+  ('pemda-kota', 'smart-k-sistem-manajemen-akuakultur-rekayasa-teknologi-dan-kemitraan', '/static/pemkot/smart-k.webp', '/static/pemkot/smart-k-mobile.webp'),
+  ('pemda-kota', 'bung-senja-tabungan-sedot-tinja', '/static/pemkot/buang-senja.webp', '/static/pemkot/buang-senja-mobile.webp'),
+  ('pemda-kota', 'gerakan-orang-cimahi-pilah-sampah-grak-ompimpah', '/static/pemkot/grak-ompimpah.webp', '/static/pemkot/grak-ompimpah-mobile.webp'),
+  ('pemda-kota', 'bogor-smart-health', '/static/pemkot/bogor-smart-health.webp', '/static/pemkot/bogor-smart-health-mobile.webp'),
+  ('pemda-kota', 'konservasi-mata-air-menjadi-ruang-terbuka-hijau-ruang-publik', '/static/pemkot/konversi-mata-air.webp', '/static/pemkot/konversi-mata-air-mobile.webp')
+) AS v(group_slug, slug, hero_url, hero_mobile_url)
+WHERE i.group_slug = v.group_slug
+  AND i.slug = v.slug
+  AND i.event_id = (SELECT id FROM events WHERE slug = 'default');
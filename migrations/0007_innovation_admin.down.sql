@@ -0,0 +1,8 @@
+-- Revert: Innovation management from the admin UI
+-- Archived innovations show on the public pages again and the saved order is
+-- lost.
+
+DROP INDEX IF EXISTS idx_innovations_event_group_position;
+
+ALTER TABLE innovations DROP COLUMN IF EXISTS archived_at;
+ALTER TABLE innovations DROP COLUMN IF EXISTS position;
@@ -0,0 +1,17 @@
+-- Migration: Innovation management from the admin UI
+-- position orders innovations within their group; archived innovations are
+-- hidden from the public pages but keep their votes.
+
+ALTER TABLE innovations ADD COLUMN IF NOT EXISTS position INT NOT NULL DEFAULT 0;
+ALTER TABLE innovations ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+
+-- Keep the current alphabetical order as the initial position
+UPDATE innovations i
+SET position = o.position
+FROM (
+  SELECT id, ROW_NUMBER() OVER (PARTITION BY event_id, group_slug ORDER BY name) AS position
+  FROM innovations
+) o
+WHERE i.id = o.id;
+
+CREATE INDEX IF NOT EXISTS idx_innovations_event_group_position ON innovations(event_id, group_slug, position);
@@ -0,0 +1,4 @@
+-- Revert: Server-side admin sessions
+-- Every admin is signed out.
+
+DROP TABLE IF EXISTS admin_sessions;
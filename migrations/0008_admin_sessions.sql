@@ -0,0 +1,16 @@
+-- Migration: Server-side admin sessions
+-- The cookie carries a random token signed with SESSION_SECRET; only the
+-- SHA-256 of the token is stored so a database dump cannot be replayed.
+
+CREATE TABLE IF NOT EXISTS admin_sessions (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  token_hash BYTEA NOT NULL UNIQUE,
+  subject TEXT NOT NULL,
+  user_agent TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  expires_at TIMESTAMPTZ NOT NULL,
+  revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_sessions_active ON admin_sessions(expires_at) WHERE revoked_at IS NULL;
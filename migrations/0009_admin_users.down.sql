@@ -0,0 +1,10 @@
+-- Revert: Admin accounts with roles
+-- Admin accounts are deleted with their sessions; the shared ADMIN_CODE must
+-- be configured again.
+
+DELETE FROM admin_sessions;
+DROP INDEX IF EXISTS idx_admin_sessions_user;
+ALTER TABLE admin_sessions DROP COLUMN IF EXISTS user_id;
+ALTER TABLE admin_sessions ADD COLUMN IF NOT EXISTS subject TEXT NOT NULL;
+
+DROP TABLE IF EXISTS admin_users;
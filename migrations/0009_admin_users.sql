@@ -0,0 +1,23 @@
+-- Migration: Admin accounts with roles
+-- Replaces the shared ADMIN_CODE. Roles are hierarchical:
+-- viewer < operator < superadmin.
+
+CREATE TABLE IF NOT EXISTS admin_users (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  username TEXT NOT NULL,
+  password_hash TEXT NOT NULL,
+  role TEXT NOT NULL CHECK (role IN ('viewer', 'operator', 'superadmin')),
+  disabled_at TIMESTAMPTZ,
+  last_login_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_admin_users_username ON admin_users(LOWER(username));
+
+-- Sessions now belong to a user; sessions opened with the shared code are dropped
+DELETE FROM admin_sessions;
+ALTER TABLE admin_sessions DROP COLUMN IF EXISTS subject;
+ALTER TABLE admin_sessions ADD COLUMN IF NOT EXISTS user_id UUID NOT NULL REFERENCES admin_users(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_admin_sessions_user ON admin_sessions(user_id);
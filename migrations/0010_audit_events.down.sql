@@ -0,0 +1,5 @@
+-- Revert: Append-only audit log
+-- The log is dropped with every entry in it; export it first to keep it.
+
+DROP TABLE IF EXISTS audit_events;
+DROP FUNCTION IF EXISTS audit_events_append_only();
@@ -0,0 +1,3 @@
+-- Revert: Index votes by time
+
+DROP INDEX IF EXISTS idx_votes_event_created;
@@ -0,0 +1,4 @@
+-- Migration: Index votes by time
+-- Time-series analytics bucket an event's votes by created_at.
+
+CREATE INDEX IF NOT EXISTS idx_votes_event_created ON votes(event_id, created_at);
@@ -0,0 +1,42 @@
+-- Migration: Voter verification by one-time code
+-- An event either identifies voters by IP (the default) or asks them to verify
+-- an email address or phone number first. Votes are deduplicated by voter_key:
+-- the hashed IP in 'ip' mode, the hashed contact in 'otp' mode. voter_ip_hash
+-- keeps the hashed IP of every vote either way.
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS voter_verification TEXT NOT NULL DEFAULT 'ip';
+ALTER TABLE events ADD CONSTRAINT events_voter_verification_check
+  CHECK (voter_verification IN ('ip', 'otp'));
+
+ALTER TABLE votes ADD COLUMN IF NOT EXISTS voter_key BYTEA;
+UPDATE votes SET voter_key = voter_ip_hash WHERE voter_key IS NULL;
+ALTER TABLE votes ALTER COLUMN voter_key SET NOT NULL;
+
+ALTER TABLE votes DROP CONSTRAINT IF EXISTS votes_unique_per_ip_per_scope;
+ALTER TABLE votes ADD CONSTRAINT votes_unique_per_voter_per_scope UNIQUE (event_id, scope_key, voter_key);
+
+-- Codes are stored as HMACs of the code, keyed to the hashed contact
+CREATE TABLE IF NOT EXISTS voter_codes (
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  contact_hash BYTEA NOT NULL,
+  channel TEXT NOT NULL CHECK (channel IN ('email', 'phone')),
+  code_hash BYTEA NOT NULL,
+  attempts INT NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  expires_at TIMESTAMPTZ NOT NULL,
+  used_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_voter_codes_contact ON voter_codes(event_id, contact_hash, created_at DESC);
+
+-- A verified contact gets a session; the cookie carries the token, the table its SHA-256
+CREATE TABLE IF NOT EXISTS voter_sessions (
+  token_hash BYTEA PRIMARY KEY,
+  event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  contact_hash BYTEA NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_voter_sessions_expires ON voter_sessions(expires_at);
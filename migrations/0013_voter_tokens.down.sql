@@ -0,0 +1,7 @@
+-- Revert: Voter-token cookies
+-- Votes deduplicated by token keep their token hash in voter_key, so switch
+-- VOTER_DEDUPE back to ip before reverting.
+
+DROP INDEX IF EXISTS idx_votes_scope_ip;
+
+ALTER TABLE votes DROP COLUMN IF EXISTS voter_token_hash;
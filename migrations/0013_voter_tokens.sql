@@ -0,0 +1,8 @@
+-- Migration: Voter-token cookies
+-- Votes keep the SHA-256 of the voter's signed-cookie token when one was sent.
+-- In the token modes voter_key holds that hash; the index serves the per-IP
+-- cap of the token+ip mode.
+
+ALTER TABLE votes ADD COLUMN IF NOT EXISTS voter_token_hash BYTEA;
+
+CREATE INDEX IF NOT EXISTS idx_votes_scope_ip ON votes(event_id, scope_key, voter_ip_hash);
@@ -0,0 +1,3 @@
+-- Revert: Index votes by IP and time
+
+DROP INDEX IF EXISTS idx_votes_event_ip_created;
@@ -0,0 +1,4 @@
+-- Migration: Index votes by IP and time
+-- Vote challenges count an IP's recent votes in the event.
+
+CREATE INDEX IF NOT EXISTS idx_votes_event_ip_created ON votes(event_id, voter_ip_hash, created_at);
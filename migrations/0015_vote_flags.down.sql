@@ -0,0 +1,9 @@
+-- Revert: Fraud flags and vote review
+-- Invalidated votes count again once invalidated_at is gone.
+
+DROP TABLE IF EXISTS vote_flags;
+
+ALTER TABLE votes DROP COLUMN IF EXISTS invalidation_reason;
+ALTER TABLE votes DROP COLUMN IF EXISTS invalidated_at;
+ALTER TABLE votes DROP COLUMN IF EXISTS reviewed_at;
+ALTER TABLE votes DROP COLUMN IF EXISTS voter_subnet_hash;
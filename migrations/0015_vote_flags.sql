@@ -0,0 +1,21 @@
+-- Migration: Fraud flags and vote review
+-- The analyser flags suspicious votes in vote_flags. Admins review them:
+-- invalidated votes stay in votes but no longer count, kept votes only get
+-- reviewed_at. voter_subnet_hash groups votes by /24 or /64; votes cast
+-- before this migration have none.
+
+ALTER TABLE votes ADD COLUMN IF NOT EXISTS voter_subnet_hash BYTEA;
+ALTER TABLE votes ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMPTZ;
+ALTER TABLE votes ADD COLUMN IF NOT EXISTS invalidated_at TIMESTAMPTZ;
+ALTER TABLE votes ADD COLUMN IF NOT EXISTS invalidation_reason TEXT;
+
+CREATE TABLE IF NOT EXISTS vote_flags (
+  vote_id BIGINT NOT NULL REFERENCES votes(id) ON DELETE CASCADE,
+  event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  rule TEXT NOT NULL,
+  detail TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (vote_id, rule)
+);
+
+CREATE INDEX IF NOT EXISTS idx_vote_flags_event ON vote_flags(event_id, vote_id DESC);
@@ -0,0 +1,4 @@
+-- Revert: Solved challenge tokens
+-- Tokens solved before the revert can be replayed until they expire.
+
+DROP TABLE IF EXISTS used_challenges;
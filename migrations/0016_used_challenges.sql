@@ -0,0 +1,11 @@
+-- Migration: Solved challenge tokens
+-- A proof-of-work token buys a single vote. Solved tokens are recorded here
+-- until they expire, so every replica rejects a replayed token and a restart
+-- does not forget them.
+
+CREATE TABLE IF NOT EXISTS used_challenges (
+  token_hash BYTEA PRIMARY KEY,
+  expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_used_challenges_expires ON used_challenges(expires_at);
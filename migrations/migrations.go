@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL schema migrations into the binary
+package migrations
+
+import "embed"
+
+// Files holds NNNN_name.sql migrations and their optional NNNN_name.down.sql
+// reversals
+//
+//go:embed *.sql
+var Files embed.FS
@@ -6,219 +6,356 @@ import (
 	"log"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"voteweb/internal/domain"
 )
 
+// GroupData represents the seed data for a group
+type GroupData struct {
+	Slug     string
+	Name     string
+	Position int
+}
+
+// GroupsData contains all group seed data
+var GroupsData = []GroupData{
+	{Slug: "pemprov-jabar", Name: "Pemerintah Provinsi Jawa Barat", Position: 1},
+	{Slug: "pemda-kabupaten", Name: "Pemerintah Daerah Kabupaten", Position: 2},
+	{Slug: "pemda-kota", Name: "Pemerintah Daerah Kota", Position: 3},
+	{Slug: "bumn-bumd", Name: "BUMN/BUMD", Position: 4},
+	{Slug: "kementrian-lembaga-pt", Name: "Kementerian/Lembaga/PT", Position: 5},
+	{Slug: "smp-sma-sederajat", Name: "SMP/SMA/Sederajat", Position: 6},
+}
+
 // InnovationData represents the seed data for an innovation
 type InnovationData struct {
-	GroupSlug string
-	Name      string
-	Slug      string
-	Division  string
+	GroupSlug  string
+	Name       string
+	Slug       string
+	Division   string
+	Hero       string
+	HeroMobile string
 }
 
 // InnovationsData contains all innovation seed data
 var InnovationsData = []InnovationData{
 	{
-		GroupSlug: "pemprov-jabar",
-		Name:      "Jabar Digital Academy",
-		Slug:      "jabar-digital-academy",
-		Division:  "PEMERINTAH PROVINSI JAWA BARAT",
+		GroupSlug:  "pemprov-jabar",
+		Name:       "Jabar Digital Academy",
+		Slug:       "jabar-digital-academy",
+		Division:   "PEMERINTAH PROVINSI JAWA BARAT",
+		Hero:       "/static/pemprov-jabar/jabar-istimewa-digital-academy.webp",
+		HeroMobile: "/static/pemprov-jabar/jabar-istimewa-digital-academy-mobile.webp",
 	},
 	{
-		GroupSlug: "pemprov-jabar",
-		Name:      "Data Potensi Digital Desa ( TAPAL DESA )",
-		Slug:      "data-potensi-digital-desa-tapal-desa",
-		Division:  "PEMERINTAH PROVINSI JAWA BARAT",
+		GroupSlug:  "pemprov-jabar",
+		Name:       "Data Potensi Digital Desa ( TAPAL DESA )",
+		Slug:       "data-potensi-digital-desa-tapal-desa",
+		Division:   "PEMERINTAH PROVINSI JAWA BARAT",
+		Hero:       "/static/pemprov-jabar/tapal-desa.webp",
+		HeroMobile: "/static/pemprov-jabar/tapal-desa-mobile.webp",
 	},
 	{
-		GroupSlug: "pemprov-jabar",
-		Name:      "GISA PRIMA ADMINDUK JABAR",
-		Slug:      "gisa-prima-adminduk-jabar",
-		Division:  "PEMERINTAH PROVINSI JAWA BARAT",
+		GroupSlug:  "pemprov-jabar",
+		Name:       "GISA PRIMA ADMINDUK JABAR",
+		Slug:       "gisa-prima-adminduk-jabar",
+		Division:   "PEMERINTAH PROVINSI JAWA BARAT",
+		Hero:       "/static/pemprov-jabar/gisa-prima.webp",
+		HeroMobile: "/static/pemprov-jabar/gisa-prima-mobile.webp",
 	},
 	{
-		GroupSlug: "pemprov-jabar",
-		Name:      "Delman Sarah (Model Pemeliharaan Sapi Perah) di Jawa Barat",
-		Slug:      "delman-sarah-model-pemeliharaan-sapi-perah-di-jawa-barat",
-		Division:  "PEMERINTAH PROVINSI JAWA BARAT",
+		GroupSlug:  "pemprov-jabar",
+		Name:       "Delman Sarah (Model Pemeliharaan Sapi Perah) di Jawa Barat",
+		Slug:       "delman-sarah-model-pemeliharaan-sapi-perah-di-jawa-barat",
+		Division:   "PEMERINTAH PROVINSI JAWA BARAT",
+		Hero:       "/static/pemprov-jabar/new-normal-persusuan-jawa-barat.webp",
+		HeroMobile: "/static/pemprov-jabar/new-normal-persusuan-jawa-barat-mobile.webp",
 	},
 	{
-		GroupSlug: "pemprov-jabar",
-		Name:      "Jabar Form",
-		Slug:      "jabar-form",
-		Division:  "PEMERINTAH PROVINSI JAWA BARAT",
+		GroupSlug:  "pemprov-jabar",
+		Name:       "Jabar Form",
+		Slug:       "jabar-form",
+		Division:   "PEMERINTAH PROVINSI JAWA BARAT",
+		Hero:       "/static/pemprov-jabar/jabar-form.webp",
+		HeroMobile: "/static/pemprov-jabar/jabar-form-mobile.webp",
 	},
 	{
-		GroupSlug: "bumn-bumd",
-		Name:      "Alat Pemecah Ombak (APO) Desa Mayangan Subang",
-		Slug:      "alat-pemecah-ombak-apo-desa-mayangan-subang",
-		Division:  "BUMN/BUMD",
+		GroupSlug:  "bumn-bumd",
+		Name:       "Alat Pemecah Ombak (APO) Desa Mayangan Subang",
+		Slug:       "alat-pemecah-ombak-apo-desa-mayangan-subang",
+		Division:   "BUMN/BUMD",
+		Hero:       "/static/bumn-bumd/6.webp",
+		HeroMobile: "/static/bumn-bumd/6-mobile.webp",
 	},
 	{
-		GroupSlug: "bumn-bumd",
-		Name:      "SIMOTIP",
-		Slug:      "simotip",
-		Division:  "BUMN/BUMD",
+		GroupSlug:  "bumn-bumd",
+		Name:       "SIMOTIP",
+		Slug:       "simotip",
+		Division:   "BUMN/BUMD",
+		Hero:       "/static/bumn-bumd/simotip.webp",
+		HeroMobile: "/static/bumn-bumd/simotip-mobile.webp",
 	},
 	{
-		GroupSlug: "bumn-bumd",
-		Name:      "Aplikasi Pemilu Elektronik (e-Voting)",
-		Slug:      "aplikasi-pemilu-elektronik-e-voting",
-		Division:  "BUMN/BUMD",
+		GroupSlug:  "bumn-bumd",
+		Name:       "Aplikasi Pemilu Elektronik (e-Voting)",
+		Slug:       "aplikasi-pemilu-elektronik-e-voting",
+		Division:   "BUMN/BUMD",
+		Hero:       "/static/bumn-bumd/evoting.webp",
+		HeroMobile: "/static/bumn-bumd/evoting-mobile.webp",
 	},
 	{
-		GroupSlug: "bumn-bumd",
-		Name:      "THR Asyik",
-		Slug:      "thr-asyik",
-		Division:  "BUMN/BUMD",
+		GroupSlug:  "bumn-bumd",
+		Name:       "THR Asyik",
+		Slug:       "thr-asyik",
+		Division:   "BUMN/BUMD",
+		Hero:       "/static/bumn-bumd/thr-asyik.webp",
+		HeroMobile: "/static/bumn-bumd/thr-asyik-mobile.webp",
 	},
 	{
-		GroupSlug: "kementrian-lembaga-pt",
-		Name:      "Instrumen Deteksi Risiko Stunting pada Remaja (Insting)",
-		Slug:      "instrumen-deteksi-risiko-stunting-pada-remaja-insting",
-		Division:  "KEMENTERIAN/LEMBAGA/PT",
+		GroupSlug:  "kementrian-lembaga-pt",
+		Name:       "Instrumen Deteksi Risiko Stunting pada Remaja (Insting)",
+		Slug:       "instrumen-deteksi-risiko-stunting-pada-remaja-insting",
+		Division:   "KEMENTERIAN/LEMBAGA/PT",
+		Hero:       "/static/kementrian-lembaga-pt/insting.webp",
+		HeroMobile: "/static/kementrian-lembaga-pt/insting-mobile.webp",
 	},
 	{
-		GroupSlug: "kementrian-lembaga-pt",
-		Name:      "Teknologi Hybrid Taman Sanitasi (HTS) untuk Pencegahan Pencemaran Lingkungan dan Daur Ulang Air",
-		Slug:      "teknologi-hybrid-taman-sanitasi-hts-untuk-pencegahan-pencemaran-lingkungan-dan-daur-ulang-air",
-		Division:  "KEMENTERIAN/LEMBAGA/PT",
+		GroupSlug:  "kementrian-lembaga-pt",
+		Name:       "Teknologi Hybrid Taman Sanitasi (HTS) untuk Pencegahan Pencemaran Lingkungan dan Daur Ulang Air",
+		Slug:       "teknologi-hybrid-taman-sanitasi-hts-untuk-pencegahan-pencemaran-lingkungan-dan-daur-ulang-air",
+		Division:   "KEMENTERIAN/LEMBAGA/PT",
+		Hero:       "/static/kementrian-lembaga-pt/hts.webp",
+		HeroMobile: "/static/kementrian-lembaga-pt/hts-mobile-1.webp",
 	},
 	{
-		GroupSlug: "kementrian-lembaga-pt",
-		Name:      "MENTARI (Mental Health Remaja Indonesia) Assessment",
-		Slug:      "mentari-mental-health-remaja-indonesia-assessment",
-		Division:  "KEMENTERIAN/LEMBAGA/PT",
+		GroupSlug:  "kementrian-lembaga-pt",
+		Name:       "MENTARI (Mental Health Remaja Indonesia) Assessment",
+		Slug:       "mentari-mental-health-remaja-indonesia-assessment",
+		Division:   "KEMENTERIAN/LEMBAGA/PT",
+		Hero:       "/static/kementrian-lembaga-pt/mentari-assesment.webp",
+		HeroMobile: "/static/kementrian-lembaga-pt/mentari-assesment.webp",
 	},
 	{
-		GroupSlug: "kementrian-lembaga-pt",
-		Name:      "ISOPA (Intelligent Solar Panel)",
-		Slug:      "isopa-intelligent-solar-panel",
-		Division:  "KEMENTERIAN/LEMBAGA/PT",
+		GroupSlug:  "kementrian-lembaga-pt",
+		Name:       "ISOPA (Intelligent Solar Panel)",
+		Slug:       "isopa-intelligent-solar-panel",
+		Division:   "KEMENTERIAN/LEMBAGA/PT",
+		Hero:       "/static/kementrian-lembaga-pt/isopa.webp",
+		HeroMobile: "/static/kementrian-lembaga-pt/isopa-mobile.webp",
 	},
 	{
-		GroupSlug: "kementrian-lembaga-pt",
-		Name:      "INOVASI SASCHIEVERSITY",
-		Slug:      "inovasi-saschieversity",
-		Division:  "KEMENTERIAN/LEMBAGA/PT",
+		GroupSlug:  "kementrian-lembaga-pt",
+		Name:       "INOVASI SASCHIEVERSITY",
+		Slug:       "inovasi-saschieversity",
+		Division:   "KEMENTERIAN/LEMBAGA/PT",
+		Hero:       "/static/kementrian-lembaga-pt/saschieversity.webp",
+		HeroMobile: "/static/kementrian-lembaga-pt/saschieversity-mobile.webp",
 	},
 	{
-		GroupSlug: "smp-sma-sederajat",
-		Name:      "TONNETAR (Tongkat Tunanetra Pintar)",
-		Slug:      "tonnetar-tongkat-tunanetra-pintar",
-		Division:  "SMP/SMA/SEDERAJAT",
+		GroupSlug:  "smp-sma-sederajat",
+		Name:       "TONNETAR (Tongkat Tunanetra Pintar)",
+		Slug:       "tonnetar-tongkat-tunanetra-pintar",
+		Division:   "SMP/SMA/SEDERAJAT",
+		Hero:       "/static/smp-sma/tonnetar.webp",
+		HeroMobile: "/static/smp-sma/tonnetar-mobile.webp",
 	},
 	{
-		GroupSlug: "smp-sma-sederajat",
-		Name:      "Motor Lstrik Dengan Teknologi Finger Print",
-		Slug:      "motor-lstrik-dengan-teknologi-finger-print",
-		Division:  "SMP/SMA/SEDERAJAT",
+		GroupSlug:  "smp-sma-sederajat",
+		Name:       "Motor Lstrik Dengan Teknologi Finger Print",
+		Slug:       "motor-lstrik-dengan-teknologi-finger-print",
+		Division:   "SMP/SMA/SEDERAJAT",
+		Hero:       "/static/smp-sma/motor-listrik-dengan-teknologi-finger-print.webp",
+		HeroMobile: "/static/smp-sma/motor-listrik-dengan-teknologi-finger-print-mobile.webp",
 	},
 	{
-		GroupSlug: "smp-sma-sederajat",
-		Name:      "SAMVING BLOCK (SAMPAH PLASTIK MENJADI PAVING BLOCK)",
-		Slug:      "samving-block-sampah-plastik-menjadi-paving-block",
-		Division:  "SMP/SMA/SEDERAJAT",
+		GroupSlug:  "smp-sma-sederajat",
+		Name:       "SAMVING BLOCK (SAMPAH PLASTIK MENJADI PAVING BLOCK)",
+		Slug:       "samving-block-sampah-plastik-menjadi-paving-block",
+		Division:   "SMP/SMA/SEDERAJAT",
+		Hero:       "/static/smp-sma/samving-block.webp",
+		HeroMobile: "/static/smp-sma/samving-block-mobile.webp",
 	},
 	{
-		GroupSlug: "smp-sma-sederajat",
-		Name:      "Penguatan Kompetensi LITNUM Melalui Lesson Study",
-		Slug:      "penguatan-kompetensi-litnum-melalui-lesson-study",
-		Division:  "SMP/SMA/SEDERAJAT",
+		GroupSlug:  "smp-sma-sederajat",
+		Name:       "Penguatan Kompetensi LITNUM Melalui Lesson Study",
+		Slug:       "penguatan-kompetensi-litnum-melalui-lesson-study",
+		Division:   "SMP/SMA/SEDERAJAT",
+		Hero:       "/static/smp-sma/litnum.webp",
+		HeroMobile: "/static/smp-sma/litnum-mobile.webp",
 	},
 	{
-		GroupSlug: "smp-sma-sederajat",
-		Name:      "INOVASI SABUN NANAS TSANAWIYAH SATU",
-		Slug:      "inovasi-sabun-nanas-tsanawiyah-satu",
-		Division:  "SMP/SMA/SEDERAJAT",
+		GroupSlug:  "smp-sma-sederajat",
+		Name:       "INOVASI SABUN NANAS TSANAWIYAH SATU",
+		Slug:       "inovasi-sabun-nanas-tsanawiyah-satu",
+		Division:   "SMP/SMA/SEDERAJAT",
+		Hero:       "/static/smp-sma/sanatsu.webp",
+		HeroMobile: "/static/smp-sma/sanatsu-mobile.webp",
 	},
 	{
-		GroupSlug: "pemda-kota",
-		Name:      "Konservasi Mata Air menjadi Ruang Terbuka Hijau/Ruang Publik",
-		Slug:      "konservasi-mata-air-menjadi-ruang-terbuka-hijau-ruang-publik",
-		Division:  "PEMERINTAH DAERAH KOTA",
+		GroupSlug:  "pemda-kota",
+		Name:       "Konservasi Mata Air menjadi Ruang Terbuka Hijau/Ruang Publik",
+		Slug:       "konservasi-mata-air-menjadi-ruang-terbuka-hijau-ruang-publik",
+		Division:   "PEMERINTAH DAERAH KOTA",
+		Hero:       "/static/pemkot/konversi-mata-air.webp",
+		HeroMobile: "/static/pemkot/konversi-mata-air-mobile.webp",
 	},
 	{
-		GroupSlug: "pemda-kota",
-		Name:      "Bogor Smart Health",
-		Slug:      "bogor-smart-health",
-		Division:  "PEMERINTAH DAERAH KOTA",
+		GroupSlug:  "pemda-kota",
+		Name:       "Bogor Smart Health",
+		Slug:       "bogor-smart-health",
+		Division:   "PEMERINTAH DAERAH KOTA",
+		Hero:       "/static/pemkot/bogor-smart-health.webp",
+		HeroMobile: "/static/pemkot/bogor-smart-health-mobile.webp",
 	},
 	{
-		GroupSlug: "pemda-kota",
-		Name:      "Gerakan Orang Cimahi Pilah Sampah (Grak Ompimpah)",
-		Slug:      "gerakan-orang-cimahi-pilah-sampah-grak-ompimpah",
-		Division:  "PEMERINTAH DAERAH KOTA",
+		GroupSlug:  "pemda-kota",
+		Name:       "Gerakan Orang Cimahi Pilah Sampah (Grak Ompimpah)",
+		Slug:       "gerakan-orang-cimahi-pilah-sampah-grak-ompimpah",
+		Division:   "PEMERINTAH DAERAH KOTA",
+		Hero:       "/static/pemkot/grak-ompimpah.webp",
+		HeroMobile: "/static/pemkot/grak-ompimpah-mobile.webp",
 	},
 	{
-		GroupSlug: "pemda-kota",
-		Name:      "BUNG SENJA (Tabungan Sedot Tinja )",
-		Slug:      "bung-senja-tabungan-sedot-tinja",
-		Division:  "PEMERINTAH DAERAH KOTA",
+		GroupSlug:  "pemda-kota",
+		Name:       "BUNG SENJA (Tabungan Sedot Tinja )",
+		Slug:       "bung-senja-tabungan-sedot-tinja",
+		Division:   "PEMERINTAH DAERAH KOTA",
+		Hero:       "/static/pemkot/buang-senja.webp",
+		HeroMobile: "/static/pemkot/buang-senja-mobile.webp",
 	},
 	{
-		GroupSlug: "pemda-kota",
-		Name:      "SMART - K (Sistem Manajemen Akuakultur, Rekayasa Teknologi dan Kemitraan)",
-		Slug:      "smart-k-sistem-manajemen-akuakultur-rekayasa-teknologi-dan-kemitraan",
-		Division:  "PEMERINTAH DAERAH KOTA",
+		GroupSlug:  "pemda-kota",
+		Name:       "SMART - K (Sistem Manajemen Akuakultur, Rekayasa Teknologi dan Kemitraan)",
+		Slug:       "smart-k-sistem-manajemen-akuakultur-rekayasa-teknologi-dan-kemitraan",
+		Division:   "PEMERINTAH DAERAH KOTA",
+		Hero:       "/static/pemkot/smart-k.webp",
+		HeroMobile: "/static/pemkot/smart-k-mobile.webp",
 	},
 	{
-		GroupSlug: "pemda-kabupaten",
-		Name:      "Si Pintar Online",
-		Slug:      "si-pintar-online",
-		Division:  "PEMERINTAH DAERAH KABUPATEN",
+		GroupSlug:  "pemda-kabupaten",
+		Name:       "Si Pintar Online",
+		Slug:       "si-pintar-online",
+		Division:   "PEMERINTAH DAERAH KABUPATEN",
+		Hero:       "/static/pemda-jabar/pintar-on-line.webp",
+		HeroMobile: "/static/pemda-jabar/pintar-on-line-mobile.webp",
 	},
 	{
-		GroupSlug: "pemda-kabupaten",
-		Name:      "EKONOMI BANGKIT HARAPAN TERBIT: SI DARA PUBER BUKA JALAN SEJAHTERA UNTUK 5.260 ORANG MISKIN DI KABUPATEN SUMEDANG (SISTEM PEMBERDAYAAN MASYARAKAT MISKIN DENGAN PENGEMBANGAN EKONOMI PRODUKTIF MELALUI KELOMPOK USAHA BERSAMA)",
-		Slug:      "ekonomi-bangit-harapan-terbit-si-dara-puber-buka-jalan-sejahtera-untuk-5-260-orang-miskin-di-kabupaten-sumedang-sistem-pemberdayaan-masyarakat-miskin-dengan-pengembangan-ekonomi-produktif-melalui-kelompok-usaha-bersama",
-		Division:  "PEMERINTAH DAERAH KABUPATEN",
+		GroupSlug:  "pemda-kabupaten",
+		Name:       "EKONOMI BANGKIT HARAPAN TERBIT: SI DARA PUBER BUKA JALAN SEJAHTERA UNTUK 5.260 ORANG MISKIN DI KABUPATEN SUMEDANG (SISTEM PEMBERDAYAAN MASYARAKAT MISKIN DENGAN PENGEMBANGAN EKONOMI PRODUKTIF MELALUI KELOMPOK USAHA BERSAMA)",
+		Slug:       "ekonomi-bangit-harapan-terbit-si-dara-puber-buka-jalan-sejahtera-untuk-5-260-orang-miskin-di-kabupaten-sumedang-sistem-pemberdayaan-masyarakat-miskin-dengan-pengembangan-ekonomi-produktif-melalui-kelompok-usaha-bersama",
+		Division:   "PEMERINTAH DAERAH KABUPATEN",
+		Hero:       "/static/pemda-jabar/sidara-puber.webp",
+		HeroMobile: "/static/pemda-jabar/sidara-puber-mobile.webp",
 	},
 	{
-		GroupSlug: "pemda-kabupaten",
-		Name:      "Sistem Informasi Manajemen Perlindungan Pertanian (SIMARLIN)",
-		Slug:      "sistem-informasi-manajemen-perlindungan-pertanian-simarlin",
-		Division:  "PEMERINTAH DAERAH KABUPATEN",
+		GroupSlug:  "pemda-kabupaten",
+		Name:       "Sistem Informasi Manajemen Perlindungan Pertanian (SIMARLIN)",
+		Slug:       "sistem-informasi-manajemen-perlindungan-pertanian-simarlin",
+		Division:   "PEMERINTAH DAERAH KABUPATEN",
+		Hero:       "/static/pemda-jabar/simarlin.webp",
+		HeroMobile: "/static/pemda-jabar/simarlin-mobile.webp",
 	},
 	{
-		GroupSlug: "pemda-kabupaten",
-		Name:      "KETUPAT LEBARAN (Kegunaan Kartu Kepatuhan Minum Tablet Tambah darah)",
-		Slug:      "ketupat-lebaran-kegunaan-kartu-kepatuhan-minum-tablet-tambah-darah",
-		Division:  "PEMERINTAH DAERAH KABUPATEN",
+		GroupSlug:  "pemda-kabupaten",
+		Name:       "KETUPAT LEBARAN (Kegunaan Kartu Kepatuhan Minum Tablet Tambah darah)",
+		Slug:       "ketupat-lebaran-kegunaan-kartu-kepatuhan-minum-tablet-tambah-darah",
+		Division:   "PEMERINTAH DAERAH KABUPATEN",
+		Hero:       "/static/pemda-jabar/ketupat-lebaran.webp",
+		HeroMobile: "/static/pemda-jabar/ketupat-lebaran-mobile.webp",
 	},
 	{
-		GroupSlug: "pemda-kabupaten",
-		Name:      "NGUPAHAN (NGABAGI, NGUBAH, NGURAI, SAMPAH PANGAN) DINAS KETAHANAN PANGAN KAB.BOGOR",
-		Slug:      "ngupahan-ngabagi-ngubah-ngurai-sampah-pangan-dinas-ketahanan-pangan-kab-bogor",
-		Division:  "PEMERINTAH DAERAH KABUPATEN",
+		GroupSlug:  "pemda-kabupaten",
+		Name:       "NGUPAHAN (NGABAGI, NGUBAH, NGURAI, SAMPAH PANGAN) DINAS KETAHANAN PANGAN KAB.BOGOR",
+		Slug:       "ngupahan-ngabagi-ngubah-ngurai-sampah-pangan-dinas-ketahanan-pangan-kab-bogor",
+		Division:   "PEMERINTAH DAERAH KABUPATEN",
+		Hero:       "/static/pemda-jabar/ngupahan.webp",
+		HeroMobile: "/static/pemda-jabar/ngupahan-mobile.webp",
 	},
 	{
-		GroupSlug: "pemda-kabupaten",
-		Name:      "NYAI (INDRAMAYU ARTIFICIAL INTELLIGENCE)",
-		Slug:      "nyai-indramayu-artificial-intelligence",
-		Division:  "PEMERINTAH DAERAH KABUPATEN",
+		GroupSlug:  "pemda-kabupaten",
+		Name:       "NYAI (INDRAMAYU ARTIFICIAL INTELLIGENCE)",
+		Slug:       "nyai-indramayu-artificial-intelligence",
+		Division:   "PEMERINTAH DAERAH KABUPATEN",
+		Hero:       "/static/pemda-jabar/nyai.webp",
+		HeroMobile: "/static/pemda-jabar/nyai.webp",
 	},
 }
 
-// SeedInnovations inserts innovation seed data into the database
+// SeedInnovations inserts group and innovation seed data into the default event
 func SeedInnovations(ctx context.Context, pool *pgxpool.Pool) error {
+	var eventID string
+	err := pool.QueryRow(ctx, `SELECT id FROM events WHERE is_default`).Scan(&eventID)
+	if err != nil {
+		return fmt.Errorf("failed to find default event: %w", err)
+	}
+
+	// Overwriting an existing row is recorded in audit_events with the old
+	// and new values; the CTEs all read the table as it was before the upsert
+	groupQuery := `
+		WITH old AS (
+			SELECT id, name, position FROM event_groups WHERE event_id = $1 AND slug = $2
+		), upsert AS (
+			INSERT INTO event_groups (event_id, slug, name, position)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (event_id, slug) DO UPDATE
+			SET name = EXCLUDED.name,
+			    position = EXCLUDED.position
+			RETURNING id, name, position
+		)
+		INSERT INTO audit_events (actor, action, target_type, target_id, before, after)
+		SELECT $5, $6, 'group', u.id::text, to_jsonb(o) - 'id', to_jsonb(u) - 'id'
+		FROM upsert u
+		JOIN old o ON o.id = u.id
+		WHERE (o.name, o.position) IS DISTINCT FROM (u.name, u.position)
+	`
+
+	for _, group := range GroupsData {
+		_, err := pool.Exec(ctx, groupQuery, eventID, group.Slug, group.Name, group.Position,
+			domain.AuditActorSeed, domain.AuditSeedOverwritten)
+		if err != nil {
+			return fmt.Errorf("failed to seed group %s: %w", group.Slug, err)
+		}
+	}
+
 	query := `
-		INSERT INTO innovations (group_slug, slug, name, division, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, NOW(), NOW())
-		ON CONFLICT (group_slug, slug) DO UPDATE
-		SET name = EXCLUDED.name,
-		    division = EXCLUDED.division,
-		    updated_at = NOW()
+		WITH old AS (
+			SELECT id, name, division, hero_url, hero_mobile_url
+			FROM innovations
+			WHERE event_id = $1 AND group_slug = $2 AND slug = $3
+		), upsert AS (
+			INSERT INTO innovations (event_id, group_slug, slug, name, division, hero_url, hero_mobile_url, position, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''),
+			        (SELECT COALESCE(MAX(position), 0) + 1 FROM innovations WHERE event_id = $1 AND group_slug = $2),
+			        NOW(), NOW())
+			ON CONFLICT (event_id, group_slug, slug) DO UPDATE
+			SET name = EXCLUDED.name,
+			    division = EXCLUDED.division,
+			    hero_url = EXCLUDED.hero_url,
+			    hero_mobile_url = EXCLUDED.hero_mobile_url,
+			    updated_at = NOW()
+			RETURNING id, name, division, hero_url, hero_mobile_url
+		)
+		INSERT INTO audit_events (actor, action, target_type, target_id, before, after)
+		SELECT $8, $9, 'innovation', u.id::text, to_jsonb(o) - 'id', to_jsonb(u) - 'id'
+		FROM upsert u
+		JOIN old o ON o.id = u.id
+		WHERE (o.name, o.division, o.hero_url, o.hero_mobile_url)
+		      IS DISTINCT FROM (u.name, u.division, u.hero_url, u.hero_mobile_url)
 	`
 
 	log.Println("Starting innovation seeding...")
 
 	for i, innovation := range InnovationsData {
 		_, err := pool.Exec(ctx, query,
+			eventID,
 			innovation.GroupSlug,
 			innovation.Slug,
 			innovation.Name,
 			innovation.Division,
+			innovation.Hero,
+			innovation.HeroMobile,
+			domain.AuditActorSeed,
+			domain.AuditSeedOverwritten,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to seed innovation %s: %w", innovation.Name, err)
@@ -229,7 +366,3 @@ func SeedInnovations(ctx context.Context, pool *pgxpool.Pool) error {
 	log.Printf("Successfully seeded %d innovations", len(InnovationsData))
 	return nil
 }
-
-
-
-
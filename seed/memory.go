@@ -0,0 +1,61 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+
+	"voteweb/internal/domain"
+	"voteweb/internal/repo"
+)
+
+// SeedMemory loads the group and innovation seed data into the default event
+// of an in-memory store and opens voting, for DEMO_MODE
+func SeedMemory(ctx context.Context, store *repo.MemoryRepository) error {
+	event, err := store.GetDefaultEvent(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to find default event: %w", err)
+	}
+
+	for _, group := range GroupsData {
+		err := store.AddGroup(ctx, &domain.Group{
+			EventID:  event.ID,
+			Slug:     group.Slug,
+			Name:     group.Name,
+			Position: group.Position,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to seed group %s: %w", group.Slug, err)
+		}
+	}
+
+	for _, data := range InnovationsData {
+		division := data.Division
+		err := store.CreateInnovation(ctx, &domain.Innovation{
+			EventID:       event.ID,
+			GroupSlug:     data.GroupSlug,
+			Slug:          data.Slug,
+			Name:          data.Name,
+			Division:      &division,
+			HeroURL:       nullIfEmpty(data.Hero),
+			HeroMobileURL: nullIfEmpty(data.HeroMobile),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to seed innovation %s: %w", data.Name, err)
+		}
+	}
+
+	window := event.Window
+	window.Paused = false
+	if err := store.UpdateEventWindow(ctx, event.ID, &window); err != nil {
+		return fmt.Errorf("failed to open voting: %w", err)
+	}
+	return nil
+}
+
+// nullIfEmpty stores empty strings as NULL, as the SQL seed does
+func nullIfEmpty(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
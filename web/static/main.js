@@ -6,6 +6,7 @@ document.addEventListener('DOMContentLoaded', function() {
     const alreadyVotedModal = document.getElementById('alreadyVotedModal');
     const errorModal = document.getElementById('errorModal');
     const errorMessage = document.getElementById('errorMessage');
+    const alreadyVotedMessage = document.getElementById('alreadyVotedMessage');
 
     if (!voteBtn) return;
 
@@ -27,7 +28,7 @@ document.addEventListener('DOMContentLoaded', function() {
             
             const subNotice = document.createElement('p');
             subNotice.className = 'vote-notice-sub';
-            subNotice.textContent = 'Sistem ini hanya mengizinkan 1 vote per pengguna';
+            subNotice.textContent = 'Sistem ini hanya mengizinkan ' + voteRule;
             subNotice.style.cssText = 'color: #6b7280; font-size: 0.75rem; margin-top: 0.25rem; text-align: center;';
             
             voteSection.appendChild(notice);
@@ -40,26 +41,37 @@ document.addEventListener('DOMContentLoaded', function() {
         addVoteNotice();
     }
 
-    voteBtn.addEventListener('click', async function() {
+    voteBtn.addEventListener('click', function() {
         // Confirm vote with copywriting
-        if (!confirm('Yakin ingin vote untuk inovasi ini?\n\n⚠️ PERHATIAN: Anda hanya bisa vote untuk 1 karya saja. Pastikan pilihan Anda sudah tepat!')) {
+        if (!confirm('Yakin ingin vote untuk inovasi ini?\n\n⚠️ PERHATIAN: Sistem ini hanya mengizinkan ' + voteRule + '. Pastikan pilihan Anda sudah tepat!')) {
             return;
         }
 
+        // Events with one-time codes verify the voter's contact first
+        if (typeof requiresVerification !== 'undefined' && requiresVerification && !voterVerified) {
+            openVerify();
+            return;
+        }
+
+        submitVote();
+    });
+
+    // solution answers a challenge from a previous attempt
+    async function submitVote(solution, originalText = voteBtn.innerHTML) {
         // Disable button and show loading state
         voteBtn.disabled = true;
         voteBtn.classList.add('loading');
-        const originalText = voteBtn.innerHTML;
         voteBtn.textContent = 'Memproses...';
 
         try {
-            const response = await fetch(`/api/vote/${groupSlug}/${slug}`, {
+            const response = await fetch(voteURL, {
                 method: 'POST',
                 headers: {
                     'Content-Type': 'application/json',
                     'X-CSRF-Token': csrfToken
                 },
-                credentials: 'same-origin'
+                credentials: 'same-origin',
+                body: JSON.stringify(solution || {})
             });
 
             const data = await response.json();
@@ -89,6 +101,11 @@ document.addEventListener('DOMContentLoaded', function() {
                     voteCountEl.textContent = data.vote_count;
                 }
 
+                // Server message names the innovation already voted for
+                if (data.message && alreadyVotedMessage) {
+                    alreadyVotedMessage.textContent = data.message;
+                }
+
                 alreadyVotedModal.showModal();
                 voteBtn.disabled = true;
                 voteBtn.textContent = '✓ Sudah Vote';
@@ -96,6 +113,20 @@ document.addEventListener('DOMContentLoaded', function() {
                 
                 // Add vote notice
                 addVoteNotice();
+            } else if (response.status === 401 && data.error === 'verification_required') {
+                // Verification expired or missing; ask again and retry
+                voteBtn.disabled = false;
+                voteBtn.innerHTML = originalText;
+                voterVerified = false;
+                openVerify();
+            } else if (response.status === 403 && data.challenge && !solution) {
+                // Suspicious requests solve a security challenge, then retry once
+                voteBtn.textContent = 'Memverifikasi...';
+                const answer = await solveChallenge(data.challenge);
+                await submitVote({
+                    challenge_token: data.challenge.token || '',
+                    challenge_answer: answer
+                }, originalText);
             } else {
                 // Other error
                 throw new Error(data.message || data.error || 'Terjadi kesalahan');
@@ -112,7 +143,122 @@ document.addEventListener('DOMContentLoaded', function() {
             errorMessage.textContent = error.message || 'Terjadi kesalahan. Silakan coba lagi.';
             errorModal.showModal();
         }
-    });
+    }
+
+    // Proof of work is solved here; CAPTCHAs need a page-provided
+    // window.solveVoteCaptcha(challenge) that shows the provider's widget and
+    // resolves to its response token
+    async function solveChallenge(challenge) {
+        if (challenge.kind === 'pow') {
+            return solveProofOfWork(challenge.token, challenge.difficulty);
+        }
+        if (challenge.kind === 'captcha' && typeof window.solveVoteCaptcha === 'function') {
+            return window.solveVoteCaptcha(challenge);
+        }
+        throw new Error('Verifikasi keamanan tidak tersedia. Silakan muat ulang halaman.');
+    }
+
+    // Find an answer whose SHA-256 with the token starts with difficulty zero bits
+    async function solveProofOfWork(token, difficulty) {
+        if (!window.crypto || !window.crypto.subtle) {
+            throw new Error('Browser Anda tidak mendukung verifikasi keamanan.');
+        }
+        const encoder = new TextEncoder();
+        for (let answer = 0; ; answer++) {
+            const digest = await crypto.subtle.digest('SHA-256', encoder.encode(token + ':' + answer));
+            if (leadingZeroBits(new Uint8Array(digest)) >= difficulty) {
+                return String(answer);
+            }
+        }
+    }
+
+    function leadingZeroBits(bytes) {
+        let bits = 0;
+        for (const b of bytes) {
+            if (b !== 0) {
+                return bits + Math.clz32(b) - 24;
+            }
+            bits += 8;
+        }
+        return bits;
+    }
+
+    // Voter verification: request a code for a contact, then confirm it
+    const verifyModal = document.getElementById('verifyModal');
+    const verifyContactForm = document.getElementById('verifyContactForm');
+    const verifyCodeForm = document.getElementById('verifyCodeForm');
+    const verifyContact = document.getElementById('verifyContact');
+    const verifyCode = document.getElementById('verifyCode');
+    const verifyDestination = document.getElementById('verifyDestination');
+    const verifyResend = document.getElementById('verifyResend');
+    const verifyError = document.getElementById('verifyError');
+
+    function openVerify() {
+        if (!verifyModal) return;
+        verifyError.hidden = true;
+        verifyContactForm.hidden = false;
+        verifyCodeForm.hidden = true;
+        verifyModal.showModal();
+        verifyContact.focus();
+    }
+
+    function showVerifyError(message) {
+        verifyError.textContent = message;
+        verifyError.hidden = false;
+    }
+
+    async function postVerify(path, body) {
+        const response = await fetch(verifyURL + path, {
+            method: 'POST',
+            headers: {
+                'Content-Type': 'application/json',
+                'X-CSRF-Token': csrfToken
+            },
+            credentials: 'same-origin',
+            body: JSON.stringify(body)
+        });
+        const data = await response.json().catch(() => ({}));
+        if (!response.ok) {
+            throw new Error(data.message || data.error || 'Terjadi kesalahan. Silakan coba lagi.');
+        }
+        return data;
+    }
+
+    async function requestCode() {
+        verifyError.hidden = true;
+        try {
+            const data = await postVerify('/request', { contact: verifyContact.value });
+            verifyDestination.textContent = data.destination;
+            verifyContactForm.hidden = true;
+            verifyCodeForm.hidden = false;
+            verifyCode.value = '';
+            verifyCode.focus();
+        } catch (error) {
+            showVerifyError(error.message);
+        }
+    }
+
+    if (verifyModal) {
+        verifyContactForm.addEventListener('submit', function(event) {
+            event.preventDefault();
+            requestCode();
+        });
+
+        verifyResend.addEventListener('click', requestCode);
+
+        verifyCodeForm.addEventListener('submit', async function(event) {
+            event.preventDefault();
+            verifyError.hidden = true;
+            try {
+                await postVerify('/confirm', { contact: verifyContact.value, code: verifyCode.value });
+                voterVerified = true;
+                verifyModal.close();
+                submitVote();
+            } catch (error) {
+                showVerifyError(error.message);
+            }
+        });
+    }
 });
 
 // Close modal function